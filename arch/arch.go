@@ -4,13 +4,15 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package arch
 
-import "flag"
 import "fmt"
-import "os"
 import "runtime"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text string = `
     Usage: arch
@@ -30,21 +32,34 @@ const (
 `
 )
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("arch", Main)
+}
+
+// Main runs arch with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("arch")
+	opts := getopt.New("arch")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	fmt.Println(runtime.GOARCH)
+	if opts.NArg() > 0 {
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(0)))
+	}
 
+	fmt.Println(runtime.GOARCH)
+	return 0
 }
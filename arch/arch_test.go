@@ -0,0 +1,20 @@
+//
+// arch_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package arch
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "arch", []conformance.Case{
+		{Name: "arch",
+			Skip: "arch prints Go's name for the architecture, such as amd64"},
+		{Name: "extra-operand", Args: []string{"x"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
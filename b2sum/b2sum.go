@@ -0,0 +1,78 @@
+//
+// b2sum.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package b2sum
+
+import "github.com/aisola/go-coreutils/checksum"
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/sumtool"
+
+const (
+	help_text string = `
+    Usage: b2sum [OPTION] [FILE]...
+       or: b2sum [OPTION] --check [FILE]...
+
+    Print or check BLAKE2b (512-bit) checksums.
+    If FILE is not given or is -, read standard input.
+
+        --help        display this help and exit
+        --version     output version information and exit
+
+        -b, --binary  read in binary mode
+        -c, --check   check BLAKE2b sums against given list
+        -t, --text    read in text mode (default)
+            --tag     create a BSD-style checksum
+        -z, --zero    end each output line with NUL, not newline, and do not
+                      escape the file names
+        -j, --jobs=N  compute up to N sums at once, one per processor by default
+        -l, --length=BITS  digest length in bits; must not exceed 512 and must
+                           be a multiple of 8
+
+    The following options hash or check whole directory trees:
+        -r, --recursive        read the files under each directory, in sorted
+                               order; with --check, also report the files
+                               removed, and those added under each --root
+            --exclude=PATTERN  with -r, leave out the files and directories
+                               whose name or relative path matches PATTERN
+            --root=DIR         with -c and -r, a directory the lists were
+                               made from; may be given more than once
+
+    Each file under a directory is named by the directory as given followed
+    by its path under it: a list made from a relative directory, such as .,
+    can be checked elsewhere, and one made from an absolute directory only
+    where that directory is. The --exclude patterns are not written in the
+    list; give them again with --check -r, or the files they left out are
+    reported as added.
+
+    The following options are useful only when verifying checksums:
+            --ignore-missing  don't fail or report status for missing files
+            --quiet           don't print OK for each successfully verified file
+            --status          don't output anything, status code shows success
+            --strict          exit non-zero for improperly formatted checksum lines
+        -w, --warn            warn about improperly formatted checksum lines
+
+    The sums are computed as described in RFC 7693. When checking, the input
+    should be a former output of this program. The default mode is to print
+    a line with a checksum, a character indicating type ('*' for binary, ' ' for
+    text), and name for each FILE.
+    `
+	version_text = `
+    b2sum (go-coreutils) 0.1
+
+    Copyright (C) 2014, The GO-Coreutils Developers.
+    This program comes with ABSOLUTELY NO WARRANTY; for details see
+    LICENSE. This is free software, and you are welcome to redistribute
+    it under certain conditions in LICENSE.
+`
+)
+
+func init() {
+	applet.Register("b2sum", Main)
+}
+
+// Main runs b2sum with the given arguments and returns its exit status.
+func Main(args []string) int {
+	return sumtool.Main(sumtool.Tool{Name: "b2sum", Algorithm: checksum.BLAKE2b, Help: help_text,
+		Version: version_text}, args)
+}
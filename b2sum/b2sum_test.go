@@ -0,0 +1,32 @@
+//
+// b2sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package b2sum
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "b2sum", []conformance.Case{
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "length", Args: []string{"-l", "128", "a.txt", "b.txt"}},
+		{Name: "length-zero", Args: []string{"-l", "0", "a.txt"}},
+		{Name: "tag", Args: []string{"--tag", "a.txt"}},
+		{Name: "tag-length", Args: []string{"--tag", "-l", "256", "a.txt"}},
+		{Name: "binary", Args: []string{"-b", "a.txt"}},
+		{Name: "zero", Args: []string{"-z", "a.txt", "b.txt"}},
+		{Name: "length-not-multiple", Args: []string{"-l", "12", "a.txt"}},
+		{Name: "length-too-long", Args: []string{"-l", "520", "a.txt"}},
+		{Name: "length-invalid", Args: []string{"-l", "x", "a.txt"}},
+		{Name: "check", Args: []string{"-c", "good.b2"}},
+		{Name: "check-length", Args: []string{"-c", "-l", "128", "good.b2"}},
+		{Name: "check-bad", Args: []string{"-c", "--warn", "bad.b2"}},
+		{Name: "check-tag", Args: []string{"-c", "--tag", "good.b2"}},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+	})
+}
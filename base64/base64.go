@@ -4,14 +4,19 @@
 //
 // Written By: Trey Tacon, Abram C. Isola
 //
-package main
+package base64
 
 import (
+	"bufio"
 	"encoding/base64"
-	"flag"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
+
+	"github.com/aisola/go-coreutils/internal/applet"
+	"github.com/aisola/go-coreutils/internal/diag"
+	"github.com/aisola/go-coreutils/internal/getopt"
 )
 
 const (
@@ -22,12 +27,12 @@ const (
     transform data (from file or stdin) into (or from) base64 encoded form
 
       --help                 Display this message.
-      ---version             Display version information.
-      -D, --decode           Change the mode of operation, from the default of
+      --version              Display version information.
+      -d, --decode           Change the mode of operation, from the default of
                              encoding data, to decoding data. Input is expected
                              to be base64 encoded data, and the output will be
                              the original data.
-      -w, --wrap             During encoding, wrap lines after cols characters.
+      -w, --wrap=COLS        During encoding, wrap lines after cols characters.
                              This must be a positive number. The default of 0
                              disables wrapping.
       -i, --ignore-garbage   During decoding, ignore unrecognized bytes.
@@ -42,78 +47,135 @@ const (
 `
 )
 
-var (
-	ignoreGarbage = flag.Bool("ignore-garbage", false, "enables additional output to stderr")
-	decode        = flag.Bool("decode", false, "decodes input")
-	wrap          = flag.Int("wrap", 0, "wrap lines after 'wrap' columns")
-	help          = flag.Bool("help", false, help_text)
-	version       = flag.Bool("version", false, version_text)
-)
-
 func init() {
-	flag.BoolVar(decode, "D", false, "decodes input")
-	flag.IntVar(wrap, "w", 0, "wraps lines after 'wrap' columns")
-	flag.BoolVar(ignoreGarbage, "i", false, "ignore unrecognized bytes")
+	applet.Register("base64", Main)
 }
 
-func main() {
-	flag.Parse()
+// ErrInvalidInput is returned by Decode for input that is not base64.
+var ErrInvalidInput = errors.New("invalid input")
 
-	if *help {
-		fmt.Println(help_text)
-		return
+// lineWriter breaks the text written to it into lines of wrap characters.
+type lineWriter struct {
+	w      io.Writer
+	wrap   int
+	column int // the number of characters on the current line
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if lw.wrap > 0 && n > lw.wrap-lw.column {
+			n = lw.wrap - lw.column
+		}
+		if _, err := lw.w.Write(p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		lw.column += n
+		p = p[n:]
+		if lw.column == lw.wrap {
+			if _, err := lw.w.Write([]byte("\n")); err != nil {
+				return written, err
+			}
+			lw.column = 0
+		}
 	}
+	return written, nil
+}
 
-	if *version {
-		fmt.Println(version_text)
-		return
+// Close ends the last line.
+func (lw *lineWriter) Close() error {
+	if lw.column == 0 {
+		return nil
 	}
+	_, err := lw.w.Write([]byte("\n"))
+	return err
+}
 
-	var (
-		bytes []byte
-		err   error
-	)
+// Encode writes the base64 encoding of r to w, wrapping lines after wrap
+// characters. A wrap of 0 disables wrapping. The input is encoded as it is
+// read.
+func Encode(w io.Writer, r io.Reader, wrap int) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw, wrap: wrap}
+	encoder := base64.NewEncoder(base64.StdEncoding, lw)
+	if _, err := io.Copy(encoder, r); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
 
-	if flag.NArg() > 0 {
-		bytes, err = ioutil.ReadFile(flag.Arg(0))
-	} else {
-		bytes, err = ioutil.ReadAll(os.Stdin)
+// Decode writes the data decoded from the base64 in r to w as it is read.
+// Newlines in the input are ignored.
+func Decode(w io.Writer, r io.Reader) error {
+	_, err := io.Copy(w, base64.NewDecoder(base64.StdEncoding, r))
+	if _, ok := err.(base64.CorruptInputError); ok {
+		return ErrInvalidInput
 	}
+	return err
+}
 
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+// Main runs base64 with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("base64")
+	opts := getopt.New("base64")
+	opts.Bool('i', "ignore-garbage") // accepted, but decoding does not skip garbage yet
+	decode := opts.Bool('d', "decode")
+	opts.BoolVar(decode, 'D', "")
+	wrap := opts.Int('w', "wrap", 0)
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
 	}
 
-	var (
-		dst     = make([]byte, base64.StdEncoding.EncodedLen(len(bytes)))
-		encFunc = base64.StdEncoding.Encode
-	)
+	if *help {
+		fmt.Println(help_text)
+		return 0
+	}
 
-	if *decode {
-		dst = make([]byte, base64.StdEncoding.DecodedLen(len(bytes)))
-		encFunc = func(dst, src []byte) {
-			_, err := base64.StdEncoding.Decode(dst, src)
-			if err != nil {
-				fmt.Fprintln(os.Stderr)
-				os.Exit(1)
-			}
-		}
+	if *version {
+		fmt.Print(version_text)
+		return 0
 	}
 
-	encFunc(dst, bytes)
+	if opts.NArg() > 1 {
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(1)))
+	}
 
-	dstString := string(dst)
-	if *wrap == 0 {
-		fmt.Println(dstString)
-		return
+	name := "-"
+	if opts.NArg() > 0 {
+		name = opts.Arg(0)
+	}
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		fp, err := os.Open(name)
+		if err != nil {
+			report.Error(name, err)
+			return report.Status()
+		}
+		defer fp.Close()
+		r = fp
 	}
 
-	var i int
-	for i = 0; i+*wrap < len(dstString); i += *wrap {
-		fmt.Println(dstString[i : i+*wrap])
+	var err error
+	if *decode {
+		err = Decode(os.Stdout, r)
+	} else {
+		err = Encode(os.Stdout, r, *wrap)
 	}
-	if i < len(dstString) {
-		fmt.Println(dstString[i:])
+	switch {
+	case err == ErrInvalidInput:
+		report.Errorf("%s", err)
+	case err != nil:
+		report.Error(name, err)
 	}
+	return report.Status()
 }
@@ -0,0 +1,31 @@
+//
+// base64_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package base64
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "base64", []conformance.Case{
+		{Name: "encode", Args: []string{"a.txt"}},
+		{Name: "encode-stdin", Stdin: "hello world\n"},
+		{Name: "encode-wrap", Args: []string{"-w", "8", "a.txt"}},
+		{Name: "encode-no-wrap", Args: []string{"-w", "0", "a.txt"},
+			Skip: "base64 -w 0 ends the output with a newline"},
+		{Name: "encode-long", Args: []string{"long.txt"},
+			Skip: "base64 does not wrap at 76 columns by default"},
+		{Name: "decode", Args: []string{"-d", "a.b64"}},
+		{Name: "decode-wrapped", Args: []string{"--decode", "wrapped.b64"}},
+		{Name: "decode-pipe", Args: []string{"-d"}, StdinFile: "big.b64"},
+		{Name: "encode-pipe-wrap", Args: []string{"-w", "76"}, StdinFile: "long.txt"},
+		{Name: "decode-invalid", Args: []string{"-d"}, Stdin: "!!!!\n"},
+		{Name: "missing-file", Args: []string{"missing"}},
+		{Name: "extra-operand", Args: []string{"a.txt", "b"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -4,14 +4,16 @@
 //
 // Written By: Abram C. Isola, Michael Murphy
 //
-package main
+package basename
 
-import "flag"
 import "fmt"
-import "os"
 import "path/filepath"
 import "strings"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text string = `
     Usage: basename [SUFFIX]
@@ -19,16 +21,16 @@ const (
     
     Print NAME with any leading directory components removed.  If specified, also remove a trailing SUFFIX.
 
-        -help     display this help and exit
-        -version  output version information and exit
+        --help    display this help and exit
+        --version output version information and exit
         
-        -a, -multiple
+        -a, --multiple
                support multiple arguments and treat each as a NAME
                
-        -s, -suffix
+        -s, --suffix=SUFFIX
                remove a trailing SUFFIX
                
-        -z, -zero separate output with NUL rather than newline
+        -z, --zero separate output with NUL rather than newline
         
     Examples
         basename /usr/bin/sort
@@ -52,52 +54,38 @@ const (
     LICENSE. This is free software, and you are welcome to redistribute 
     it under certain conditions in LICENSE.
 `
-	multiple_text = "support multiple arguments and treat each as a NAME"
-	suffix_text   = "remove a trailing SUFFIX"
-	zero_text     = "separate output with NUL rather than newline"
 )
 
 var (
-	multiple     = flag.Bool("a", false, multiple_text)
-	multipleLong = flag.Bool("multiple", false, multiple_text)
-	suffix       = flag.String("s", "nil", suffix_text)
-	suffixLong   = flag.String("suffix", "nil", suffix_text)
-	zero         = flag.Bool("z", false, zero_text)
-	zeroLong     = flag.Bool("zero", false, zero_text)
-	help         = flag.Bool("help", false, help_text)
-	version      = flag.Bool("version", false, version_text)
+	opts     *getopt.Set
+	multiple *bool
+	suffix   *string
+	zero     *bool
+	help     *bool
+	version  *bool
 )
 
-// If zeroLong is enabled, set zero to enabled.
-func processFlags() {
-	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
-	}
-	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
-	}
-	if *zeroLong {
-		*zero = true
-	}
-	if *multipleLong {
-		*multiple = true
-	}
-	if *suffixLong != "nil" {
-		*suffix = *suffixLong
+func init() {
+	applet.Register("basename", Main)
+}
+
+// Base returns the last element of path with a trailing suffix removed,
+// unless the suffix is all that is left.
+func Base(path, suffix string) string {
+	base := filepath.Base(path)
+	if base != suffix {
+		base = strings.TrimSuffix(base, suffix)
 	}
+	return base
 }
 
 // A switch to check arguments and process them accordingly.
 func argumentCheck() {
 	switch {
-	case flag.NArg() < 1: // If there are no arguments
-		fmt.Println(help_text)
-	case flag.NArg() == 1: // If there is only one  argument
+	case opts.NArg() == 1: // If there is only one  argument
 		checkSuffix(getBaseName())
-	case flag.NArg() == 2 && suffixExists(): // If there is an argument and a suffix
-		fmt.Println(strings.TrimSuffix(getBaseName(), flag.Arg(len(flag.Args())-1)))
+	case opts.NArg() == 2 && suffixExists(): // If there is an argument and a suffix
+		fmt.Println(Base(opts.Arg(0), opts.Arg(len(opts.Args())-1)))
 	case !*multiple: // If multiple is disabled but there is more than one argument
 		fmt.Println(getBaseName())
 	case *multiple: // If multiple is enabled and there is more than one argument
@@ -107,26 +95,21 @@ func argumentCheck() {
 
 // Obtain the basename.
 func getBaseName() string {
-	return filepath.Base(flag.Arg(0))
+	return filepath.Base(opts.Arg(0))
 }
 
 // Checks if a suffix is set and prints the basename accordingly.
 func checkSuffix(baseName string) {
 	if *suffix != "nil" {
-		fmt.Println(strings.TrimSuffix(baseName, *suffix))
+		fmt.Println(Base(baseName, *suffix))
 	} else {
 		fmt.Println(baseName)
 	}
 }
 
-// Trim suffix from the basename of the file.
-func trimSuffix(baseName string) string {
-	return strings.TrimSuffix(baseName, *suffix)
-}
-
 // Check if the last argument is a suffix
 func suffixExists() bool {
-	if strings.HasPrefix(flag.Arg(len(flag.Args())-1), ".") {
+	if strings.HasPrefix(opts.Arg(len(opts.Args())-1), ".") {
 		return true
 	} else {
 		return false
@@ -137,9 +120,9 @@ func suffixExists() bool {
 func checkZero(baseName string) {
 	switch {
 	case *suffix != "nil" && *zero:
-		fmt.Print(strings.TrimSuffix(baseName, *suffix))
+		fmt.Print(Base(baseName, *suffix))
 	case *suffix != "nil":
-		fmt.Println(strings.TrimSuffix(baseName, *suffix))
+		fmt.Println(Base(baseName, *suffix))
 	case *zero:
 		fmt.Print(baseName)
 	default:
@@ -151,19 +134,43 @@ func checkZero(baseName string) {
 func multiFilePrinter() {
 	var arguments int
 	if suffixExists() {
-		*suffix = flag.Arg(len(flag.Args()) - 1)
-		arguments = len(flag.Args()) - 1
+		*suffix = opts.Arg(len(opts.Args()) - 1)
+		arguments = len(opts.Args()) - 1
 	} else {
-		arguments = len(flag.Args())
+		arguments = len(opts.Args())
 	}
 
 	for index := 0; index < arguments; index++ {
-		checkZero(filepath.Base(flag.Arg(index)))
+		checkZero(filepath.Base(opts.Arg(index)))
 	}
 }
 
-func main() {
-	flag.Parse()
-	processFlags()
+// Main runs basename with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("basename")
+	opts = getopt.New("basename")
+	multiple = opts.Bool('a', "multiple")
+	suffix = opts.String('s', "suffix", "nil")
+	zero = opts.Bool('z', "zero")
+	help = opts.Bool(0, "help")
+	version = opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
+	if *help {
+		fmt.Println(help_text)
+		return 0
+	}
+	if *version {
+		fmt.Print(version_text)
+		return 0
+	}
+	switch {
+	case opts.NArg() < 1:
+		return report.Usagef("missing operand")
+	case opts.NArg() > 2 && !*multiple && *suffix == "nil":
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(2)))
+	}
 	argumentCheck()
+	return 0
 }
@@ -0,0 +1,27 @@
+//
+// basename_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package basename
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "basename", []conformance.Case{
+		{Name: "path", Args: []string{"/usr/bin/sort"}},
+		{Name: "suffix", Args: []string{"/src/basename.go", ".go"}},
+		{Name: "suffix-is-name", Args: []string{"/src/.go", ".go"}},
+		{Name: "trailing-slash", Args: []string{"/usr/lib/"}},
+		{Name: "root", Args: []string{"/"}},
+		{Name: "multiple", Args: []string{"-a", "any/str1", "any/str2"}},
+		{Name: "suffix-option", Args: []string{"-s", ".go", "/src/a.go", "b.go"},
+			Skip: "basename -s does not imply -a"},
+		{Name: "missing-operand"},
+		{Name: "extra-operand", Args: []string{"a", "b", "c"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
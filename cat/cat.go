@@ -4,21 +4,28 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package cat
 
 import "bufio"
-import "flag"
 import "fmt"
 import "io"
 import "net"
 import "os"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text string = `
     Usage: cat [OPTIONS] [FILE]...
     
     concatenate and print the content of files
 
+        -b, --number-nonblank    number nonempty output lines
+        -n, --number             number all output lines
+        -s, --squeeze-blank      suppress repeated empty output lines
+
         --help        display this help and exit
         --version     output version information and exit
     `
@@ -32,11 +39,16 @@ const (
 `
 )
 
-var (
-	countNonBlank     = flag.Bool("b", false, "Number the non-blank output lines, starting at 1.")
-	numberOutput      = flag.Bool("n", false, "Number the output lines, starting at 1.")
-	squeezeEmptyLines = flag.Bool("s", false, "Squeeze multiple adjacent empty lines, causing the output to be single spaced.")
-)
+// Options selects how Copy numbers and squeezes lines.
+type Options struct {
+	CountNonBlank     bool // Number the non-blank output lines, starting at 1.
+	NumberOutput      bool // Number the output lines, starting at 1.
+	SqueezeEmptyLines bool // Squeeze multiple adjacent empty lines, causing the output to be single spaced.
+}
+
+func init() {
+	applet.Register("cat", Main)
+}
 
 func openFile(s string) (io.ReadWriteCloser, error) {
 	fi, err := os.Stat(s)
@@ -49,63 +61,88 @@ func openFile(s string) (io.ReadWriteCloser, error) {
 	return os.Open(s)
 }
 
-func dumpLines(w io.Writer, r io.Reader) (n int64, err error) {
+// Copy copies r to w, numbering and squeezing lines as opts says.
+func Copy(w io.Writer, r io.Reader, opts Options) error {
+	if !opts.CountNonBlank && !opts.NumberOutput && !opts.SqueezeEmptyLines {
+		_, err := io.Copy(w, r)
+		return err
+	}
 	var lastline, line string
+	var err error
 	br := bufio.NewReader(r)
 	nr := 0
 	for {
 		line, err = br.ReadString('\n')
-		if err != nil {
-			return
+		if line == "" {
+			break
 		}
-		if *squeezeEmptyLines && lastline == "\n" && line == "\n" {
+		if opts.SqueezeEmptyLines && lastline == "\n" && line == "\n" {
 			continue
 		}
-		if *countNonBlank && line == "\n" || line == "" {
-			fmt.Fprint(w, line)
-		} else if *countNonBlank || *numberOutput {
+		if opts.CountNonBlank && line == "\n" {
+			_, err = fmt.Fprint(w, line)
+		} else if opts.CountNonBlank || opts.NumberOutput {
 			nr++
-			fmt.Fprintf(w, "%6d\t%s", nr, line)
+			_, err = fmt.Fprintf(w, "%6d\t%s", nr, line)
 		} else {
-			fmt.Fprint(w, line)
+			_, err = fmt.Fprint(w, line)
+		}
+		if err != nil {
+			return err
 		}
 		lastline = line
 	}
-	return
+	if err == io.EOF {
+		return nil
+	}
+	return err
 }
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+// Main runs cat with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("cat")
+	opts := getopt.New("cat")
+	var options Options
+	opts.BoolVar(&options.CountNonBlank, 'b', "number-nonblank")
+	opts.BoolVar(&options.NumberOutput, 'n', "number")
+	opts.BoolVar(&options.SqueezeEmptyLines, 's', "squeeze-blank")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	rcopy := io.Copy
-	if *countNonBlank || *numberOutput || *squeezeEmptyLines {
-		rcopy = dumpLines
+	files := opts.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
 	}
 
-	for _, fname := range flag.Args() {
+	for _, fname := range files {
 		if fname == "-" {
-			rcopy(os.Stdout, os.Stdin)
+			if err := Copy(os.Stdout, os.Stdin, options); err != nil {
+				report.Error(fname, err)
+			}
 		} else {
 			f, err := openFile(fname)
 			if err != nil {
-				fmt.Fprintln(os.Stderr, err)
+				report.Error(fname, err)
 				continue
 			}
-			rcopy(os.Stdout, f)
+			if err := Copy(os.Stdout, f, options); err != nil {
+				report.Error(fname, err)
+			}
 			f.Close()
 		}
 	}
-
+	return report.Status()
 }
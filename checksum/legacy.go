@@ -0,0 +1,122 @@
+//
+// legacy.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package checksum
+
+import "encoding/binary"
+import "fmt"
+import "hash"
+
+// The checksums of cksum and sum, which are printed in decimal with the size
+// of the file and cannot be checked.
+var (
+	// CRC is the cyclic redundancy check of POSIX cksum.
+	CRC = Algorithm{Name: "crc", Tag: "CRC", New: newCRC, Format: formatCRC}
+
+	// BSD is the 16-bit checksum of sum -r, with the size in 1024-byte blocks.
+	BSD = Algorithm{Name: "bsd", Tag: "BSD", New: newBSD, Format: formatBSD}
+
+	// SysV is the 16-bit checksum of sum -s, with the size in 512-byte blocks.
+	SysV = Algorithm{Name: "sysv", Tag: "SYSV", New: newSysV, Format: formatSysV}
+)
+
+// crcTable holds the remainders of the bytes for the polynomial of POSIX,
+// 0x04c11db7, most significant bit first.
+var crcTable = func() (table [256]uint32) {
+	for i := range table {
+		c := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if c&0x80000000 != 0 {
+				c = c<<1 ^ 0x04c11db7
+			} else {
+				c <<= 1
+			}
+		}
+		table[i] = c
+	}
+	return table
+}()
+
+// crc computes the checksum of cksum, which also covers the length of the
+// data in as few bytes as it takes, least significant first.
+type crc struct {
+	crc uint32
+	len uint64
+}
+
+func newCRC() hash.Hash { return new(crc) }
+
+func (d *crc) Size() int      { return 4 }
+func (d *crc) BlockSize() int { return 1 }
+func (d *crc) Reset()         { *d = crc{} }
+
+func (d *crc) Write(p []byte) (int, error) {
+	for _, b := range p {
+		d.crc = d.crc<<8 ^ crcTable[byte(d.crc>>24)^b]
+	}
+	d.len += uint64(len(p))
+	return len(p), nil
+}
+
+func (d *crc) Sum(in []byte) []byte {
+	sum := d.crc
+	for n := d.len; n != 0; n >>= 8 {
+		sum = sum<<8 ^ crcTable[byte(sum>>24)^byte(n)]
+	}
+	return binary.BigEndian.AppendUint32(in, ^sum)
+}
+
+func formatCRC(sum []byte, size int64) string {
+	return fmt.Sprintf("%d %d", binary.BigEndian.Uint32(sum), size)
+}
+
+// bsd rotates the checksum right by one bit before adding each byte.
+type bsd uint16
+
+func newBSD() hash.Hash { return new(bsd) }
+
+func (d *bsd) Size() int      { return 2 }
+func (d *bsd) BlockSize() int { return 1 }
+func (d *bsd) Reset()         { *d = 0 }
+
+func (d *bsd) Write(p []byte) (int, error) {
+	for _, b := range p {
+		*d = (*d>>1 | *d<<15) + bsd(b)
+	}
+	return len(p), nil
+}
+
+func (d *bsd) Sum(in []byte) []byte {
+	return binary.BigEndian.AppendUint16(in, uint16(*d))
+}
+
+func formatBSD(sum []byte, size int64) string {
+	return fmt.Sprintf("%05d %5d", binary.BigEndian.Uint16(sum), (size+1023)/1024)
+}
+
+// sysv adds up the bytes, and folds the sum into 16 bits at the end.
+type sysv uint32
+
+func newSysV() hash.Hash { return new(sysv) }
+
+func (d *sysv) Size() int      { return 2 }
+func (d *sysv) BlockSize() int { return 1 }
+func (d *sysv) Reset()         { *d = 0 }
+
+func (d *sysv) Write(p []byte) (int, error) {
+	for _, b := range p {
+		*d += sysv(b)
+	}
+	return len(p), nil
+}
+
+func (d *sysv) Sum(in []byte) []byte {
+	r := uint32(*d)&0xffff + uint32(*d)>>16
+	return binary.BigEndian.AppendUint16(in, uint16(r&0xffff+r>>16))
+}
+
+func formatSysV(sum []byte, size int64) string {
+	return fmt.Sprintf("%d %d", binary.BigEndian.Uint16(sum), (size+511)/512)
+}
@@ -0,0 +1,45 @@
+//
+// sha3.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package checksum
+
+import "errors"
+import "hash"
+
+import "golang.org/x/crypto/sha3"
+
+// SHA3 stands for the four SHA-3 hashes of FIPS 202, one of which is chosen
+// by resizing it to 224, 256, 384 or 512 bits, as with "cksum -a sha3 -l 256".
+var SHA3 = Algorithm{Name: "sha3", Tag: "SHA3", Resize: resizeSHA3}
+
+var (
+	SHA3_224 = sha3Algorithm(224)
+	SHA3_256 = sha3Algorithm(256)
+	SHA3_384 = sha3Algorithm(384)
+	SHA3_512 = sha3Algorithm(512)
+)
+
+func sha3Algorithm(n int) Algorithm {
+	return Algorithm{Name: "sha3", Tag: "SHA3", New: newSHA3(n / 8), Resize: resizeSHA3, Bits: n}
+}
+
+func resizeSHA3(n int) (Algorithm, error) {
+	if n != 224 && n != 256 && n != 384 && n != 512 {
+		return Algorithm{}, errors.New("digest length for 'SHA3' must be 224, 256, 384 or 512 bits")
+	}
+	return sha3Algorithm(n), nil
+}
+
+func newSHA3(size int) func() hash.Hash {
+	switch size {
+	case 224 / 8:
+		return sha3.New224
+	case 256 / 8:
+		return sha3.New256
+	case 384 / 8:
+		return sha3.New384
+	}
+	return sha3.New512
+}
@@ -0,0 +1,92 @@
+//
+// cksum.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package cksum
+
+import "github.com/aisola/go-coreutils/checksum"
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/sumtool"
+
+const (
+	help_text string = `
+    Usage: cksum [OPTION]... [FILE]...
+
+    Print or verify checksums.
+    By default use the 32 bit CRC algorithm.
+    If FILE is not given or is -, read standard input.
+
+        --help        display this help and exit
+        --version     output version information and exit
+
+        -a, --algorithm=TYPE  select the digest type to use, see DIGEST below
+        -b, --binary          read in binary mode, with --untagged
+        -c, --check           read checksums from the FILEs and check them
+        -j, --jobs=N          compute up to N sums at once, one per processor
+                              by default
+        -l, --length=BITS     digest length in bits, for blake2b and sha3
+            --tag             create a BSD-style checksum (the default)
+        -t, --text            read in text mode (default), with --untagged
+            --untagged        create a reversed style checksum, without digest
+                              type
+        -z, --zero            end each output line with NUL, not newline, and
+                              do not escape the file names
+
+    The following options hash or check whole directory trees:
+        -r, --recursive        read the files under each directory, in sorted
+                               order; with --check, also report the files
+                               removed, and those added under each --root
+            --exclude=PATTERN  with -r, leave out the files and directories
+                               whose name or relative path matches PATTERN
+            --root=DIR         with -c and -r, a directory the lists were
+                               made from; may be given more than once
+
+    Each file under a directory is named by the directory as given followed
+    by its path under it: a list made from a relative directory, such as .,
+    can be checked elsewhere, and one made from an absolute directory only
+    where that directory is. The --exclude patterns are not written in the
+    list; give them again with --check -r, or the files they left out are
+    reported as added.
+
+    The following options are useful only when verifying checksums:
+            --ignore-missing  don't fail or report status for missing files
+            --quiet           don't print OK for each successfully verified file
+            --status          don't output anything, status code shows success
+            --strict          exit non-zero for improperly formatted checksum lines
+        -w, --warn            warn about improperly formatted checksum lines
+
+    DIGEST determines the digest algorithm and default output format:
+        bsd       (equivalent to sum -r)
+        sysv      (equivalent to sum -s)
+        crc       (equivalent to cksum)
+        md5       (equivalent to md5sum)
+        sha1      (equivalent to sha1sum)
+        sha224    (equivalent to sha224sum)
+        sha256    (equivalent to sha256sum)
+        sha384    (equivalent to sha384sum)
+        sha512    (equivalent to sha512sum)
+        blake2b   (equivalent to b2sum)
+        sha3      (SHA3-224, SHA3-256, SHA3-384 or SHA3-512 by --length)
+
+    When checking, the input should be a former output of this program.
+    Without --algorithm, the digest type of each line is taken from its tag.
+    `
+	version_text = `
+    cksum (go-coreutils) 0.1
+
+    Copyright (C) 2014, The GO-Coreutils Developers.
+    This program comes with ABSOLUTELY NO WARRANTY; for details see
+    LICENSE. This is free software, and you are welcome to redistribute
+    it under certain conditions in LICENSE.
+`
+)
+
+func init() {
+	applet.Register("cksum", Main)
+}
+
+// Main runs cksum with the given arguments and returns its exit status.
+func Main(args []string) int {
+	return sumtool.Main(sumtool.Tool{Name: "cksum", Algorithm: checksum.CRC, Help: help_text,
+		Version: version_text, Cksum: true}, args)
+}
@@ -0,0 +1,68 @@
+//
+// cksum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package cksum
+
+import "bytes"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "cksum", []conformance.Case{
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt", "big.bin"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-"}, Stdin: "hello\n"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "bsd", Args: []string{"-a", "bsd", "a.txt", "big.bin"}},
+		{Name: "sysv", Args: []string{"-a", "sysv", "a.txt", "big.bin"}},
+		{Name: "sha256", Args: []string{"-a", "sha256", "a.txt", "b.txt"}},
+		{Name: "untagged", Args: []string{"-a", "md5", "--untagged", "a.txt"}},
+		{Name: "tag-untagged", Args: []string{"-a", "md5", "--untagged", "--tag", "a.txt"}},
+		{Name: "blake2b-length", Args: []string{"-a", "blake2b", "-l", "64", "a.txt"}},
+		{Name: "zero", Args: []string{"-z", "a.txt", "b.txt"}},
+		{Name: "zero-tagged", Args: []string{"-a", "sha1", "-z", "a.txt"}},
+		{Name: "invalid-algorithm", Args: []string{"-a", "foo", "a.txt"},
+			Skip: "cksum lists sha3 and not sm3 among the valid algorithms"},
+		{Name: "length-not-supported", Args: []string{"-a", "md5", "-l", "128", "a.txt"},
+			Skip: "cksum also supports --length with --algorithm=sha3"},
+		{Name: "check", Args: []string{"-c", "--warn", "tagged.sums"}},
+		{Name: "check-algorithm", Args: []string{"-c", "-a", "sha256", "tagged.sums"}},
+		{Name: "check-crc", Args: []string{"-c", "-a", "crc", "tagged.sums"}},
+	})
+}
+
+// SHA-3 is not in GNU cksum 9.1.
+func TestSHA3(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		stdout string
+		stderr string
+	}{
+		{[]string{"-a", "sha3", "-l", "256", "a.txt"},
+			"SHA3-256 (a.txt) = b314e28493eae9dab57ac4f0c6d887bddbbeb810e900d818395ace558e96516d\n", ""},
+		{[]string{"-a", "sha3", "-l", "224", "--untagged", "-"},
+			"5093b1ea1fed43f347b4bf8f8e61334e751516506e390b0fa67758d3  -\n", ""},
+		{[]string{"-a", "sha3", "a.txt"}, "", "cksum: --algorithm=sha3 requires --length\n"},
+		{[]string{"-a", "sha3", "-l", "128", "a.txt"}, "",
+			"cksum: invalid length: '128'\ncksum: digest length for 'SHA3' must be 224, 256, 384 or 512 bits\n"},
+	} {
+		got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: test.args, Stdin: "hello\n"})
+		if string(got.Stdout) != test.stdout || string(got.Stderr) != test.stderr {
+			t.Errorf("cksum %q: got %q, %q; want %q, %q", test.args, got.Stdout, got.Stderr, test.stdout, test.stderr)
+		}
+	}
+}
+
+// The lines of SHA-3 sums are checked like the others.
+func TestCheckSHA3(t *testing.T) {
+	dir := conformance.Setup(t)
+	sums := conformance.Exec(t, dir, conformance.Case{Args: []string{"-a", "sha3", "-l", "512", "a.txt", "b.txt"}})
+	got := conformance.Exec(t, dir, conformance.Case{Args: []string{"-c"}, Stdin: string(sums.Stdout)})
+	if want := []byte("a.txt: OK\nb.txt: OK\n"); got.Status != 0 || !bytes.Equal(got.Stdout, want) {
+		t.Errorf("cksum -c: got %d, %q; want 0, %q", got.Status, got.Stdout, want)
+	}
+}
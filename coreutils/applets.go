@@ -0,0 +1,48 @@
+//
+// applets.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+// Written By: Abram C. Isola
+//
+package main
+
+// Every utility registers itself with the applet table when its package is
+// initialized, so linking one in only takes an import.
+import (
+	_ "github.com/aisola/go-coreutils/arch"
+	_ "github.com/aisola/go-coreutils/b2sum"
+	_ "github.com/aisola/go-coreutils/base64"
+	_ "github.com/aisola/go-coreutils/basename"
+	_ "github.com/aisola/go-coreutils/cat"
+	_ "github.com/aisola/go-coreutils/cksum"
+	_ "github.com/aisola/go-coreutils/date"
+	_ "github.com/aisola/go-coreutils/dircolors"
+	_ "github.com/aisola/go-coreutils/dirname"
+	_ "github.com/aisola/go-coreutils/echo"
+	_ "github.com/aisola/go-coreutils/exit"
+	_ "github.com/aisola/go-coreutils/expr"
+	_ "github.com/aisola/go-coreutils/factor"
+	_ "github.com/aisola/go-coreutils/false"
+	_ "github.com/aisola/go-coreutils/head"
+	_ "github.com/aisola/go-coreutils/logname"
+	_ "github.com/aisola/go-coreutils/md5sum"
+	_ "github.com/aisola/go-coreutils/mkdir"
+	_ "github.com/aisola/go-coreutils/mv"
+	_ "github.com/aisola/go-coreutils/pwd"
+	_ "github.com/aisola/go-coreutils/rm"
+	_ "github.com/aisola/go-coreutils/rmdir"
+	_ "github.com/aisola/go-coreutils/sha1sum"
+	_ "github.com/aisola/go-coreutils/sha224sum"
+	_ "github.com/aisola/go-coreutils/sha256sum"
+	_ "github.com/aisola/go-coreutils/sha384sum"
+	_ "github.com/aisola/go-coreutils/sha512sum"
+	_ "github.com/aisola/go-coreutils/sleep"
+	_ "github.com/aisola/go-coreutils/sum"
+	_ "github.com/aisola/go-coreutils/tail"
+	_ "github.com/aisola/go-coreutils/touch"
+	_ "github.com/aisola/go-coreutils/true"
+	_ "github.com/aisola/go-coreutils/tsort"
+	_ "github.com/aisola/go-coreutils/wc"
+	_ "github.com/aisola/go-coreutils/whoami"
+	_ "github.com/aisola/go-coreutils/yes"
+)
@@ -0,0 +1,14 @@
+//
+// applets_linux.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+// Written By: Abram C. Isola
+//
+package main
+
+import (
+	_ "github.com/aisola/go-coreutils/ls"
+	_ "github.com/aisola/go-coreutils/stat"
+	_ "github.com/aisola/go-coreutils/uname"
+	_ "github.com/aisola/go-coreutils/uptime"
+)
@@ -0,0 +1,15 @@
+//
+// applets_unix.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+// Written By: Abram C. Isola
+//
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	_ "github.com/aisola/go-coreutils/sync"
+)
@@ -0,0 +1,11 @@
+//
+// applets_windows.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+// Written By: Abram C. Isola
+//
+package main
+
+import (
+	_ "github.com/aisola/go-coreutils/ls"
+)
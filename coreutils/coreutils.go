@@ -0,0 +1,107 @@
+//
+// coreutils.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+// Written By: Abram C. Isola
+//
+package main
+
+import "fmt"
+import "os"
+import "path/filepath"
+import "strings"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+
+const (
+	help_text string = `
+    Usage: coreutils APPLET [ARGUMENT]...
+       or: APPLET [ARGUMENT]...
+       or: coreutils OPTION
+
+    run one of the utilities linked into this binary. The utility is picked
+    from the name the binary was invoked as, so a symlink named 'ls' pointing
+    to coreutils behaves like ls.
+
+        --help           display this help and exit
+        --version        output version information and exit
+        --list           list the utilities linked into this binary
+        --install DIR    create a symlink in DIR for every utility
+    `
+	version_text = `
+    coreutils (go-coreutils) 0.1
+
+    Copyright (C) 2014, The GO-Coreutils Developers.
+    This program comes with ABSOLUTELY NO WARRANTY; for details see
+    LICENSE. This is free software, and you are welcome to redistribute
+    it under certain conditions in LICENSE.
+`
+)
+
+// appletName returns the utility name the binary was invoked as.
+func appletName(arg0 string) string {
+	return strings.TrimSuffix(filepath.Base(arg0), ".exe")
+}
+
+// install creates a symlink to the running binary in dir for every
+// utility, skipping (and reporting) names that already exist.
+func install(report *diag.Reporter, dir string) int {
+	target, err := os.Executable()
+	if err == nil {
+		target, err = filepath.Abs(target)
+	}
+	if err != nil {
+		report.Errorf("cannot locate executable: %s", diag.Strerror(err))
+		return report.Status()
+	}
+
+	for _, name := range applet.Names() {
+		link := filepath.Join(dir, name)
+		if err := os.Symlink(target, link); err != nil {
+			report.Errorf("cannot create link %s: %s", diag.Quote(link), diag.Strerror(err))
+		}
+	}
+	return report.Status()
+}
+
+func run(args []string) int {
+	if main, ok := applet.Lookup(appletName(args[0])); ok {
+		return main(args[1:])
+	}
+
+	if len(args) < 2 {
+		fmt.Print(help_text)
+		return 1
+	}
+
+	report := diag.New("coreutils")
+	switch args[1] {
+	case "--help", "-help":
+		fmt.Print(help_text)
+		return 0
+	case "--version", "-version":
+		fmt.Print(version_text)
+		return 0
+	case "--list", "-list":
+		for _, name := range applet.Names() {
+			fmt.Println(name)
+		}
+		return 0
+	case "--install", "-install":
+		if len(args) != 3 {
+			return report.Usagef("--install requires a directory")
+		}
+		return install(report, args[2])
+	}
+
+	main, ok := applet.Lookup(args[1])
+	if !ok {
+		return report.Usagef("unknown applet %s", diag.Quote(args[1]))
+	}
+	return main(args[2:])
+}
+
+func main() {
+	os.Exit(run(os.Args))
+}
@@ -0,0 +1,118 @@
+//
+// coreutils_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package main
+
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+// The arguments of a case are the whole command line, starting with the
+// name the binary was invoked as.
+func TestMain(m *testing.M) { conformance.Main(m, run) }
+
+func TestAppletName(t *testing.T) {
+	for _, test := range []struct{ arg0, name string }{
+		{"ls", "ls"},
+		{"/usr/local/bin/ls", "ls"},
+		{"./coreutils", "coreutils"},
+		{"ls.exe", "ls"},
+		{filepath.Join("bin", "sha256sum.exe"), "sha256sum"},
+	} {
+		if got := appletName(test.arg0); got != test.name {
+			t.Errorf("appletName(%q) = %q, want %q", test.arg0, got, test.name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	usage := "Try 'coreutils --help' for more information.\n"
+	for _, test := range []struct {
+		args           []string
+		status         int
+		stdout, stderr string
+	}{
+		{[]string{"echo", "hi"}, 0, "hi\n", ""},
+		{[]string{"/usr/local/bin/echo", "hi"}, 0, "hi\n", ""},
+		{[]string{"/bin/false"}, 1, "", ""},
+		{[]string{"coreutils", "echo", "hi"}, 0, "hi\n", ""},
+		{[]string{"/opt/coreutils", "basename", "/a/b"}, 0, "b\n", ""},
+		{[]string{"coreutils", "--version"}, 0, version_text, ""},
+		{[]string{"coreutils", "--help"}, 0, help_text, ""},
+		{[]string{"coreutils"}, 1, help_text, ""},
+		{[]string{"coreutils", "nope"}, 1, "", "coreutils: unknown applet 'nope'\n" + usage},
+		{[]string{"nope", "echo", "hi"}, 0, "hi\n", ""},
+		{[]string{"coreutils", "--install"}, 1, "", "coreutils: --install requires a directory\n" + usage},
+	} {
+		got := conformance.Exec(t, t.TempDir(), conformance.Case{Args: test.args})
+		if got.Status != test.status || string(got.Stdout) != test.stdout || string(got.Stderr) != test.stderr {
+			t.Errorf("%q: got %d, %q, %q; want %d, %q, %q", test.args, got.Status, got.Stdout, got.Stderr,
+				test.status, test.stdout, test.stderr)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	got := conformance.Exec(t, t.TempDir(), conformance.Case{Args: []string{"coreutils", "--list"}})
+	want := strings.Join(applet.Names(), "\n") + "\n"
+	if got.Status != 0 || string(got.Stdout) != want {
+		t.Fatalf("coreutils --list: got %d, %q; want 0, %q", got.Status, got.Stdout, want)
+	}
+	for _, name := range []string{"ls", "md5sum", "wc"} {
+		if !strings.Contains(want, "\n"+name+"\n") {
+			t.Errorf("coreutils --list does not list %s", name)
+		}
+	}
+}
+
+// --install links every applet to the binary, and reports the names that
+// already exist without replacing them.
+func TestInstall(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ls"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("elsewhere", filepath.Join(dir, "wc")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := conformance.Exec(t, dir, conformance.Case{Args: []string{"coreutils", "--install", "."}})
+	wantErr := "coreutils: cannot create link 'ls': File exists\n" +
+		"coreutils: cannot create link 'wc': File exists\n"
+	if got.Status != 1 || len(got.Stdout) != 0 || string(got.Stderr) != wantErr {
+		t.Errorf("coreutils --install: got %d, %q, %q; want 1, \"\", %q", got.Status, got.Stdout, got.Stderr, wantErr)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range applet.Names() {
+		link, err := os.Readlink(filepath.Join(dir, name))
+		switch name {
+		case "ls":
+			if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != "mine" {
+				t.Errorf("ls was replaced: %q, %v", link, err)
+			}
+		case "wc":
+			if link != "elsewhere" {
+				t.Errorf("wc links to %q, want elsewhere", link)
+			}
+		default:
+			if err != nil || !filepath.IsAbs(link) || filepath.Base(link) != filepath.Base(exe) {
+				t.Errorf("%s links to %q (%v), want the binary %s", name, link, err, exe)
+			}
+		}
+	}
+
+	// Installing again only reports the links that are now there.
+	got = conformance.Exec(t, dir, conformance.Case{Args: []string{"coreutils", "--install", dir}})
+	if got.Status != 1 || strings.Count(string(got.Stderr), "File exists\n") != len(applet.Names()) {
+		t.Errorf("coreutils --install again: got %d, %q", got.Status, got.Stderr)
+	}
+}
@@ -4,13 +4,16 @@
 //
 // Written By: Michael Murphy
 //
-package main
+package date
 
-import "flag"
 import "fmt"
 import "os"
 import "time"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	RFC3339_DATE    = "2006-01-02"
 	RFC3339_SECONDS = "2006-01-02 03:04:05-07:00"
@@ -25,30 +28,30 @@ const (
 
 	Display the current time in the given FORMAT.
 
-	-I [TIMESPEC], -iso-8601=[TIMESPEC]
+	-I[TIMESPEC], --iso-8601[=TIMESPEC]
 	        output date/time in ISO 8601 format. TIMESPEC='date' for date
 	        only, 'hours', 'minutes', 'seconds', or 'ns' for date and
 	        time to the indicated precision.
 
-	-r, -reference=FILE
+	-r, --reference=FILE
 	      display the last modification time of FILE
 
-	-R, -rfc-1123
+	-R, --rfc-1123
 	      output date and time in RFC 1123 format.
 	      Example: Thu, 19 Jun 2014 03:53:45 -0500
 
-	-rfc-3339=[TIMESPEC]
+	--rfc-3339=TIMESPEC
 	      output date and time in RFC 3339 format.  TIMESPEC='date',
               'seconds', or 'ns' for date and time to the indicated precision.
               Date and time components are separated by a single space.
               Example: 2014-06-19 03:55:49-05:00
 
-        -u, -utc, -universal
+        -u, --utc, --universal
               print Coordinated Universal Time (UTC)
 
-        -help display this help and exit
+        --help display this help and exit
 
-        -version output version information and exit
+        --version output version information and exit
 `
 	VERSION_TEXT = `
 	       date (go-coreutils) 0.1
@@ -61,20 +64,17 @@ const (
 )
 
 var (
-	printUTC          = flag.Bool("u", false, "print Coordinated Universal Time (UTC)")
-	printUTCLong      = flag.Bool("utc", false, "print Coordinated Universal Time (UTC)")
-	printUTCLonger    = flag.Bool("universal", false, "print Coordinated Universal Time (UTC)")
-	referenceMode     = flag.Bool("r", false, "display the last modification time of a file")
-	referenceModeLong = flag.Bool("reference", false, "display the last modification time of a file")
-	printISO8601      = flag.String("I", "", "output date and time in ISO 8601 format: [date|hours|minutes|seconds]")
-	printISO8601Long  = flag.String("iso-8601", "", "output date and time in ISO 8601 format: [date|hours|minutes|seconds]")
-	printRFC1123      = flag.Bool("R", false, "output date and time in RFC 2822 format.")
-	printRFC1123Long  = flag.Bool("rfc-1123", false, "output date and time in RFC 2822 format.")
-	printRFC3339      = flag.String("rfc-3339", "", "output date and time in RFC 3339 format: [date|seconds|ns]")
-	help              = flag.Bool("help", false, "display help information")
-	version           = flag.Bool("version", false, "output version information")
+	printUTC      *bool
+	referenceFile *string
+	printISO8601  *string
+	printRFC1123  *bool
+	printRFC3339  *string
 )
 
+func init() {
+	applet.Register("date", Main)
+}
+
 // getTime returns the current time in either the default time zone or UTC.
 func getTime() time.Time {
 	if *printUTC {
@@ -98,11 +98,6 @@ func printDate(t time.Time) {
 	switch {
 	case *printRFC1123:
 		fmt.Println(t.Format(time.RFC1123Z))
-	case *printRFC3339 != "" && *printRFC3339 != "date" &&
-		*printRFC3339 != "seconds" && *printRFC3339 != "ns":
-		fmt.Printf("date: invalid argument '%s' for '--rfc-3339'\n"+
-			"Valid arguments are:\n  - 'date'\n  - 'seconds'\n  - 'ns'\n"+
-			"Try 'date --help' for more information.\n", *printRFC3339)
 	case *printRFC3339 == "date":
 		fmt.Println(t.Format(RFC3339_DATE))
 	case *printRFC3339 == "seconds":
@@ -124,47 +119,73 @@ func printDate(t time.Time) {
 	}
 }
 
-// getReference creates an os.FileInfo of the reference file and returns it.
-func getReference() os.FileInfo {
-	file, err := os.Stat(flag.Arg(0))
-	if err != nil {
-		fmt.Printf("date: %s - No such file or directory\n", flag.Arg(0))
-		os.Exit(0)
+// checkTimespec returns the usage message for an invalid TIMESPEC given to
+// option, or "" if timespec is one of valid.
+func checkTimespec(option, timespec string, valid []string) string {
+	if timespec == "" {
+		return ""
 	}
-	return file
-}
-
-func main() {
-	switch {
-	case *referenceMode && flag.NArg() < 1:
-		fmt.Println("date: option requires an argument -- 'r'")
-	case *referenceMode:
-		printDate(getModificationTime(getReference()))
-	default:
-		printDate(getTime())
+	for _, v := range valid {
+		if timespec == v {
+			return ""
+		}
+	}
+	msg := fmt.Sprintf("invalid argument %s for '%s'\nValid arguments are:",
+		diag.Quote(timespec), option)
+	for _, v := range valid {
+		msg += fmt.Sprintf("\n  - '%s'", v)
 	}
+	return msg
 }
 
-func init() {
-	flag.Parse()
+// Main runs date with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("date")
+	opts := getopt.New("date")
+	printUTC = opts.Bool('u', "utc")
+	opts.BoolVar(printUTC, 0, "universal")
+	referenceFile = opts.String('r', "reference", "")
+	printISO8601 = new(string)
+	opts.Func('I', "iso-8601", getopt.OptionalArgument, func(timespec string) error {
+		if timespec == "" {
+			timespec = "date"
+		}
+		*printISO8601 = timespec
+		return nil
+	})
+	printRFC1123 = opts.Bool('R', "rfc-1123")
+	printRFC3339 = opts.String(0, "rfc-3339", "")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 	if *help {
-		fmt.Println(HELP_TEXT)
-		os.Exit(0)
+		fmt.Print(HELP_TEXT)
+		return 0
 	}
 	if *version {
-		fmt.Println(VERSION_TEXT)
-		os.Exit(0)
+		fmt.Print(VERSION_TEXT)
+		return 0
 	}
-	if *printUTCLong || *printUTCLonger {
-		*printUTC = true
+	if msg := checkTimespec("--iso-8601", *printISO8601,
+		[]string{"hours", "minutes", "date", "seconds", "ns"}); msg != "" {
+		return report.Usagef("%s", msg)
 	}
-	if *printISO8601Long != "" {
-		*printISO8601 = *printISO8601Long
+	if msg := checkTimespec("--rfc-3339", *printRFC3339,
+		[]string{"date", "seconds", "ns"}); msg != "" {
+		return report.Usagef("%s", msg)
 	}
-	if *printRFC1123Long {
-		*printRFC1123 = true
-	}
-	if *referenceModeLong {
-		*referenceMode = true
+
+	if *referenceFile != "" {
+		file, err := os.Stat(*referenceFile)
+		if err != nil {
+			report.Error(*referenceFile, err)
+			return report.Status()
+		}
+		printDate(getModificationTime(file))
+	} else {
+		printDate(getTime())
 	}
+	return 0
 }
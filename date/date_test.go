@@ -0,0 +1,33 @@
+//
+// date_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package date
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "date", []conformance.Case{
+		{Name: "reference", Args: []string{"-r", "a.txt"}},
+		{Name: "reference-utc", Args: []string{"-u", "-r", "a.txt"}},
+		{Name: "rfc-1123", Args: []string{"-R", "-r", "a.txt"}},
+		{Name: "rfc-3339-date", Args: []string{"--rfc-3339=date", "-r", "a.txt"}},
+		{Name: "rfc-3339-seconds", Args: []string{"--rfc-3339=seconds", "-r", "a.txt"},
+			Skip: "date --rfc-3339 uses a 12-hour clock"},
+		{Name: "iso-8601", Args: []string{"-I", "-r", "a.txt"}},
+		{Name: "iso-8601-hours", Args: []string{"--iso-8601=hours", "-r", "a.txt"},
+			Skip: "date -I prints Z rather than +00:00 for UTC"},
+		{Name: "iso-8601-minutes", Args: []string{"--iso-8601=minutes", "-r", "a.txt"},
+			Skip: "date -I prints Z rather than +00:00 for UTC"},
+		{Name: "iso-8601-seconds", Args: []string{"-Iseconds", "-r", "a.txt"},
+			Skip: "date -I prints Z rather than +00:00 for UTC"},
+		{Name: "iso-8601-invalid", Args: []string{"--iso-8601=days"}},
+		{Name: "rfc-3339-invalid", Args: []string{"--rfc-3339=hours"}},
+		{Name: "missing-reference", Args: []string{"-r", "missing"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
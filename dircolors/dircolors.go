@@ -0,0 +1,173 @@
+//
+// dircolors.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package dircolors
+
+import "bufio"
+import "fmt"
+import "io"
+import "os"
+import "path/filepath"
+import "strings"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/lscolors"
+
+const (
+	help_text string = `
+    Usage: dircolors [OPTION]... [FILE]
+
+    Output commands to set the LS_COLORS environment variable, which ls --color uses to color the
+    names of files by their type and extension. If FILE is given, it is read to determine which
+    colors to use; otherwise a built-in database is used. Without -b or -c, the syntax is that of
+    the shell in SHELL.
+
+    FILE holds a keyword and a color on each line, and # starts a comment. The lines after TERM
+    or COLORTERM lines only apply if the terminal type in TERM, or COLORTERM, matches one of
+    their patterns. Run 'dircolors --print-database' for the keywords.
+
+        -b, --sh, --bourne-shell    output Bourne shell code to set LS_COLORS
+        -c, --csh, --c-shell        output C shell code to set LS_COLORS
+        -p, --print-database        output the built-in database
+            --print-ls-colors       output each entry of LS_COLORS in its own color
+            --help                  display this help and exit
+            --version               output version information and exit
+`
+	version_text = `
+    dircolors (go-coreutils) 0.1
+
+    Copyright (C) 2014, The GO-Coreutils Developers.
+    This program comes with ABSOLUTELY NO WARRANTY; for details see
+    LICENSE. This is free software, and you are welcome to redistribute
+    it under certain conditions in LICENSE.
+`
+)
+
+func init() {
+	applet.Register("dircolors", Main)
+}
+
+// The syntaxes of the output.
+const (
+	unknownShell = iota
+	bourneShell
+	cShell
+)
+
+// guessShell returns the syntax of the shell named by SHELL.
+func guessShell() int {
+	shell := os.Getenv("SHELL")
+	switch base := filepath.Base(shell); {
+	case shell == "":
+		return unknownShell
+	case base == "csh" || base == "tcsh":
+		return cShell
+	}
+	return bourneShell
+}
+
+// write writes the entries in the given syntax, or with --print-ls-colors
+// each entry in its own color.
+func write(w io.Writer, entries []lscolors.Entry, syntax int, printLSColors bool) error {
+	out := bufio.NewWriter(w)
+	if printLSColors {
+		for _, e := range entries {
+			fmt.Fprintf(out, "\033[%sm%s\t%s\033[0m\n", e.Value, e.Key, e.Value)
+		}
+		return out.Flush()
+	}
+	var value strings.Builder
+	for _, e := range entries {
+		value.WriteString(lscolors.Quote(e.Key) + "=" + lscolors.Quote(e.Value) + ":")
+	}
+	if syntax == cShell {
+		fmt.Fprintf(out, "setenv LS_COLORS '%s'\n", value.String())
+	} else {
+		fmt.Fprintf(out, "LS_COLORS='%s';\nexport LS_COLORS\n", value.String())
+	}
+	return out.Flush()
+}
+
+// Main runs dircolors with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("dircolors")
+	opts := getopt.New("dircolors")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	syntax := unknownShell
+	bourne := func(string) error { syntax = bourneShell; return nil }
+	csh := func(string) error { syntax = cShell; return nil }
+	opts.Func('b', "sh", getopt.NoArgument, bourne)
+	opts.Func(0, "bourne-shell", getopt.NoArgument, bourne)
+	opts.Func('c', "csh", getopt.NoArgument, csh)
+	opts.Func(0, "c-shell", getopt.NoArgument, csh)
+	printDatabase := opts.Bool('p', "print-database")
+	printLSColors := opts.Bool(0, "print-ls-colors")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
+	if *help {
+		fmt.Print(help_text)
+		return 0
+	}
+	if *version {
+		fmt.Print(version_text)
+		return 0
+	}
+
+	switch {
+	case (*printDatabase || *printLSColors) && syntax != unknownShell:
+		return report.Usagef("the options to output non shell syntax,\nand to select a shell syntax are mutually exclusive")
+	case *printDatabase && *printLSColors:
+		return report.Usagef("options --print-database and --print-ls-colors are mutually exclusive")
+	case *printDatabase && opts.NArg() > 0:
+		return report.Usagef("extra operand %s\nfile operands cannot be combined with --print-database (-p)", diag.Quote(opts.Arg(0)))
+	case opts.NArg() > 1:
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(1)))
+	}
+	if *printDatabase {
+		fmt.Print(lscolors.Database)
+		return 0
+	}
+
+	if !*printLSColors && syntax == unknownShell {
+		if syntax = guessShell(); syntax == unknownShell {
+			report.Errorf("no SHELL environment variable, and no shell type option given")
+			return report.Status()
+		}
+	}
+
+	name := "<internal>"
+	var r io.Reader = strings.NewReader(lscolors.Database)
+	if opts.NArg() == 1 {
+		name = opts.Arg(0)
+		if name == "-" {
+			r = os.Stdin
+		} else {
+			f, err := os.Open(name)
+			if err != nil {
+				report.Error(name, err)
+				return report.Status()
+			}
+			defer f.Close()
+			r = f
+		}
+	}
+	entries, ok, err := lscolors.Compile(r, os.Getenv("TERM"), os.Getenv("COLORTERM"), func(line int, msg string) {
+		report.Errorf("%s:%d: %s", diag.QuoteName(name), line, msg)
+	})
+	if err != nil {
+		report.Error(name, err)
+		return report.Status()
+	}
+	if !ok {
+		return report.Status()
+	}
+	if err := write(os.Stdout, entries, syntax, *printLSColors); err != nil {
+		report.Error("standard output", err)
+	}
+	return report.Status()
+}
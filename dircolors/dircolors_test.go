@@ -0,0 +1,41 @@
+//
+// dircolors_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package dircolors
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+// The terminal type decides which lines of a database apply, so every case
+// sets it.
+var xterm = []string{"TERM=xterm", "COLORTERM=", "SHELL=/bin/sh"}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "dircolors", []conformance.Case{
+		{Name: "bourne-shell", Args: []string{"-b"}, Env: xterm},
+		{Name: "c-shell", Args: []string{"--csh"}, Env: xterm},
+		{Name: "last-syntax-wins", Args: []string{"-c", "--sh", "colors.txt"}, Env: xterm},
+		{Name: "shell-from-environment", Args: []string{"colors.txt"},
+			Env: []string{"TERM=xterm", "COLORTERM=", "SHELL=/usr/bin/tcsh"}},
+		{Name: "no-shell", Args: []string{"colors.txt"}, Env: []string{"TERM=xterm", "COLORTERM=", "SHELL="}},
+		{Name: "unknown-terminal", Args: []string{"-b"}, Env: []string{"TERM=dumb", "COLORTERM="}},
+		{Name: "colorterm", Args: []string{"-b"}, Env: []string{"TERM=dumb", "COLORTERM=truecolor"}},
+		{Name: "no-terminal", Args: []string{"-b", "colors.txt"}, Env: []string{"TERM=", "COLORTERM="}},
+		{Name: "file", Args: []string{"-b", "colors.txt"}, Env: xterm},
+		{Name: "other-terminal", Args: []string{"-b", "colors.txt"}, Env: []string{"TERM=linux", "COLORTERM="}},
+		{Name: "stdin", Args: []string{"-c", "-"}, Stdin: "DIR 35\nLINK 36\n", Env: xterm},
+		{Name: "invalid-lines", Args: []string{"-b", "bad.txt"}, Env: []string{"TERM=dumb", "COLORTERM=1"}},
+		{Name: "print-database", Args: []string{"-p"}},
+		{Name: "print-ls-colors", Args: []string{"--print-ls-colors", "colors.txt"}, Env: xterm},
+		{Name: "print-database-operand", Args: []string{"-p", "colors.txt"}},
+		{Name: "print-and-shell", Args: []string{"--print-ls-colors", "-b"}},
+		{Name: "print-both", Args: []string{"-p", "--print-ls-colors"}},
+		{Name: "extra-operand", Args: []string{"colors.txt", "bad.txt"}},
+		{Name: "missing-file", Args: []string{"-b", "missing"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -4,13 +4,15 @@
 //
 // Written By: Trey Tacon, Abram C. Isola, Michael Murphy
 //
-package main
+package dirname
 
 import (
-	"flag"
 	"fmt"
-	"os"
 	"path/filepath"
+
+	"github.com/aisola/go-coreutils/internal/applet"
+	"github.com/aisola/go-coreutils/internal/diag"
+	"github.com/aisola/go-coreutils/internal/getopt"
 )
 
 const (
@@ -20,10 +22,10 @@ const (
     Output each NAME with its last non-slash component and trailing slashes
     removed; if NAME contains no /'s, output '.' (meaning the current directory).
 
-        -help     display this help and exit
-        -version  output version information and exit
+        --help    display this help and exit
+        --version output version information and exit
         
-        -z, -zero
+        -z, --zero
               separate output with NUL rather than newline
     `
 	version_text = `
@@ -34,56 +36,55 @@ const (
     LICENSE. This is free software, and you are welcome to redistribute 
     it under certain conditions in LICENSE.
 `
-	zero_text = "separate output with NUL rather than newline"
 )
 
 var (
-	help     = flag.Bool("help", false, help_text)
-	version  = flag.Bool("version", false, version_text)
-	zero     = flag.Bool("z", false, zero_text)
-	zeroLong = flag.Bool("zero", false, zero_text)
+	help    *bool
+	version *bool
+	zero    *bool
 )
 
-// If zeroLong is enabled, set zero to enabled.
-func processFlags() {
-	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
-	}
-	if *zeroLong {
-		*zero = true
-	}
-	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
-	}
+func init() {
+	applet.Register("dirname", Main)
 }
 
-// Return the dirname
-func getDirName(file string) string {
+// Dir returns all but the last element of the path file.
+func Dir(file string) string {
 	return filepath.Dir(filepath.Clean(file))
 }
 
-/* If the number of arguments given is zero, print the help text.
- * Otherwise check if the zero flag is set and print the dirname
- * of each file. */
-func argumentCheck() {
-	if flag.NArg() < 1 {
-		fmt.Println(help_text)
-		os.Exit(0)
-	} else {
-		for _, file := range flag.Args() {
-			if *zero {
-				fmt.Print(getDirName(file))
-			} else {
-				fmt.Println(getDirName(file))
-			}
+// Check if the zero flag is set and print the dirname of each file.
+func argumentCheck(files []string) {
+	for _, file := range files {
+		if *zero {
+			fmt.Print(Dir(file))
+		} else {
+			fmt.Println(Dir(file))
 		}
 	}
 }
 
-func main() {
-	flag.Parse()
-	processFlags()
-	argumentCheck()
+// Main runs dirname with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("dirname")
+	opts := getopt.New("dirname")
+	help = opts.Bool(0, "help")
+	version = opts.Bool(0, "version")
+	zero = opts.Bool('z', "zero")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
+	if *version {
+		fmt.Print(version_text)
+		return 0
+	}
+	if *help {
+		fmt.Println(help_text)
+		return 0
+	}
+	if opts.NArg() < 1 {
+		return report.Usagef("missing operand")
+	}
+	argumentCheck(opts.Args())
+	return 0
 }
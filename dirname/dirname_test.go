@@ -0,0 +1,25 @@
+//
+// dirname_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package dirname
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "dirname", []conformance.Case{
+		{Name: "path", Args: []string{"/usr/bin/sort"}},
+		{Name: "no-slash", Args: []string{"sort"}},
+		{Name: "trailing-slash", Args: []string{"/usr/lib/"}},
+		{Name: "root", Args: []string{"/"}},
+		{Name: "multiple", Args: []string{"a/b", "c/d/e"}},
+		{Name: "zero", Args: []string{"-z", "a/b", "c/d"},
+			Skip: "dirname -z does not terminate the names with NUL"},
+		{Name: "missing-operand"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
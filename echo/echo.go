@@ -4,13 +4,14 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package echo
 
 import "os"
 import "fmt"
-import "flag"
 import "strings"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+
 const (
 	help_text string = `
     Usage: echo [OPTION]... [STRING]...
@@ -35,25 +36,54 @@ const (
 `
 )
 
-func main() {
-	enableEscapeChars := flag.Bool("e", false, "enable interpretation of backslash escapes")
-	omitNewline := flag.Bool("n", false, "do not output the trailing newline")
-	disableEscapeChars := flag.Bool("E", true, "disable interpretation of backslash escapes (default)")
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("echo", Main)
+}
+
+// isOption reports whether arg is made up only of the letters echo accepts
+// as options, like -n or -neE. Anything else, -- included, is printed.
+func isOption(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	for _, c := range arg[1:] {
+		if c != 'n' && c != 'e' && c != 'E' {
+			return false
+		}
+	}
+	return true
+}
 
-	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
+// Main runs echo with the given arguments and returns its exit status.
+func Main(args []string) int {
+	if len(args) == 1 {
+		switch args[0] {
+		case "--help":
+			fmt.Println(help_text)
+			return 0
+		case "--version":
+			fmt.Print(version_text)
+			return 0
+		}
 	}
 
-	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+	// Like GNU echo, only leading arguments such as -n or -neE are options.
+	enableEscapeChars, omitNewline := false, false
+	for len(args) > 0 && isOption(args[0]) {
+		for _, c := range args[0][1:] {
+			switch c {
+			case 'e':
+				enableEscapeChars = true
+			case 'E':
+				enableEscapeChars = false
+			case 'n':
+				omitNewline = true
+			}
+		}
+		args = args[1:]
 	}
 
-	concatenated := strings.Join(flag.Args(), " ")
+	concatenated := strings.Join(args, " ")
 
 	a := []rune(concatenated)
 
@@ -65,7 +95,7 @@ func main() {
 		for i := 0; i < length; {
 			c := a[i]
 			i++
-			if (*enableEscapeChars == true || *disableEscapeChars == false) && c == '\\' && i < length {
+			if enableEscapeChars && c == '\\' && i < length {
 				c = a[i]
 				i++
 				switch c {
@@ -74,7 +104,7 @@ func main() {
 				case 'b':
 					c = '\b'
 				case 'c':
-					os.Exit(0)
+					return 0
 				case 'e':
 					c = '\x1B'
 				case 'f':
@@ -108,7 +138,8 @@ func main() {
 	}
 
 	os.Stdout.WriteString(string(a[:ai]))
-	if *omitNewline == false {
+	if !omitNewline {
 		fmt.Print("\n")
 	}
+	return 0
 }
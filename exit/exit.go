@@ -4,12 +4,14 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package exit
 
 import "os"
-import "log"
 import "fmt"
-import "flag"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
 
 const (
 	help_text string = `
@@ -30,29 +32,37 @@ const (
 `
 )
 
-// Get PID of Parent
-var process = os.Getppid()
+func init() {
+	applet.Register("exit", Main)
+}
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+// Main runs exit with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("exit")
+	opts := getopt.New("exit")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	pproc, err := os.FindProcess(process)
-
+	// Get PID of Parent
+	pproc, err := os.FindProcess(os.Getppid())
+	if err == nil {
+		err = pproc.Kill()
+	}
 	if err != nil {
-		log.Fatalln(err)
-	} else {
-		pproc.Kill()
+		report.Errorf("cannot kill parent process: %s", diag.Strerror(err))
 	}
+	return report.Status()
 }
@@ -0,0 +1,21 @@
+//
+// exit_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package exit
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+// exit is not part of GNU coreutils, and without a usage error it kills
+// the process running the tests, so only its usage errors are checked.
+func TestUsage(t *testing.T) {
+	got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: []string{"--bogus"}})
+	want := "exit: unrecognized option '--bogus'\nTry 'exit --help' for more information.\n"
+	if got.Status != 1 || string(got.Stderr) != want {
+		t.Errorf("exit --bogus: status %d, stderr %q; want 1, %q", got.Status, got.Stderr, want)
+	}
+}
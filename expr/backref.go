@@ -0,0 +1,321 @@
+//
+// backref.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package expr
+
+import "regexp/syntax"
+import "sort"
+import "strconv"
+import "strings"
+import "unicode/utf8"
+
+// backrefName is the name of the empty group that stands for the
+// back-reference numbered i in the translation of a pattern.
+func backrefName(i int) string { return "ref" + strconv.Itoa(i) }
+
+// A backtracker matches a pattern with back-references, parsed from its
+// translation, by trying every way it can match and keeping the longest, as
+// POSIX wants. Among the longest, the groups are those of the first found,
+// with each repetition taking as much as it can.
+//
+// A way to match that reaches an expression at the same place, with the same
+// rest to match and the same text for the back-references to come, as one
+// already tried can only end where that one did, so it is not tried again:
+// without that, a pattern such as \(a*\)*\1b takes time exponential in the
+// length of the string.
+type backtracker struct {
+	re       *syntax.Regexp
+	refs     []int // the group each back-reference names
+	caps     []int // the capture of each \( \) group, by number
+	captures int   // the number of captures, the whole match included
+	refCaps  []int // the captures of the groups referred to
+
+	// The state of a match.
+	s      string
+	at     []int // the start and end of each capture, or -1
+	best   int   // the end of the longest match found
+	bestAt []int // the captures of that match
+	conts  map[cont]*cont
+	tried  map[state]bool
+}
+
+// A cont is what is left to match once an expression has matched: the
+// continuations are kept in a map so that equal ones are the same pointer,
+// and a state can name them.
+type cont struct {
+	op       contOp
+	re       *syntax.Regexp // the group, the concatenation, or the repeated expression
+	n        int            // the next expression of a concatenation
+	i        int            // where a group or a repetition started
+	min, max int            // the repetitions still wanted and allowed
+	next     *cont
+}
+
+type contOp int
+
+const (
+	contMatch   contOp = iota // the end of the pattern
+	contCapture               // the end of a group
+	contConcat                // the rest of a concatenation
+	contRepeat                // a repetition
+)
+
+// A state is an expression tried at a place, which is enough to know how
+// it ends.
+type state struct {
+	re *syntax.Regexp
+	i  int
+	k  *cont
+	at string // the captures of the groups referred to, but those k sets
+}
+
+// newBacktracker returns a backtracker for re, a translation with the
+// back-references refs.
+func newBacktracker(re *syntax.Regexp, refs []int) *backtracker {
+	b := &backtracker{re: re, refs: refs, captures: re.MaxCap() + 1}
+	// The captures that are not back-references are the groups, in order.
+	var caps []int
+	var walk func(re *syntax.Regexp)
+	walk = func(re *syntax.Regexp) {
+		if re.Op == syntax.OpCapture && re.Name == "" {
+			caps = append(caps, re.Cap)
+		}
+		for _, sub := range re.Sub {
+			walk(sub)
+		}
+	}
+	walk(re)
+	sort.Ints(caps)
+	b.caps = append([]int{0}, caps...)
+	for _, ref := range refs {
+		c := b.caps[ref]
+		if !containsInt(b.refCaps, c) {
+			b.refCaps = append(b.refCaps, c)
+		}
+	}
+	return b
+}
+
+// match matches the pattern at the start of s, and returns the start and
+// end of the match and of each group, as regexp.FindStringSubmatchIndex
+// does, or nil if it does not match.
+func (b *backtracker) match(s string) []int {
+	b.s = s
+	b.at = make([]int, 2*b.captures)
+	for i := range b.at {
+		b.at[i] = -1
+	}
+	b.best = -1
+	b.bestAt = make([]int, len(b.at))
+	b.conts = make(map[cont]*cont)
+	b.tried = make(map[state]bool)
+	b.try(b.re, 0, b.cont(cont{op: contMatch}))
+	b.conts, b.tried = nil, nil
+	if b.best < 0 {
+		return nil
+	}
+	m := []int{0, b.best}
+	for _, c := range b.caps[1:] {
+		m = append(m, b.bestAt[2*c], b.bestAt[2*c+1])
+	}
+	return m
+}
+
+// cont returns the continuation equal to c.
+func (b *backtracker) cont(c cont) *cont {
+	if k, ok := b.conts[c]; ok {
+		return k
+	}
+	k := &c
+	b.conts[c] = k
+	return k
+}
+
+// try matches re at i, and continues with k from each place the match may
+// end until that returns true, which try then returns.
+func (b *backtracker) try(re *syntax.Regexp, i int, k *cont) bool {
+	st := state{re, i, k, b.captured(k)}
+	if b.tried[st] {
+		return false
+	}
+	b.tried[st] = true
+
+	s := b.s
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return b.resume(k, i)
+	case syntax.OpLiteral:
+		for _, want := range re.Rune {
+			r, n := utf8.DecodeRuneInString(s[i:])
+			if n == 0 || r != want {
+				return false
+			}
+			i += n
+		}
+		return b.resume(k, i)
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if n == 0 || !matchesRune(re, r) {
+			return false
+		}
+		return b.resume(k, i+n)
+	case syntax.OpBeginLine:
+		return (i == 0 || s[i-1] == '\n') && b.resume(k, i)
+	case syntax.OpEndLine:
+		return (i == len(s) || s[i] == '\n') && b.resume(k, i)
+	case syntax.OpBeginText:
+		return i == 0 && b.resume(k, i)
+	case syntax.OpEndText:
+		return i == len(s) && b.resume(k, i)
+	case syntax.OpWordBoundary:
+		return b.atWordBoundary(i) && b.resume(k, i)
+	case syntax.OpNoWordBoundary:
+		return !b.atWordBoundary(i) && b.resume(k, i)
+	case syntax.OpCapture:
+		if strings.HasPrefix(re.Name, "ref") {
+			ref, _ := strconv.Atoi(re.Name[len("ref"):])
+			c := b.caps[b.refs[ref]]
+			start, end := b.at[2*c], b.at[2*c+1]
+			if start < 0 || !strings.HasPrefix(s[i:], s[start:end]) {
+				return false
+			}
+			return b.resume(k, i+end-start)
+		}
+		return b.try(re.Sub[0], i, b.cont(cont{op: contCapture, re: re, i: i, next: k}))
+	case syntax.OpConcat:
+		return b.concat(re, 0, i, k)
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if b.try(sub, i, k) {
+				return true
+			}
+		}
+		return false
+	case syntax.OpStar:
+		return b.repeat(re.Sub[0], 0, -1, i, k)
+	case syntax.OpPlus:
+		return b.repeat(re.Sub[0], 1, -1, i, k)
+	case syntax.OpQuest:
+		return b.repeat(re.Sub[0], 0, 1, i, k)
+	case syntax.OpRepeat:
+		return b.repeat(re.Sub[0], re.Min, re.Max, i, k)
+	}
+	return false // syntax.OpNoMatch
+}
+
+// resume continues with k from j, where an expression ended.
+func (b *backtracker) resume(k *cont, j int) bool {
+	switch k.op {
+	case contMatch:
+		if j > b.best {
+			b.best = j
+			copy(b.bestAt, b.at)
+		}
+		return j == len(b.s) // nothing can be longer
+	case contCapture:
+		// The group is set for what follows it, and unset again if that
+		// does not match.
+		c := k.re.Cap
+		start, end := b.at[2*c], b.at[2*c+1]
+		b.at[2*c], b.at[2*c+1] = k.i, j
+		if b.resume(k.next, j) {
+			return true
+		}
+		b.at[2*c], b.at[2*c+1] = start, end
+		return false
+	case contConcat:
+		return b.concat(k.re, k.n, j, k.next)
+	}
+	// contRepeat
+	if j == k.i && k.min <= 0 {
+		return false
+	}
+	return b.repeat(k.re, k.min-1, k.max-1, j, k.next)
+}
+
+// concat matches the expressions of re from the nth one after the other.
+func (b *backtracker) concat(re *syntax.Regexp, n, i int, k *cont) bool {
+	if n == len(re.Sub) {
+		return b.resume(k, i)
+	}
+	return b.try(re.Sub[n], i, b.cont(cont{op: contConcat, re: re, n: n + 1, next: k}))
+}
+
+// repeat matches re at least min and at most max times, or any number of
+// times if max is negative, trying the most first. Once there are enough,
+// a repetition that matches nothing is not tried again.
+func (b *backtracker) repeat(re *syntax.Regexp, min, max, i int, k *cont) bool {
+	// Counts past what they decide are all the same.
+	if min < 0 {
+		min = 0
+	}
+	if max < 0 {
+		max = -1
+	}
+	if max != 0 {
+		next := b.cont(cont{op: contRepeat, re: re, i: i, min: min, max: max, next: k})
+		if b.try(re, i, next) {
+			return true
+		}
+	}
+	return min <= 0 && b.resume(k, i)
+}
+
+// captured returns the captures that matter to what is left, k, as a
+// string for a state. Only the groups referred to do, and not those k ends,
+// as they are set again before any reference to them.
+func (b *backtracker) captured(k *cont) string {
+	var open []int
+	for ; k != nil; k = k.next {
+		if k.op == contCapture {
+			open = append(open, k.re.Cap)
+		}
+	}
+	var buf []byte
+	for _, c := range b.refCaps {
+		if containsInt(open, c) {
+			continue
+		}
+		buf = strconv.AppendInt(buf, int64(b.at[2*c]), 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(b.at[2*c+1]), 10)
+		buf = append(buf, ';')
+	}
+	return string(buf)
+}
+
+// containsInt reports whether x is in a.
+func containsInt(a []int, x int) bool {
+	for _, y := range a {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRune reports whether r is one of the characters re matches.
+func matchesRune(re *syntax.Regexp, r rune) bool {
+	switch re.Op {
+	case syntax.OpAnyChar:
+		return true
+	case syntax.OpAnyCharNotNL:
+		return r != '\n'
+	}
+	for j := 0; j < len(re.Rune); j += 2 {
+		if re.Rune[j] <= r && r <= re.Rune[j+1] {
+			return true
+		}
+	}
+	return false
+}
+
+// atWordBoundary reports whether i is between a word character and another
+// character or the start or end of the string, as \b of package regexp.
+func (b *backtracker) atWordBoundary(i int) bool {
+	before := i > 0 && syntax.IsWordChar(rune(b.s[i-1]))
+	after := i < len(b.s) && syntax.IsWordChar(rune(b.s[i]))
+	return before != after
+}
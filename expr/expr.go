@@ -4,350 +4,476 @@
 //
 // Written By: Michael Murphy
 //
-// TODO: Implement & and | expressions.
-package main
+package expr
 
-import "flag"
 import "fmt"
-import "math"
-import "os"
-import "strconv"
+import "math/big"
 import "strings"
+import "unicode/utf8"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
 
 const (
 	help_text = `
 	Usage: expr EXPRESSION
 	   or: expr OPTION
-	   
-	-help    display this help and exit
-	
-	-version output version information and exit
-	   
-	Print the value of EXPRESSION to standard output. EXPRESSIONS are listed below:
-	
-	<  - less than
-	
-	<= - less than or equal to
-	
-	=  - equal to
-	
-	=> - greater than or equal to
-	
-	>  - greater than
-	
-	+  - arithmetic sum of two arguments
-	
-	-  - arithmetic difference of two arguments
-	
-	*  - arithmetic product of two arguments
-	
-	/  - arithmetic quotient of two arguments
-	
-	%  - arithmetic remainder after dividing two arguments
-	
-	substr STRING STARTPOS LENGTH
-	      substring of STRING, STARTPOS counted from 1
-	      
-	index STRING CHAR
-	      index in STRING where any CHAR is found, or 0
-	      
-	length STRING
-	      length of STRING
+
+	--help    display this help and exit
+
+	--version output version information and exit
+
+	Print the value of EXPRESSION to standard output. A blank line below
+	separates increasing precedence groups. EXPRESSION may be:
+
+	  ARG1 | ARG2       ARG1 if it is neither null nor 0, otherwise ARG2
+
+	  ARG1 & ARG2       ARG1 if neither argument is null or 0, otherwise 0
+
+	  ARG1 < ARG2       ARG1 is less than ARG2
+	  ARG1 <= ARG2      ARG1 is less than or equal to ARG2
+	  ARG1 = ARG2       ARG1 is equal to ARG2
+	  ARG1 != ARG2      ARG1 is unequal to ARG2
+	  ARG1 >= ARG2      ARG1 is greater than or equal to ARG2
+	  ARG1 > ARG2       ARG1 is greater than ARG2
+
+	  ARG1 + ARG2       arithmetic sum of ARG1 and ARG2
+	  ARG1 - ARG2       arithmetic difference of ARG1 and ARG2
+
+	  ARG1 * ARG2       arithmetic product of ARG1 and ARG2
+	  ARG1 / ARG2       arithmetic quotient of ARG1 divided by ARG2
+	  ARG1 % ARG2       arithmetic remainder of ARG1 divided by ARG2
+
+	  STRING : REGEXP   anchored pattern match of REGEXP in STRING
+
+	  match STRING REGEXP        same as STRING : REGEXP
+	  substr STRING POS LENGTH   substring of STRING, POS counted from 1
+	  index STRING CHARS         index in STRING where any CHARS is found, or 0
+	  length STRING              length of STRING
+	  + TOKEN                    interpret TOKEN as a string, even if it is a
+	                             keyword like 'match' or an operator like '/'
+
+	  ( EXPRESSION )             value of EXPRESSION
+
+	Beware that many operators need to be escaped or quoted for shells.
+	Comparisons are arithmetic if both ARGs are numbers, else lexicographical.
+	Pattern matches return the string matched between \( and \) or null; if
+	\( and \) are not used, they return the number of characters matched or 0.
+
+	Exit status is 0 if EXPRESSION is neither null nor 0, 1 if EXPRESSION is
+	null or 0, 2 if EXPRESSION is syntactically invalid, and 3 if an error
+	occurred.
 `
 	version_text = `
     expr (go-coreutils) 0.1
 
     Copyright (C) 2014, The GO-Coreutils Developers.
     This program comes with ABSOLUTELY NO WARRANTY; for details see
-    LICENSE. This is free software, and you are welcome to redistribute 
+    LICENSE. This is free software, and you are welcome to redistribute
     it under certain conditions in LICENSE.
 `
 )
 
-var (
-	help    = flag.Bool("help", false, "display help information")
-	version = flag.Bool("version", false, "display version information")
-)
+// invalidExpression is raised with panic when the expression cannot be
+// evaluated; Eval recovers it and returns it as an error.
+type invalidExpression string
 
-// Check initial state of flags.
-func processFlags() {
-	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
-	}
-	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
-	}
+func (e invalidExpression) Error() string { return string(e) }
+
+func init() {
+	applet.Register("expr", Main)
 }
 
-// Prints an error indicating that the syntax is wrong and exits.
-func printError() {
-	fmt.Println("expr: syntax error")
-	os.Exit(0)
+// value is the value of an expression, either a string or an integer of
+// any size, when num is not nil.
+type value struct {
+	str string
+	num *big.Int
 }
 
-// Print an error and exit the program if there are no arguments.
-func checkIfNoArgumentsAreGiven() {
-	if flag.NArg() == 0 {
-		fmt.Println("expr: missing operand\nTry 'expr -help' for more information.")
-		os.Exit(0)
+func intValue(n int64) value     { return value{num: big.NewInt(n)} }
+func stringValue(s string) value { return value{str: s} }
+
+func boolValue(b bool) value {
+	if b {
+		return intValue(1)
 	}
+	return intValue(0)
 }
 
-// Returns a slice of value arguments.
-func getValueSlice() []float64 {
-	// This will select every odd argument, which is a number.
-	valueSlice := make([]float64, 0)                   // Create a slice for storing values (numbers).
-	for index := 0; index <= flag.NArg(); index += 2 { // Loop through every odd argument
-		value, _ := strconv.ParseFloat(flag.Arg(index), 64) // Convert the flag argument, which is a string, into float32 with strconv.
-		valueSlice = append(valueSlice, value)              // Append value, which is currently a float64 value, as a float32 value to the value slice.
+func (v value) String() string {
+	if v.num != nil {
+		return v.num.String()
 	}
-	return valueSlice
+	return v.str
 }
 
-// Returns a slice of modifier arguments.
-func getModifierSlice() []string {
-	// This will select every even argument, which is a modifier (+.-,/,*)
-	modifierSlice := make([]string, 0)                // Create a slice for storing modifiers.
-	for index := 1; index < flag.NArg(); index += 2 { // Loop through every even argument
-		modifierSlice = append(modifierSlice, flag.Arg(index)) // Append the modifier to the modifier slice.
+// null reports whether v is the empty string or zero, which are false. A
+// string of zeros is zero, whether or not it has a minus sign.
+func (v value) null() bool {
+	if v.num != nil {
+		return v.num.Sign() == 0
 	}
-	return modifierSlice
+	digits := strings.TrimPrefix(v.str, "-")
+	return v.str == "" || digits != "" && strings.Trim(digits, "0") == ""
+}
+
+// looksLikeInteger reports whether s is an optional minus sign followed by
+// decimal digits, the only strings taken for integers.
+func looksLikeInteger(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return s != "" && strings.Trim(s, "0123456789") == ""
 }
 
-// Return true if the current modifier is an inequality symbol.
-func isInequalitySymbol(test string) bool {
-	var isBool bool = false
-	switch test {
-	case "<", "<=", "=", "=>", ">", "!=":
-		isBool = true
+// toInt returns v as an integer, or false if it is a string that is not one.
+func toInt(v value) (*big.Int, bool) {
+	if v.num != nil {
+		return v.num, true
 	}
-	return isBool
+	if !looksLikeInteger(v.str) {
+		return nil, false
+	}
+	return new(big.Int).SetString(v.str, 10)
+}
+
+// parser evaluates the operands of expr by recursive descent, from the
+// operator of lowest precedence, |, to the parenthesized expressions. When
+// evaluate is false, it only parses, as for the operand of | or & that
+// does not decide the value, so that it may divide by zero or hold a
+// string where an integer is needed.
+type parser struct {
+	args []string
+	pos  int
 }
 
-// Checks if the number can be an integer for calculating the modulus.
-func floatIsInteger(number float64) bool {
-	// If the float can be truncated into an int, return true.
-	if math.Trunc(number) == number {
+// more reports whether any operand is left.
+func (p *parser) more() bool {
+	return p.pos < len(p.args)
+}
+
+// next consumes the next operand if it is s.
+func (p *parser) next(s string) bool {
+	if p.more() && p.args[p.pos] == s {
+		p.pos++
 		return true
 	}
-
 	return false
 }
 
-// Checks if the numbers can be modulated, and if so returns the modulated value.
-func calculateModulus(original, current float64) float64 {
-	var result float64
-
-	// Check if the numbers can be modulated
-	if floatIsInteger(original) && floatIsInteger(current) {
-		result = float64(int64(original) % int64(current))
-	} else {
-		fmt.Println("expr: non-integer argument")
-		os.Exit(0)
+// nextOf consumes the next operand if it is one of ops, and returns it.
+func (p *parser) nextOf(ops ...string) (string, bool) {
+	for _, op := range ops {
+		if p.next(op) {
+			return op, true
+		}
 	}
+	return "", false
+}
 
-	return result
+// syntaxError aborts the evaluation with a syntax error.
+func syntaxError(format string, a ...interface{}) {
+	panic(invalidExpression("syntax error: " + fmt.Sprintf(format, a...)))
 }
 
-// Return 1 if true, 0 if false.
-func booleanToFloat(boolean bool) float64 {
-	if boolean {
-		return 1
-	} else {
-		return 0
+// or evaluates ARG1 | ARG2.
+func (p *parser) or(evaluate bool) value {
+	l := p.and(evaluate)
+	for p.next("|") {
+		r := p.and(evaluate && l.null())
+		if evaluate && l.null() {
+			l = r
+			if r.null() {
+				l = intValue(0)
+			}
+		}
 	}
+	return l
 }
 
-// The initial result in an expression needs to be calculated differently than the rest.
-func calculateInitialResult(firstNum, secondNum float64, modifier string) float64 {
-	var result float64
-	switch modifier {
-	case "+":
-		result = firstNum + secondNum
-	case "-":
-		result = firstNum - secondNum
-	case "*":
-		result = firstNum * secondNum
-	case "/":
-		result = firstNum / secondNum
-	case "%":
-		result = calculateModulus(firstNum, secondNum)
-	default:
-		printError()
+// and evaluates ARG1 & ARG2.
+func (p *parser) and(evaluate bool) value {
+	l := p.compare(evaluate)
+	for p.next("&") {
+		r := p.compare(evaluate && !l.null())
+		if evaluate && (l.null() || r.null()) {
+			l = intValue(0)
+		}
 	}
-	return result
+	return l
 }
 
-// Calculate the range of numbers to calculate between expressions.
-func calculateExpressionRanges(valueSlice []float64, modifierSlice []string) ([]int, int) {
-	var currentRange, equalityCount int = 0, 0
-	expressionRanges := make([]int, 0)
-
-	// Loop through each modifier and check if it is an inequality symbol, then append the range.
-	for _, modifier := range modifierSlice {
-		if isInequalitySymbol(modifier) {
-			equalityCount++
-			expressionRanges = append(expressionRanges, currentRange+1)
-			currentRange = 0
+// compare evaluates the comparisons, which compare integers if both
+// operands are integers and strings otherwise.
+func (p *parser) compare(evaluate bool) value {
+	l := p.sum(evaluate)
+	for {
+		op, ok := p.nextOf("<", "<=", "=", "==", "!=", ">=", ">")
+		if !ok {
+			return l
+		}
+		r := p.sum(evaluate)
+		if !evaluate {
+			continue
+		}
+		var cmp int
+		a, aok := toInt(l)
+		b, bok := toInt(r)
+		if aok && bok {
+			cmp = a.Cmp(b)
 		} else {
-			currentRange++
+			cmp = strings.Compare(l.String(), r.String())
+		}
+		switch op {
+		case "<":
+			l = boolValue(cmp < 0)
+		case "<=":
+			l = boolValue(cmp <= 0)
+		case "=", "==":
+			l = boolValue(cmp == 0)
+		case "!=":
+			l = boolValue(cmp != 0)
+		case ">=":
+			l = boolValue(cmp >= 0)
+		case ">":
+			l = boolValue(cmp > 0)
 		}
 	}
-
-	// The above loop will exit before appending the last range, so this will append it.
-	currentRange++
-	expressionRanges = append(expressionRanges, currentRange)
-
-	return expressionRanges, equalityCount
 }
 
-// Calculate each result between inequality expressions
-func calculateExpressions(valueSlice []float64, modifierSlice []string, expressionRanges []int) []float64 {
-	results := make([]float64, 0)
-	var result float64
-	var position int = 0
-
-	for _, currentRange := range expressionRanges {
-		if currentRange == 1 {
-			result = valueSlice[position]
-		} else if currentRange == 2 {
-			result = calculateInitialResult(valueSlice[position], valueSlice[position+1], modifierSlice[position])
-		} else if currentRange > 2 {
-			result = calculateInitialResult(valueSlice[position], valueSlice[position+1], modifierSlice[position])
-			for index := position; index < currentRange+position-2; index++ {
-				switch modifierSlice[index+1] {
-				case "+":
-					result += valueSlice[index+2]
-				case "-":
-					result -= valueSlice[index+2]
-				case "*":
-					result *= valueSlice[index+2]
-				case "/":
-					result /= valueSlice[index+2]
-				case "%":
-					result = calculateModulus(result, valueSlice[index+2])
-				default:
-					printError()
-				}
-			}
+// sum evaluates ARG1 + ARG2 and ARG1 - ARG2.
+func (p *parser) sum(evaluate bool) value {
+	l := p.product(evaluate)
+	for {
+		op, ok := p.nextOf("+", "-")
+		if !ok {
+			return l
+		}
+		r := p.product(evaluate)
+		if evaluate {
+			l = arithmetic(op, l, r)
 		}
-		results = append(results, result)
-		position = position + currentRange
 	}
-
-	return results
 }
 
-// Returns 1 for true, 0 for false, or any
-func calculateInequalities(results []float64, expressionRanges []int, boolCount int, modifierSlice []string) float64 {
-	currentValue := results[0]
+// product evaluates ARG1 * ARG2, ARG1 / ARG2 and ARG1 % ARG2.
+func (p *parser) product(evaluate bool) value {
+	l := p.colon(evaluate)
+	for {
+		op, ok := p.nextOf("*", "/", "%")
+		if !ok {
+			return l
+		}
+		r := p.colon(evaluate)
+		if evaluate {
+			l = arithmetic(op, l, r)
+		}
+	}
+}
 
-	for index := 0; index < boolCount; index++ {
-		currentModifier := modifierSlice[expressionRanges[index]-1]
-		nextValue := results[index+1]
+// arithmetic applies the operator op to two integers. Quotients are
+// truncated toward zero, and remainders have the sign of the dividend.
+func arithmetic(op string, l, r value) value {
+	a, aok := toInt(l)
+	b, bok := toInt(r)
+	if !aok || !bok {
+		panic(invalidExpression("non-integer argument"))
+	}
+	if (op == "/" || op == "%") && b.Sign() == 0 {
+		panic(invalidExpression("division by zero"))
+	}
+	n := new(big.Int)
+	switch op {
+	case "+":
+		n.Add(a, b)
+	case "-":
+		n.Sub(a, b)
+	case "*":
+		n.Mul(a, b)
+	case "/":
+		n.Quo(a, b)
+	case "%":
+		n.Rem(a, b)
+	}
+	return value{num: n}
+}
 
-		switch currentModifier {
-		case "<":
-			currentValue = booleanToFloat(currentValue < nextValue)
-		case "<=":
-			currentValue = booleanToFloat(currentValue <= nextValue)
-		case "=":
-			currentValue = booleanToFloat(currentValue == nextValue)
-		case "=>":
-			currentValue = booleanToFloat(currentValue <= nextValue)
-		case ">":
-			currentValue = booleanToFloat(currentValue > nextValue)
-		case "!=":
-			currentValue = booleanToFloat(currentValue != nextValue)
+// colon evaluates STRING : REGEXP.
+func (p *parser) colon(evaluate bool) value {
+	l := p.keyword(evaluate)
+	for p.next(":") {
+		r := p.keyword(evaluate)
+		if evaluate {
+			l = match(l, r)
 		}
 	}
-
-	return currentValue
+	return l
 }
 
-// Performs arithmetic calculations
-func calculateArithmetic(valueSlice []float64, modifierSlice []string) {
-	expressionRanges, boolCount := calculateExpressionRanges(valueSlice, modifierSlice)
+// match matches the pattern r at the start of l.
+func match(l, r value) value {
+	pat, err := compilePattern(r.String())
+	if err != nil {
+		panic(err)
+	}
+	return pat.match(l.String())
+}
 
-	// Calculate the totals between expressions
-	results := calculateExpressions(valueSlice, modifierSlice, expressionRanges)
+// keyword evaluates the functions match, substr, index and length, and
+// + TOKEN, which stands for TOKEN even if it is a keyword or an operator.
+func (p *parser) keyword(evaluate bool) value {
+	switch {
+	case p.next("+"):
+		if !p.more() {
+			syntaxError("missing argument after %s", diag.Quote("+"))
+		}
+		p.pos++
+		return stringValue(p.args[p.pos-1])
+	case p.next("length"):
+		s := p.keyword(evaluate)
+		return intValue(int64(utf8.RuneCountInString(s.String())))
+	case p.next("match"):
+		l := p.keyword(evaluate)
+		r := p.keyword(evaluate)
+		if evaluate {
+			return match(l, r)
+		}
+		return l
+	case p.next("index"):
+		s := p.keyword(evaluate)
+		chars := p.keyword(evaluate)
+		return intValue(index(s.String(), chars.String()))
+	case p.next("substr"):
+		s := p.keyword(evaluate)
+		pos := p.keyword(evaluate)
+		length := p.keyword(evaluate)
+		if !evaluate {
+			return s
+		}
+		return stringValue(substr(s.String(), pos, length))
+	}
+	return p.primary(evaluate)
+}
 
-	// Calculate inequality expressions between results or print result.
-	if boolCount != 0 {
-		fmt.Println(calculateInequalities(results, expressionRanges, boolCount, modifierSlice))
-	} else {
-		fmt.Println(results[0])
+// index returns the position of the first character of s that is in
+// chars, counted from 1, or 0.
+func index(s, chars string) int64 {
+	pos := int64(0)
+	for _, c := range s {
+		pos++
+		if strings.ContainsRune(chars, c) {
+			return pos
+		}
 	}
+	return 0
 }
 
-// Returns the length of the string
-func getStringLength() int {
-	return len(flag.Arg(1))
+// substr returns the length characters of s from the one at pos, counted
+// from 1, or "" if either is not a positive integer or pos is beyond s.
+func substr(s string, pos, length value) string {
+	chars := []rune(s)
+	p, pok := toInt(pos)
+	n, nok := toInt(length)
+	if !pok || !nok || p.Sign() <= 0 || n.Sign() <= 0 || p.Cmp(big.NewInt(int64(len(chars)))) > 0 {
+		return ""
+	}
+	start := int(p.Int64()) - 1
+	end := len(chars)
+	if n.Cmp(big.NewInt(int64(end-start))) < 0 {
+		end = start + int(n.Int64())
+	}
+	return string(chars[start:end])
 }
 
-// Returns the index value of the position of the first occurence of a character.
-func getCharacterIndex() int {
-	return strings.IndexByte(flag.Arg(1), flag.Arg(2)[0]) + 1
+// primary evaluates ( EXPRESSION ) or an operand.
+func (p *parser) primary(evaluate bool) value {
+	if !p.more() {
+		syntaxError("missing argument after %s", diag.Quote(p.args[p.pos-1]))
+	}
+	switch {
+	case p.next("("):
+		v := p.or(evaluate)
+		if !p.more() {
+			syntaxError("expecting ')' after %s", diag.Quote(p.args[p.pos-1]))
+		}
+		if !p.next(")") {
+			syntaxError("expecting ')' instead of %s", diag.Quote(p.args[p.pos]))
+		}
+		return v
+	case p.next(")"):
+		syntaxError("unexpected ')'")
+	}
+	p.pos++
+	return stringValue(p.args[p.pos-1])
 }
 
-// Returns a substring containing only the characters in the input range.
-func getSubstring() string {
-	inputString := flag.Arg(1)
-	start, starterr := strconv.Atoi(flag.Arg(2))
-	end, enderr := strconv.Atoi(flag.Arg(3))
+// Eval evaluates the expression given by args, as expr does with its
+// operands, and returns the result.
+func Eval(args []string) (result string, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			msg, ok := e.(invalidExpression)
+			if !ok {
+				panic(e)
+			}
+			err = msg
+		}
+	}()
 
-	// Check for errors in syntax
-	if starterr != nil || enderr != nil {
-		printError()
+	if len(args) == 0 {
+		return "", invalidExpression("missing operand")
 	}
-
-	// In case the user sets a length beyond the slice size,
-	// set end to the length of the input string.
-	if end > len(inputString) {
-		end = len(inputString)
+	p := parser{args: args}
+	v := p.or(true)
+	if p.more() {
+		syntaxError("unexpected argument %s", diag.Quote(p.args[p.pos]))
 	}
+	return v.String(), nil
+}
 
-	return inputString[start-1 : end]
+// exitStatus returns the exit status for a result: 1 if it is null or 0.
+func exitStatus(result string) int {
+	if stringValue(result).null() {
+		return 1
+	}
+	return 0
 }
 
-func main() {
-	flag.Parse()
-	processFlags()
+// Main runs expr with the given arguments and returns its exit status: 0 if
+// the expression is neither null nor 0, 1 if it is, 2 if it is invalid and
+// 3 if its value cannot be written.
+func Main(args []string) int {
+	report := diag.New("expr")
+	report.FailStatus = 2
+	report.UsageStatus = 2
+
+	if len(args) == 1 {
+		switch args[0] {
+		case "--help":
+			fmt.Print(help_text)
+			return 0
+		case "--version":
+			fmt.Print(version_text)
+			return 0
+		}
+	}
+	if len(args) > 0 && args[0] == "--" {
+		args = args[1:]
+	}
 
 	// If there are no arguments, print an error and exit.
-	checkIfNoArgumentsAreGiven()
-
-	// Check if length is the first argument
-	switch flag.Arg(0) {
-	case "match":
-		//TODO
-		fmt.Println("not implemented")
-		os.Exit(0)
-	case "substr":
-		fmt.Println(getSubstring())
-		os.Exit(0)
-	case "index":
-		fmt.Println(getCharacterIndex())
-		os.Exit(0)
-	case "length":
-		fmt.Println(getStringLength())
-		os.Exit(0)
-	case "+":
-		//TODO
-		fmt.Println("not implemented")
+	if len(args) == 0 {
+		return report.Usagef("missing operand")
 	}
 
-	// Obtain value and modifier slices
-	valueSlice := getValueSlice()
-	modifierSlice := getModifierSlice()
-
-	// Determine how to process the arguments
-	switch modifierSlice[0] {
-	case "+", "-", "*", "/", "%", "<", "<=", "=", "=>", ">", "!=":
-		calculateArithmetic(valueSlice, modifierSlice)
-	default:
-		printError()
+	result, err := Eval(args)
+	if err != nil {
+		report.Errorf("%s", err)
+		return report.Status()
+	}
+	if _, err := fmt.Println(result); err != nil {
+		report.Errorf("write error: %s", diag.Strerror(err))
+		report.Fail(3)
+		return report.Status()
 	}
+	return exitStatus(result)
 }
@@ -0,0 +1,107 @@
+//
+// expr_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package expr
+
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "expr", []conformance.Case{
+		{Name: "add", Args: []string{"1", "+", "2"}},
+		{Name: "subtract", Args: []string{"5", "-", "7"}},
+		{Name: "multiply", Args: []string{"6", "*", "7"}},
+		{Name: "divide", Args: []string{"7", "/", "2"}},
+		{Name: "modulus", Args: []string{"7", "%", "3"}},
+		{Name: "zero-result", Args: []string{"2", "-", "2"}},
+		{Name: "compare", Args: []string{"1", "<", "2"}},
+		{Name: "compare-false", Args: []string{"3", "<", "2"}},
+		{Name: "single", Args: []string{"hello"}},
+		{Name: "length", Args: []string{"length", "hello"}},
+		{Name: "index", Args: []string{"index", "hello", "l"}},
+		{Name: "substr", Args: []string{"substr", "hello", "2", "3"}},
+		{Name: "division-by-zero", Args: []string{"1", "/", "0"}},
+		{Name: "non-integer", Args: []string{"a", "+", "1"}},
+		{Name: "syntax-error", Args: []string{"1", "2"}},
+		{Name: "missing-operand"},
+		{Name: "negative", Args: []string{"--", "-1", "+", "1"}},
+		{Name: "big-product", Args: []string{"99999999999999999999", "*", "99999999999999999999"}},
+		{Name: "big-quotient", Args: []string{"--", "-99999999999999999999", "/", "7"}},
+		{Name: "big-remainder", Args: []string{"100000000000000000000", "%", "7"}},
+		{Name: "big-compare", Args: []string{"99999999999999999999", "=", "099999999999999999999"}},
+		{Name: "big-division-by-zero", Args: []string{"99999999999999999999", "%", "0"}},
+		{Name: "precedence", Args: []string{"2", "+", "3", "*", "4"}},
+		{Name: "left-to-right", Args: []string{"8", "/", "2", "/", "2"}},
+		{Name: "parentheses", Args: []string{"(", "2", "+", "3", ")", "*", "4"}},
+		{Name: "or", Args: []string{"", "|", "b"}},
+		{Name: "or-null", Args: []string{"0", "|", ""}},
+		{Name: "or-short-circuit", Args: []string{"1", "|", "1", "/", "0"}},
+		{Name: "and", Args: []string{"a", "&", "b"}},
+		{Name: "and-short-circuit", Args: []string{"0", "&", "1", "/", "0"}},
+		{Name: "compare-numbers", Args: []string{"10", ">", "9"}},
+		{Name: "compare-strings", Args: []string{"10", ">", "9a"}},
+		{Name: "compare-leading-zero", Args: []string{"01", "=", "1"}},
+		{Name: "compare-ge", Args: []string{"3", ">=", "3"}},
+		{Name: "not-an-operator", Args: []string{"3", "=>", "2"}},
+		{Name: "null-string", Args: []string{"-00"}},
+		{Name: "match-length", Args: []string{"abc", ":", "a.*"}},
+		{Name: "match-group", Args: []string{"abc", ":", `a\(.\)`}},
+		{Name: "match-none", Args: []string{"abc", ":", "b"}},
+		{Name: "match-group-none", Args: []string{"abc", ":", `x\(.\)`}},
+		{Name: "match-keyword", Args: []string{"match", "abc", "ab"}},
+		{Name: "match-anchors", Args: []string{"ab$", ":", "ab$"}},
+		{Name: "match-literal-star", Args: []string{"*a", ":", "*a"}},
+		{Name: "match-interval", Args: []string{"aaaa", ":", `a\{1,3\}`}},
+		{Name: "match-alternation", Args: []string{"ab", ":", `a\|ab`}},
+		{Name: "match-bracket", Args: []string{"a]b", ":", `[]a]*`}},
+		{Name: "match-class", Args: []string{"abc1", ":", "[[:alpha:]]*"}},
+		{Name: "match-unmatched-paren", Args: []string{"abc", ":", `\(a`}},
+		{Name: "match-unmatched-bracket", Args: []string{"abc", ":", "[a"}},
+		{Name: "match-bad-interval", Args: []string{"abc", ":", `a\{2,1\}`}},
+		{Name: "match-backreference", Args: []string{"aa", ":", `\(a\)\1`}},
+		{Name: "match-backreference-prefix", Args: []string{"aabc", ":", `\(a\)\1`}},
+		{Name: "match-backreference-longest", Args: []string{"abcdbcdx", ":", `a\(.*\)\1`}},
+		{Name: "match-backreference-repeated", Args: []string{"xyxyxy", ":", `\(xy\)*\1`}},
+		{Name: "match-backreference-none", Args: []string{"abab", ":", `\(a\)\(b\)\2`}},
+		{Name: "match-backreference-unset", Args: []string{"aab", ":", `a\(b\)*\1`}},
+		{Name: "match-backreference-invalid", Args: []string{"a", ":", `\(a\)\2`}},
+		{Name: "match-backreference-other-alternative", Args: []string{"ab", ":", `\(a\)\|b\1`}},
+		{Name: "match-backreference-nested-star", Args: []string{"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa!", ":", `\(a*\)*\1b`}},
+		{Name: "quote-keyword", Args: []string{"+", "length"}},
+		{Name: "length-of-keyword", Args: []string{"length", "+", "index"}},
+		{Name: "substr-out-of-range", Args: []string{"substr", "hello", "0", "2"}},
+		{Name: "substr-long", Args: []string{"substr", "hello", "2", "99"}},
+		{Name: "index-chars", Args: []string{"index", "hello", "ol"}},
+		{Name: "remainder-negative", Args: []string{"--", "-7", "%", "3"}},
+		{Name: "missing-argument", Args: []string{"1", "+"}},
+		{Name: "missing-paren", Args: []string{"(", "1"}},
+		{Name: "extra-argument-in-paren", Args: []string{"(", "1", "2", ")"}},
+		{Name: "unexpected-paren", Args: []string{")"}},
+		{Name: "missing-keyword-argument", Args: []string{"length"}},
+	})
+}
+
+// A repetition of a repetition can match a string in exponentially many
+// ways, which the backtracker must not all try.
+func TestBackreferenceTime(t *testing.T) {
+	pat, err := compilePattern(`\(a*\)*\1b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan string)
+	go func() { done <- pat.match(strings.Repeat("a", 200) + "!").String() }()
+	select {
+	case got := <-done:
+		if got != "" {
+			t.Errorf("got %q, want no match", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("matching 200 a's took more than 10s")
+	}
+}
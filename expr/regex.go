@@ -0,0 +1,421 @@
+//
+// regex.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package expr
+
+import "regexp"
+import "regexp/syntax"
+import "strconv"
+import "strings"
+
+// The messages of the GNU regex library for the errors in a pattern.
+const (
+	errBrack     = "Unmatched [, [^, [:, [., or [="
+	errParen     = "Unmatched ( or \\("
+	errRParen    = "Unmatched ) or \\)"
+	errBrace     = "Unmatched \\{"
+	errBadBrace  = "Invalid content of \\{\\}"
+	errBackslash = "Trailing backslash"
+	errCtype     = "Invalid character class name"
+	errCollate   = "Invalid collation character"
+	errSubreg    = "Invalid back reference"
+	errSize      = "Regular expression too big"
+)
+
+// dupMax is the largest count of an interval, RE_DUP_MAX.
+const dupMax = 32767
+
+// A pattern is a basic regular expression compiled for the : operator,
+// which matches it at the start of a string.
+type pattern struct {
+	re     *regexp.Regexp
+	groups int // the number of \( \) groups
+
+	// A pattern with back-references, which package regexp cannot match,
+	// is matched by a backtracker instead.
+	backrefs *backtracker
+}
+
+// compilePattern compiles a POSIX basic regular expression with the GNU
+// extensions: \| for alternation, \+ and \? for one or more and zero or
+// one, and \w, \W, \s, \S, \b, \B, \<, \>, \` and \' for words, spaces and
+// boundaries, and the back-references \1 to \9.
+func compilePattern(s string) (*pattern, error) {
+	t := translator{src: s}
+	if err := t.translate(); err != nil {
+		return nil, err
+	}
+	expr := `(?s)^(?:` + t.out.String() + `)`
+	if len(t.refs) > 0 {
+		re, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			return nil, invalidExpression(errSize)
+		}
+		return &pattern{groups: t.groups, backrefs: newBacktracker(re, t.refs)}, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		// The translation is valid but for the counts of intervals above
+		// what package regexp allows.
+		return nil, invalidExpression(errSize)
+	}
+	re.Longest()
+	return &pattern{re: re, groups: t.groups}, nil
+}
+
+// match matches the pattern at the start of s. It returns the text of the
+// first group, or "" if it did not match, for a pattern with groups, and
+// the number of characters matched otherwise.
+func (p *pattern) match(s string) value {
+	var m []int
+	if p.backrefs != nil {
+		m = p.backrefs.match(s)
+	} else {
+		m = p.re.FindStringSubmatchIndex(s)
+	}
+	switch {
+	case p.groups > 0 && (m == nil || m[2] < 0):
+		return stringValue("")
+	case p.groups > 0:
+		return stringValue(s[m[2]:m[3]])
+	case m == nil:
+		return intValue(0)
+	}
+	return intValue(int64(len([]rune(s[:m[1]]))))
+}
+
+// group is a \( \) group being translated.
+type group struct {
+	n     int // its number, counted from 1
+	start int // where it starts in the output
+
+	// The alternatives of the expression or group it is in.
+	outer alternatives
+}
+
+// alternatives tracks the groups that a back-reference may name in the
+// alternatives of an expression or group: as in GNU, those closed before it
+// in the same alternative or before the expression or group started. They
+// are sets of group numbers up to 9.
+type alternatives struct {
+	start  uint16 // the groups closed when it started
+	closed uint16 // the groups closed in the alternatives before this one
+}
+
+// translator rewrites a basic regular expression in the syntax of package
+// regexp.
+type translator struct {
+	src    string
+	i      int
+	out    strings.Builder
+	groups int
+	open   []group // the groups not yet closed
+	refs   []int   // the group each back-reference names, in order
+
+	alts   alternatives // of the innermost group, or of the expression
+	closed uint16       // the groups that a back-reference may name
+
+	// atom is where the last thing that a * may repeat starts in out, or -1
+	// where a * is an ordinary character, at the start of the expression,
+	// of a group or of an alternative.
+	atom     int
+	repeated bool // the last atom already has a repetition
+}
+
+func (t *translator) translate() error {
+	t.atom = -1
+	for t.i < len(t.src) {
+		c := t.src[t.i]
+		t.i++
+		switch c {
+		case '\\':
+			if t.i == len(t.src) {
+				return invalidExpression(errBackslash)
+			}
+			if err := t.escape(); err != nil {
+				return err
+			}
+		case '[':
+			start := t.out.Len()
+			if err := t.bracket(); err != nil {
+				return err
+			}
+			t.atomAt(start)
+		case '*':
+			if t.atom < 0 {
+				t.literal("*")
+			} else {
+				t.repeat("*")
+			}
+		case '^':
+			if t.atom < 0 && t.afterStart() {
+				t.out.WriteString("^")
+			} else {
+				t.literal("^")
+			}
+		case '$':
+			if t.atEnd() {
+				t.out.WriteString("$")
+				t.atom = -1
+			} else {
+				t.literal("$")
+			}
+		case '.':
+			t.atomAt(t.out.Len())
+			t.out.WriteString(".")
+		default:
+			t.literal(t.src[t.i-1 : t.i])
+		}
+	}
+	if len(t.open) > 0 {
+		return invalidExpression(errParen)
+	}
+	return nil
+}
+
+// afterStart reports whether a ^ just read follows the start of the
+// expression, of a group or of an alternative, where it is an anchor.
+func (t *translator) afterStart() bool {
+	before := t.src[:t.i-1]
+	return before == "" || strings.HasSuffix(before, `\(`) || strings.HasSuffix(before, `\|`)
+}
+
+// atEnd reports whether a $ just read ends the expression, a group or an
+// alternative, where it is an anchor.
+func (t *translator) atEnd() bool {
+	rest := t.src[t.i:]
+	return rest == "" || strings.HasPrefix(rest, `\)`) || strings.HasPrefix(rest, `\|`)
+}
+
+// atomAt records that an atom starts at start in the output.
+func (t *translator) atomAt(start int) {
+	t.atom = start
+	t.repeated = false
+}
+
+// literal writes a character that matches itself.
+func (t *translator) literal(s string) {
+	t.atomAt(t.out.Len())
+	t.out.WriteString(regexp.QuoteMeta(s))
+}
+
+// repeat applies a repetition to the last atom. As package regexp rejects
+// a repetition of a repetition, such as a**, the first is made a group.
+func (t *translator) repeat(op string) {
+	if t.repeated {
+		s := t.out.String()
+		t.out.Reset()
+		t.out.WriteString(s[:t.atom] + "(?:" + s[t.atom:] + ")")
+	}
+	t.out.WriteString(op)
+	t.repeated = true
+}
+
+// escape translates the character after a backslash.
+func (t *translator) escape() error {
+	c := t.src[t.i]
+	t.i++
+	switch c {
+	case '(':
+		t.groups++
+		t.open = append(t.open, group{t.groups, t.out.Len(), t.alts})
+		t.alts = alternatives{start: t.closed}
+		t.out.WriteString("(")
+		t.atom = -1
+	case ')':
+		if len(t.open) == 0 {
+			return invalidExpression(errRParen)
+		}
+		// The group as a whole is the atom a repetition applies to.
+		g := t.open[len(t.open)-1]
+		t.open = t.open[:len(t.open)-1]
+		t.closed |= t.alts.closed
+		t.alts = g.outer
+		if g.n <= 9 {
+			t.closed |= 1 << g.n
+		}
+		t.out.WriteString(")")
+		t.atomAt(g.start)
+	case '|':
+		t.alts.closed |= t.closed
+		t.closed = t.alts.start
+		t.out.WriteString("|")
+		t.atom = -1
+	case '{':
+		if t.atom < 0 {
+			t.literal("{")
+			return nil
+		}
+		return t.interval()
+	case '+', '?':
+		if t.atom < 0 {
+			t.literal(string(c))
+		} else {
+			t.repeat(string(c))
+		}
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		n := int(c - '0')
+		if t.closed&(1<<n) == 0 {
+			return invalidExpression(errSubreg)
+		}
+		// An empty named group stands for the back-reference, for the
+		// backtracker to find.
+		t.atomAt(t.out.Len())
+		t.out.WriteString("(?P<" + backrefName(len(t.refs)) + ">)")
+		t.refs = append(t.refs, n)
+	case 'w', 'W', 's', 'S':
+		t.atomAt(t.out.Len())
+		t.out.WriteString(`\` + string(c))
+	case 'b', 'B':
+		t.out.WriteString(`\` + string(c))
+	case '<', '>':
+		t.out.WriteString(`\b`)
+	case '`':
+		t.out.WriteString(`\A`)
+	case '\'':
+		t.out.WriteString(`\z`)
+	default:
+		t.literal(string(c))
+	}
+	return nil
+}
+
+// interval translates \{M\}, \{M,\} or \{M,N\}, once \{ has been read.
+func (t *translator) interval() error {
+	end := strings.Index(t.src[t.i:], `\}`)
+	if end < 0 {
+		return invalidExpression(errBrace)
+	}
+	body := t.src[t.i : t.i+end]
+	t.i += end + 2
+	lo, hi, found := strings.Cut(body, ",")
+	if lo == "" && found {
+		lo = "0"
+	}
+	least, err := strconv.Atoi(lo)
+	if err != nil || least < 0 || strings.HasPrefix(lo, "+") {
+		return invalidExpression(errBadBrace)
+	}
+	most := least
+	if found {
+		most = -1
+		if hi != "" {
+			if most, err = strconv.Atoi(hi); err != nil || most < least || strings.HasPrefix(hi, "+") {
+				return invalidExpression(errBadBrace)
+			}
+		}
+	}
+	if least > dupMax || most > dupMax {
+		return invalidExpression(errSize)
+	}
+	switch {
+	case !found:
+		t.repeat("{" + lo + "}")
+	case most < 0:
+		t.repeat("{" + lo + ",}")
+	default:
+		t.repeat("{" + lo + "," + hi + "}")
+	}
+	return nil
+}
+
+// classes are the names allowed in [:name:].
+var classes = map[string]bool{
+	"alnum": true, "alpha": true, "blank": true, "cntrl": true, "digit": true, "graph": true,
+	"lower": true, "print": true, "punct": true, "space": true, "upper": true, "xdigit": true,
+}
+
+// bracket translates a bracket expression, once [ has been read. Unlike in
+// package regexp, a backslash in one is an ordinary character, and a range
+// whose end comes before its start is empty.
+func (t *translator) bracket() error {
+	negate := false
+	if t.i < len(t.src) && t.src[t.i] == '^' {
+		negate = true
+		t.i++
+	}
+	var items []string
+	first := true
+	for {
+		if t.i >= len(t.src) {
+			return invalidExpression(errBrack)
+		}
+		if t.src[t.i] == ']' && !first {
+			t.i++
+			break
+		}
+		first = false
+		if strings.HasPrefix(t.src[t.i:], "[:") {
+			end := strings.Index(t.src[t.i+2:], ":]")
+			if end < 0 {
+				return invalidExpression(errBrack)
+			}
+			name := t.src[t.i+2 : t.i+2+end]
+			if !classes[name] {
+				return invalidExpression(errCtype)
+			}
+			items = append(items, "[:"+name+":]")
+			t.i += end + 4
+			continue
+		}
+		lo, err := t.bracketChar()
+		if err != nil {
+			return err
+		}
+		if t.i+1 < len(t.src) && t.src[t.i] == '-' && t.src[t.i+1] != ']' {
+			t.i++
+			hi, err := t.bracketChar()
+			if err != nil {
+				return err
+			}
+			if lo <= hi {
+				items = append(items, quoteClassChar(lo)+"-"+quoteClassChar(hi))
+			}
+			continue
+		}
+		items = append(items, quoteClassChar(lo))
+	}
+	switch {
+	case len(items) == 0 && negate:
+		t.out.WriteString(`[\x00-\x{10FFFF}]`)
+	case len(items) == 0:
+		t.out.WriteString(`[^\x00-\x{10FFFF}]`)
+	case negate:
+		t.out.WriteString("[^" + strings.Join(items, "") + "]")
+	default:
+		t.out.WriteString("[" + strings.Join(items, "") + "]")
+	}
+	return nil
+}
+
+// bracketChar reads one character of a bracket expression, which may be a
+// collating symbol [.c.] or an equivalence class [=c=] of one character.
+func (t *translator) bracketChar() (rune, error) {
+	rest := t.src[t.i:]
+	if strings.HasPrefix(rest, "[.") || strings.HasPrefix(rest, "[=") {
+		delim := rest[1:2] + "]"
+		end := strings.Index(rest[2:], delim)
+		if end < 0 {
+			return 0, invalidExpression(errBrack)
+		}
+		r := []rune(rest[2 : 2+end])
+		if len(r) != 1 {
+			return 0, invalidExpression(errCollate)
+		}
+		t.i += end + 4
+		return r[0], nil
+	}
+	r := []rune(rest)[0]
+	t.i += len(string(r))
+	return r, nil
+}
+
+// quoteClassChar quotes a character for a class of package regexp.
+func quoteClassChar(r rune) string {
+	if strings.ContainsRune(`\[]^-`, r) {
+		return `\` + string(r)
+	}
+	return string(r)
+}
@@ -4,24 +4,33 @@
 //
 // Written By: Michael Murphy
 //
-package main
+package factor
 
+import "bufio"
 import "bytes"
-import "flag"
 import "fmt"
-import "strconv"
+import "io"
+import "math/big"
 import "os"
+import "sort"
+import "strings"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
 
 const (
 	help_text = `
-    Usage: factor [NUMBER]...
+    Usage: factor [OPTION] [NUMBER]...
     
     Print the prime factors of each specified integer number. If none are
-    specified on the command line, read them from standard output.
+    specified on the command line, read them from standard input.
+    
+    -h, --exponents print repeated factors in form p^e unless e is 1
     
-    -help display this help and exit
+    --help display this help and exit
     
-    -version output version information and exit
+    --version output version information and exit
 `
 	version_text = `
     factor (go-coreutils) 0.1
@@ -33,89 +42,204 @@ const (
 `
 )
 
-var (
-	help    = flag.Bool("help", false, "display help information")
-	version = flag.Bool("version", false, "display version information")
-)
+func init() {
+	applet.Register("factor", Main)
+}
 
-type factorList []int
+// FactorList is a list of prime factors in ascending order.
+type FactorList []*big.Int
 
-// toString returns the factorList as a string.
-func (numbers *factorList) toString() string {
+// String returns the factors separated by spaces.
+func (numbers FactorList) String() string {
 	var buffer bytes.Buffer
-	for _, number := range *numbers {
-		buffer.WriteString(" " + strconv.Itoa(number))
+	for index, number := range numbers {
+		if index > 0 {
+			buffer.WriteString(" ")
+		}
+		buffer.WriteString(number.String())
 	}
 	return buffer.String()
 }
 
-/* getFactorList generates a factorList type containing all of the prime factors
- * of the 'number' input. The prime seive used will first check if the number is
- * divisible by the index, and if true, appends the index to the factor list. If
- * the 'index' value is greater than the square root of the 'number', the value
- * of 'number' is also a prime factor. If the square root of 'number' is equal
- * to 'index', then we can add 'index' twice and exit. If the value of 'index'
- * is two, change it to one so that we will only start checking for odd numbers
- * as prime candidates. */
-func getFactorList(number int) factorList {
-	var factors factorList
-	for index := 2; index <= number; index += 2 {
-		if number%index == 0 {
-			factors = append(factors, index)
-			number /= index
-			index = 0
-		} else if index*index > number {
-			factors = append(factors, number)
-			break
-		} else if index*index == number {
-			factors = append(factors, index)
-			factors = append(factors, index)
-			break
-		}
-		if index == 2 {
-			index = 1
+/* Factors generates a FactorList type containing all of the prime factors
+ * of the 'number' input, which may be of any size. The primes below 1000
+ * are tried by division first. What is left is split by Pollard's rho
+ * method, in Brent's variant, until each part passes the Miller-Rabin test
+ * for primes; see prime.go. */
+func Factors(number *big.Int) FactorList {
+	var factors FactorList
+	if number.Sign() <= 0 {
+		return factors
+	}
+	n := new(big.Int).Set(number)
+	quotient, remainder, divisor := new(big.Int), new(big.Int), new(big.Int)
+	for _, p := range smallPrimes {
+		divisor.SetUint64(p)
+		for {
+			quotient.QuoRem(n, divisor, remainder)
+			if remainder.Sign() != 0 {
+				break
+			}
+			factors = append(factors, big.NewInt(int64(p)))
+			n.Set(quotient)
 		}
 	}
+	factorBig(n, func(p *big.Int) { factors = append(factors, p) })
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Cmp(factors[j]) < 0 })
 	return factors
 }
 
-/* getNumber parses the input number in string format and returns the value
- * as a number if it really is a number -- else returns an error. */
-func getNumber(currentNumber string) int {
-	number, err := strconv.Atoi(currentNumber)
-	if err != nil {
-		fmt.Printf("factor: '%s' is not a valid positive integer\n",
-			flag.Arg(0))
-		os.Exit(0)
+// Exponents returns the factors separated by spaces, with each repeated
+// factor written once with its power, as in "2^3 3^2 5".
+func (numbers FactorList) Exponents() string {
+	var buffer bytes.Buffer
+	for index := 0; index < len(numbers); {
+		count := 1
+		for index+count < len(numbers) && numbers[index+count].Cmp(numbers[index]) == 0 {
+			count++
+		}
+		if index > 0 {
+			buffer.WriteString(" ")
+		}
+		buffer.WriteString(numbers[index].String())
+		if count > 1 {
+			fmt.Fprintf(&buffer, "^%d", count)
+		}
+		index += count
 	}
-	return number
+	return buffer.String()
 }
 
-func main() {
-	if flag.NArg() == 0 {
-		var number int
-		for {
-			fmt.Scan(&number)
-			factors := getFactorList(number)
-			fmt.Print(number, ":", factors.toString(), "\n")
-		}
+/* ParseNumber parses the input number in string format and returns the value
+ * as a number if it really is a number -- else returns an error. As in GNU
+ * factor, the digits may be preceded by blanks and a plus sign. */
+func ParseNumber(currentNumber string) (*big.Int, error) {
+	digits := strings.TrimLeft(currentNumber, " \t\n\v\f\r")
+	digits = strings.TrimPrefix(digits, "+")
+	number, ok := new(big.Int).SetString(digits, 10)
+	if !ok || strings.Trim(digits, "0123456789") != "" {
+		return nil, fmt.Errorf("%s is not a valid positive integer",
+			diag.Quote(currentNumber))
+	}
+	return number, nil
+}
+
+// Print writes the line factor prints for number to w: the number, a colon
+// and its prime factors.
+func Print(w io.Writer, number *big.Int) error {
+	return printWith(w, number, FactorList.String)
+}
+
+// PrintExponents is Print for factor -h, which writes each repeated factor
+// once with its power.
+func PrintExponents(w io.Writer, number *big.Int) error {
+	return printWith(w, number, FactorList.Exponents)
+}
+
+func printWith(w io.Writer, number *big.Int, format func(FactorList) string) error {
+	factors := Factors(number)
+	if len(factors) == 0 {
+		_, err := fmt.Fprintf(w, "%s:\n", number)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s: %s\n", number, format(factors))
+	return err
+}
+
+// printFactors prints the prime factors of the number in currentNumber, or
+// reports why it cannot. It returns false if the output cannot be written.
+func printFactors(report *diag.Reporter, w io.Writer, exponents bool, currentNumber string) bool {
+	number, err := ParseNumber(currentNumber)
+	if err != nil {
+		report.Errorf("%s", err)
+		return true
+	}
+	if exponents {
+		err = PrintExponents(w, number)
 	} else {
-		for index := 0; index < flag.NArg(); index++ {
-			number := getNumber(flag.Arg(index))
-			factors := getFactorList(number)
-			fmt.Print(number, ":", factors.toString(), "\n")
+		err = Print(w, number)
+	}
+	if err != nil {
+		report.Error("standard output", err)
+		return false
+	}
+	return true
+}
+
+// readWord reads the next number from r, skipping the blanks and newlines
+// before it. It returns io.EOF when there is none left.
+func readWord(r *bufio.Reader) (string, error) {
+	var word []byte
+	for {
+		c, err := r.ReadByte()
+		switch {
+		case err == io.EOF && len(word) > 0:
+			return string(word), nil
+		case err != nil:
+			return "", err
+		case c == ' ' || c == '\t' || c == '\n':
+			if len(word) > 0 {
+				return string(word), nil
+			}
+		default:
+			word = append(word, c)
 		}
 	}
 }
 
-func init() {
-	flag.Parse()
+// Main runs factor with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("factor")
+	opts := getopt.New("factor")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	exponents := opts.Bool('h', "exponents")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
+		fmt.Print(help_text)
+		return 0
 	}
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	if opts.NArg() == 0 {
+		// The numbers are factored as they are read, and the output is
+		// flushed whenever reading on would wait for more input, so that
+		// factor can answer a pipe one line at a time.
+		in := bufio.NewReader(os.Stdin)
+		for {
+			word, err := readWord(in)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				report.Error("-", err)
+				break
+			}
+			if !printFactors(report, out, *exponents, word) {
+				return report.Status()
+			}
+			if in.Buffered() == 0 {
+				if err := out.Flush(); err != nil {
+					report.Error("standard output", err)
+					return report.Status()
+				}
+			}
+		}
+	} else {
+		for index := 0; index < opts.NArg(); index++ {
+			if !printFactors(report, out, *exponents, opts.Arg(index)) {
+				return report.Status()
+			}
+		}
+	}
+	if err := out.Flush(); err != nil {
+		report.Error("standard output", err)
 	}
+	return report.Status()
 }
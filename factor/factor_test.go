@@ -0,0 +1,49 @@
+//
+// factor_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package factor
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "factor", []conformance.Case{
+		{Name: "numbers", Args: []string{"12", "97", "360"}},
+		{Name: "zero-and-one", Args: []string{"0", "1"}},
+		{Name: "square", Args: []string{"49"}},
+		{Name: "stdin", Stdin: "6 8\n  10\n"},
+		{Name: "invalid", Args: []string{"x", "6"}},
+		{Name: "negative", Args: []string{"--", "-1"}},
+		{Name: "big", Args: []string{"18446744073709551617", "123456789012345678901234567890", "99999999999999999999999"}},
+		{Name: "big-prime", Args: []string{"18446744073709551557"}},
+		{Name: "plus-and-blanks", Args: []string{"+12", " 12", "0012"}},
+		{Name: "semiprime", Args: []string{"99999999859999999373"}},
+		{Name: "semiprime-big", Args: []string{"100000000700000000039000000273"}},
+		{Name: "mersenne-prime", Args: []string{"170141183460469231731687303715884105727"}},
+		{Name: "many-digits", Args: []string{"1000000000000000000000000000000000000000000000000000000000002"}},
+		{Name: "stdin-blanks", Stdin: "\t6\n\n  8 \t10"},
+		{Name: "stdin-invalid", Stdin: "6 x 8\n"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
+
+// -h is not in GNU factor 9.1.
+func TestExponents(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		stdout string
+	}{
+		{[]string{"-h", "360", "1", "97"}, "360: 2^3 3^2 5\n1:\n97: 97\n"},
+		{[]string{"--exponents", "4611686014132420609"}, "4611686014132420609: 2147483647^2\n"},
+		{[]string{"--exponents", "1024"}, "1024: 2^10\n"},
+	} {
+		got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: test.args})
+		if string(got.Stdout) != test.stdout || got.Status != 0 {
+			t.Errorf("factor %q: got %d, %q; want 0, %q", test.args, got.Status, got.Stdout, test.stdout)
+		}
+	}
+}
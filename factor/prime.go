@@ -0,0 +1,352 @@
+//
+// prime.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package factor
+
+import "math/big"
+import "math/bits"
+
+// smallPrimes are the primes below 1000, which are tried by division
+// before anything cleverer.
+var smallPrimes = func() []uint64 {
+	var primes []uint64
+	var composite [1000]bool
+	for i := 2; i < len(composite); i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, uint64(i))
+		for j := i * i; j < len(composite); j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}()
+
+// mulMod returns a*b mod m, for a and b less than m.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi, lo, m)
+	return rem
+}
+
+// powMod returns a**e mod m, for a less than m.
+func powMod(a, e, m uint64) uint64 {
+	result := uint64(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = mulMod(result, a, m)
+		}
+		a = mulMod(a, a, m)
+	}
+	return result
+}
+
+// millerRabinBases are enough witnesses for the Miller-Rabin test to be
+// exact for every number of 64 bits.
+var millerRabinBases = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// isPrime reports whether n is a prime, by the Miller-Rabin test.
+func isPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range millerRabinBases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+	d, s := n-1, 0
+	for d%2 == 0 {
+		d /= 2
+		s++
+	}
+next:
+	for _, a := range millerRabinBases {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		for i := 1; i < s; i++ {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				continue next
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// isPrimeBig reports whether n is a prime. math/big runs 20 rounds of the
+// Miller-Rabin test with random bases and the Baillie-PSW test, which no
+// composite number is known to pass.
+func isPrimeBig(n *big.Int) bool {
+	return n.ProbablyPrime(20)
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// brent returns a factor of n other than 1 and n, for an odd composite n,
+// by Brent's variant of Pollard's rho method: the sequence x² + c mod n
+// repeats modulo each prime factor p after about √p steps, which shows as
+// a common divisor of n and the difference of two of its terms. The
+// differences are multiplied together so that a gcd is only taken once in
+// a while. If a sequence repeats modulo n itself, another c is tried.
+func brent(n uint64) uint64 {
+	const batch = 128
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 {
+			sum, carry := bits.Add64(mulMod(x, x, n), c, 0)
+			if carry != 0 || sum >= n {
+				sum -= n
+			}
+			return sum
+		}
+		y, g, q := uint64(2), uint64(1), uint64(1)
+		var x, ys uint64
+		for r := uint64(1); g == 1; r *= 2 {
+			x = y
+			for i := uint64(0); i < r; i++ {
+				y = f(y)
+			}
+			for k := uint64(0); k < r && g == 1; k += batch {
+				ys = y
+				for i := uint64(0); i < batch && i < r-k; i++ {
+					y = f(y)
+					q = mulMod(q, diff(x, y), n)
+				}
+				g = gcd(q, n)
+			}
+		}
+		if g == n {
+			// The batch went past the repetition; go over it one step
+			// at a time.
+			for g = 1; g == 1; {
+				ys = f(ys)
+				g = gcd(diff(x, ys), n)
+			}
+		}
+		if g != n {
+			return g
+		}
+	}
+}
+
+func diff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// montgomery does arithmetic modulo an odd n of any size, held in words
+// of 64 bits with the least significant first, on numbers in Montgomery
+// form: aR mod n, where R is 2 to the power of the bits of the words.
+// Products need no division in that form.
+type montgomery struct {
+	n    []uint64
+	nInv uint64   // -1/n mod 2^64
+	t    []uint64 // the product being reduced, two words longer than n
+}
+
+func newMontgomery(n *big.Int) *montgomery {
+	m := &montgomery{n: words(n, (n.BitLen()+63)/64)}
+	m.t = make([]uint64, len(m.n)+2)
+	// Newton's iteration doubles the bits of 1/n that are right, from the
+	// 3 of n itself.
+	inv := m.n[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - m.n[0]*inv
+	}
+	m.nInv = -inv
+	return m
+}
+
+// words returns the n words of x, least significant first.
+func words(x *big.Int, n int) []uint64 {
+	w := make([]uint64, n)
+	t := new(big.Int).Set(x)
+	for i := range w {
+		w[i] = t.Uint64()
+		t.Rsh(t, 64)
+	}
+	return w
+}
+
+// to returns x in Montgomery form.
+func (m *montgomery) to(x *big.Int) []uint64 {
+	r := new(big.Int).Lsh(x, uint(64*len(m.n)))
+	return words(r.Mod(r, m.big(m.n)), len(m.n))
+}
+
+// big returns the number of the words a.
+func (m *montgomery) big(a []uint64) *big.Int {
+	x := new(big.Int)
+	for i := len(a) - 1; i >= 0; i-- {
+		x.Lsh(x, 64).Or(x, new(big.Int).SetUint64(a[i]))
+	}
+	return x
+}
+
+// mul sets z to a*b/R mod n, which is the Montgomery form of the product
+// of the numbers a and b stand for. It multiplies and reduces a word of b
+// at a time, the method called CIOS.
+func (m *montgomery) mul(z, a, b []uint64) {
+	s, t := len(m.n), m.t
+	for i := range t {
+		t[i] = 0
+	}
+	for i := 0; i < s; i++ {
+		var c uint64
+		for j := 0; j < s; j++ {
+			t[j], c = mulAdd(a[j], b[i], t[j], c)
+		}
+		t[s], c = bits.Add64(t[s], c, 0)
+		t[s+1] = c
+		// Adding a multiple of n clears the low word, which is dropped.
+		q := t[0] * m.nInv
+		_, c = mulAdd(q, m.n[0], t[0], 0)
+		for j := 1; j < s; j++ {
+			t[j-1], c = mulAdd(q, m.n[j], t[j], c)
+		}
+		t[s-1], c = bits.Add64(t[s], c, 0)
+		t[s] = t[s+1] + c
+	}
+	copy(z, t[:s])
+	if t[s] != 0 || !less(z, m.n) {
+		sub(z, z, m.n)
+	}
+}
+
+// mulAdd returns the low and high words of x*y + a + c.
+func mulAdd(x, y, a, c uint64) (lo, hi uint64) {
+	hi, lo = bits.Mul64(x, y)
+	var carry uint64
+	lo, carry = bits.Add64(lo, a, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	return lo, hi
+}
+
+// add sets z to a+b mod n.
+func (m *montgomery) add(z, a, b []uint64) {
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(a[i], b[i], c)
+	}
+	if c != 0 || !less(z, m.n) {
+		sub(z, z, m.n)
+	}
+}
+
+// less reports whether a < b.
+func less(a, b []uint64) bool {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// sub sets z to a-b, modulo R.
+func sub(z, a, b []uint64) {
+	var borrow uint64
+	for i := range z {
+		z[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+}
+
+// diffWords sets z to |a-b|.
+func diffWords(z, a, b []uint64) {
+	if less(a, b) {
+		sub(z, b, a)
+	} else {
+		sub(z, a, b)
+	}
+}
+
+// brentBig is brent for numbers of more than 64 bits, in Montgomery form.
+func brentBig(n *big.Int) *big.Int {
+	const batch = 128
+	m := newMontgomery(n)
+	size := len(m.n)
+	one := big.NewInt(1)
+	g := new(big.Int)
+	gcdWith := func(a []uint64) { g.GCD(nil, nil, m.big(a), n) }
+	x, ys, d := make([]uint64, size), make([]uint64, size), make([]uint64, size)
+	for c := int64(1); ; c++ {
+		cm := m.to(big.NewInt(c))
+		f := func(y []uint64) {
+			m.mul(y, y, y)
+			m.add(y, y, cm)
+		}
+		y, q := m.to(big.NewInt(2)), m.to(one)
+		g.SetInt64(1)
+		for r := 1; g.Cmp(one) == 0; r *= 2 {
+			copy(x, y)
+			for i := 0; i < r; i++ {
+				f(y)
+			}
+			for k := 0; k < r && g.Cmp(one) == 0; k += batch {
+				copy(ys, y)
+				for i := 0; i < batch && i < r-k; i++ {
+					f(y)
+					diffWords(d, x, y)
+					m.mul(q, q, d)
+				}
+				gcdWith(q)
+			}
+		}
+		if g.Cmp(n) == 0 {
+			// The batch went past the repetition; go over it one step
+			// at a time.
+			for g.SetInt64(1); g.Cmp(one) == 0; {
+				f(ys)
+				diffWords(d, x, ys)
+				gcdWith(d)
+			}
+		}
+		if g.Cmp(n) != 0 {
+			return new(big.Int).Set(g)
+		}
+	}
+}
+
+// factor64 adds the prime factors of n, which has no factor below 1000, in
+// no particular order.
+func factor64(n uint64, add func(uint64)) {
+	switch {
+	case n == 1:
+	case isPrime(n):
+		add(n)
+	default:
+		d := brent(n)
+		factor64(d, add)
+		factor64(n/d, add)
+	}
+}
+
+// factorBig is factor64 for numbers of any size, which go on with factor64
+// once they fit in 64 bits.
+func factorBig(n *big.Int, add func(*big.Int)) {
+	switch {
+	case n.IsUint64():
+		factor64(n.Uint64(), func(p uint64) { add(new(big.Int).SetUint64(p)) })
+	case isPrimeBig(n):
+		add(n)
+	default:
+		d := brentBig(n)
+		factorBig(d, add)
+		factorBig(new(big.Int).Quo(n, d), add)
+	}
+}
@@ -0,0 +1,62 @@
+//
+// prime_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package factor
+
+import "math/big"
+import "math/rand"
+import "testing"
+
+func TestIsPrime(t *testing.T) {
+	// Strong pseudoprimes to several bases and Carmichael numbers among
+	// the numbers below 2^64, besides primes next to them.
+	numbers := []uint64{0, 1, 2, 3, 4, 561, 1105, 2047, 3215031751, 4759123141,
+		1122004669633, 3825123056546413051,
+		18446744073709551557, 18446744073709551615}
+	for n := uint64(0); n < 3000; n++ {
+		numbers = append(numbers, n)
+	}
+	for _, n := range numbers {
+		want := new(big.Int).SetUint64(n).ProbablyPrime(20)
+		if got := isPrime(n); got != want {
+			t.Errorf("isPrime(%d) = %v; want %v", n, got, want)
+		}
+	}
+}
+
+func TestMontgomery(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []int{65, 127, 128, 129, 200, 256, 300} {
+		for i := 0; i < 100; i++ {
+			n := new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), uint(size)))
+			n.SetBit(n, 0, 1).SetBit(n, size-1, 1)
+			m := newMontgomery(n)
+			a, b := new(big.Int).Rand(r, n), new(big.Int).Rand(r, n)
+			z := make([]uint64, len(m.n))
+			m.mul(z, m.to(a), m.to(b))
+			want := m.to(new(big.Int).Mod(new(big.Int).Mul(a, b), n))
+			if m.big(z).Cmp(m.big(want)) != 0 {
+				t.Fatalf("%d bits: %v * %v mod %v in Montgomery form = %v; want %v", size, a, b, n, m.big(z), m.big(want))
+			}
+		}
+	}
+}
+
+func TestFactors(t *testing.T) {
+	for _, s := range []string{"0", "1", "2", "360", "4611686014132420609", "18446744073709551615",
+		"99999999859999999373", "1000000000000000000000000000000000000000000000000000000000002"} {
+		n, _ := new(big.Int).SetString(s, 10)
+		product := big.NewInt(1)
+		factors := Factors(n)
+		for i, p := range factors {
+			if !p.ProbablyPrime(20) || i > 0 && p.Cmp(factors[i-1]) < 0 {
+				t.Errorf("Factors(%s) = %s", s, factors)
+			}
+			product.Mul(product, p)
+		}
+		if n.Cmp(big.NewInt(2)) >= 0 && product.Cmp(n) != 0 {
+			t.Errorf("Factors(%s) = %s, whose product is %s", s, factors, product)
+		}
+	}
+}
@@ -4,11 +4,11 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package false
 
-import "flag"
 import "fmt"
-import "os"
+
+import "github.com/aisola/go-coreutils/internal/applet"
 
 const (
 	help_text = `
@@ -30,21 +30,21 @@ const (
 `
 )
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
-
-	if flag.NFlag() > 1 {
-		os.Exit(-1)
-	}
-
-	if *help {
-		fmt.Println(help_text)
-	}
+func init() {
+	applet.Register("false", Main)
+}
 
-	if *version {
-		fmt.Println(version_text)
+// Main runs false with the given arguments and returns its exit status.
+// Like GNU false it ignores its arguments, except that a lone --help or
+// --version is honoured; the status is a failure either way.
+func Main(args []string) int {
+	if len(args) == 1 {
+		switch args[0] {
+		case "--help":
+			fmt.Println(help_text)
+		case "--version":
+			fmt.Print(version_text)
+		}
 	}
-	os.Exit(1)
+	return 1
 }
@@ -0,0 +1,18 @@
+//
+// false_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package false
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "false", []conformance.Case{
+		{Name: "false"},
+		{Name: "ignores-operands", Args: []string{"x", "y"}},
+	})
+}
@@ -5,16 +5,20 @@
 // Written By: Michael Murphy
 //
 
-package main
+package head
 
-import "bytes"
-import "flag"
+import "bufio"
 import "fmt"
 import "io"
-import "io/ioutil"
+import "math"
 import "os"
 import "strings"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/units"
+
 const (
 	help_text string = `
     Usage: head [OPTION]... [FILE]...
@@ -24,18 +28,27 @@ const (
     
     Mandatory arguments to long options are mandatory for short options too.
 
-       -help        display this help and exit
-       -version     output version information and exit
+       --help       display this help and exit
+       --version    output version information and exit
+
+       -c, --bytes=[-]K
+              print the first K bytes of each file; with the leading '-', print all but the last K bytes of each file
+
+       -n, --lines=[-]K
+              print the first K lines instead of the first 10; with the leading '-', print all but the last K lines of each file
 
-       -c, --bytes=K
-              output the first K bytes; or use -n +K to output starting with the Kth byte.
+       -q, --quiet, --silent
+              never print headers giving file names
 
-       -n, -lines=K
-              output the first K lines; or use -n +K to output starting with 
-the Kth
+       -v, --verbose
+              always print headers giving file names
 
-       -q, -quiet, -silent
-              never output headers giving file names
+       -z, --zero-terminated
+              line delimiter is NUL, not newline
+
+    The first option may also be written in the obsolete form -K[bkm][cblqvz], as in head -5 or head -2cv: K lines, or with b, k or m, K blocks of 512, 1024 or 1048576 bytes, followed by letters that stand for the options of the same names, with c for bytes and l for lines.
+
+    K may have a multiplier suffix: b 512, kB 1000, K 1024, MB 1000*1000, M 1024*1024, GB 1000*1000*1000, G 1024*1024*1024, and so on for T, P, E, Z, Y, R, Q. Binary prefixes can be used, too: KiB=K, MiB=M, and so on.
 `
 	version_text = `
     head (go-coreutils) 0.1
@@ -45,144 +58,306 @@ the Kth
     LICENSE. This is free software, and you are welcome to redistribute 
     it under certain conditions in LICENSE.
 `
-	bytes_text  = "output the first K bytes"
-	lines_text  = "output the first K lines"
-	silent_text = "never output headers giving file names"
 )
 
 var (
-	help        = flag.Bool("help", false, help_text)
-	version     = flag.Bool("version", false, version_text)
-	lines       = flag.Int("n", 10, lines_text)
-	linesLong   = flag.Int("lines", 10, lines_text)
-	bytesF      = flag.Int("c", 0, bytes_text)
-	bytesFLong  = flag.Int("bytes", 0, bytes_text)
-	silent      = flag.Bool("q", false, silent_text)
-	silentLong  = flag.Bool("quiet", false, silent_text)
-	silentLong2 = flag.Bool("silent", false, silent_text)
+	count   string // the K given to -n or -c
+	bytesF  bool   // count bytes rather than lines
+	silent  bool   // never output headers giving file names
+	verbose bool   // always output headers giving file names
+	eol     byte   // the byte that ends a line
+
+	report *diag.Reporter
 )
 
-// bufferFile returns a byte slice of the file contents.
-func bufferFile(s string) []byte {
-	buffer, err := ioutil.ReadFile(s)
-	if err != nil {
-		fmt.Println(err, "wc: "+s+": No such file or directory")
-		os.Exit(0)
+// Lines copies the first n lines of r to w. Lines end with eol.
+func Lines(w io.Writer, r io.Reader, n int64, eol byte) error {
+	reader := bufio.NewReader(r)
+	for n > 0 {
+		line, err := reader.ReadSlice(eol)
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		switch err {
+		case nil:
+			n--
+		case bufio.ErrBufferFull: // Only part of a long line was read.
+		case io.EOF:
+			return nil
+		default:
+			return err
+		}
 	}
-	return buffer
+	return nil
 }
 
-// silentCheck prints the file name if silent mode is enabled.
-func silentCheck(filename string) {
-	if !*silent {
-		fmt.Printf("==> %s <==\n", filename)
+// Bytes copies the first n bytes of r to w.
+func Bytes(w io.Writer, r io.Reader, n int64) error {
+	_, err := io.CopyN(w, r, n)
+	if err == io.EOF {
+		return nil
 	}
+	return err
 }
 
-// multiFileLineProcessor prints that first K lines of every file.
-func multiFileLineProcessor() {
-	for index, currentFile := range flag.Args() {
-		silentCheck(currentFile)
-		printHeadingLines(string(bufferFile(currentFile)))
-		if index+1 != flag.NArg() && !*silent {
-			fmt.Println()
+/* AllButLines copies all but the last n lines of r to w. It reads a line
+ * ahead of what it writes, keeping the last n lines it has read in a ring,
+ * and writes each line as it falls out of the ring. */
+func AllButLines(w io.Writer, r io.Reader, n int64, eol byte) error {
+	if n <= 0 {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	var ring [][]byte
+	next := 0 // the oldest line once the ring is full
+	partial := false
+	reader := bufio.NewReader(r)
+	for {
+		chunk, err := reader.ReadSlice(eol)
+		if len(chunk) > 0 {
+			switch {
+			case partial: // The rest of a line longer than the reader's buffer.
+				last := (next + len(ring) - 1) % len(ring)
+				ring[last] = append(ring[last], chunk...)
+			case int64(len(ring)) < n:
+				ring = append(ring, append([]byte(nil), chunk...))
+			default:
+				if _, err := w.Write(ring[next]); err != nil {
+					return err
+				}
+				ring[next] = append(ring[next][:0], chunk...)
+				next = (next + 1) % len(ring)
+			}
+			partial = err == bufio.ErrBufferFull
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil && err != bufio.ErrBufferFull {
+			return err
 		}
 	}
 }
 
-// multiFileByteProcessor prints the first K bytes of every file.
-func multiFileByteProcessor() {
-	for index, currentFile := range flag.Args() {
-		silentCheck(currentFile)
-		printHeadingBytes(bufferFile(currentFile))
-		if index+1 != flag.NArg() && !*silent {
-			fmt.Println()
+// AllButBytes copies all but the last n bytes of r to w. If r can seek, it
+// copies up to n bytes before its end; otherwise it holds the last n bytes
+// it has read back.
+func AllButBytes(w io.Writer, r io.Reader, n int64) error {
+	if n <= 0 {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if start, err := rs.Seek(0, io.SeekCurrent); err == nil {
+			if end, err := rs.Seek(0, io.SeekEnd); err == nil {
+				if _, err := rs.Seek(start, io.SeekStart); err != nil {
+					return err
+				}
+				if end-n <= start {
+					return nil
+				}
+				return Bytes(w, rs, end-n-start)
+			}
 		}
 	}
-}
 
-// printHeadingLines prints the first N lines from the input buffer.
-func printHeadingLines(buffer string) {
-	lineSlice := strings.Split(buffer, "\n")
-	lineCount := len(lineSlice)
-	if *lines < lineCount {
-		lineCount = *lines
+	var held []byte
+	buffer := make([]byte, 8192)
+	for {
+		size, err := r.Read(buffer)
+		held = append(held, buffer[:size]...)
+		if extra := int64(len(held)) - n; extra > int64(len(buffer)) {
+			if _, err := w.Write(held[:extra]); err != nil {
+				return err
+			}
+			held = append(held[:0], held[extra:]...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
 	}
-	for index := 0; index < lineCount; index++ {
-		fmt.Println(lineSlice[index])
+	if extra := int64(len(held)) - n; extra > 0 {
+		_, err := w.Write(held[:extra])
+		return err
 	}
+	return nil
 }
 
-// printHeadingBytes prints the first N bytes from the input buffer.
-func printHeadingBytes(buffer []byte) {
-	var byteCount int
-	if *bytesF > len(buffer) {
-		byteCount = len(buffer)
-	} else {
-		byteCount = *bytesF
+// parseCount parses the K given to -n or -c. A leading '-' asks for all but
+// the last K lines or bytes.
+func parseCount(s string) (n int64, allBut bool, err error) {
+	value := s
+	if strings.HasPrefix(value, "-") {
+		value, allBut = value[1:], true
 	}
-	for index := 0; index < byteCount; index++ {
-		fmt.Print(string(buffer[index]))
+	size, err := units.ParseSize(value)
+	if size > math.MaxInt64 {
+		size = math.MaxInt64
 	}
+	return int64(size), allBut, err
 }
 
-// getStdin will get input from stdin if there are no file arguments for tail.
-func getStdin() {
-	buffer := bytes.NewBuffer(nil)
-	io.Copy(buffer, os.Stdin)
-	if *bytesF == 0 {
-		printHeadingLines(buffer.String())
-	} else {
-		printHeadingBytes(buffer.Bytes())
+// obsolete applies arg if it is in the obsolete form of the options, a
+// count and letters: -NUM[bkm][cblqvz]. It returns false if arg is not in
+// that form, and an error for a letter that is not one of those.
+func obsolete(arg string, zero *bool) (bool, error) {
+	if len(arg) < 2 || arg[0] != '-' || arg[1] < '0' || arg[1] > '9' {
+		return false, nil
 	}
-}
-
-// oneFile will use the first file argument as an argument for tail.
-func oneFile() {
-	if *bytesF == 0 {
-		printHeadingLines(string(bufferFile(flag.Arg(0))))
-	} else {
-		printHeadingBytes(bufferFile(flag.Arg(0)))
+	letters := strings.TrimLeft(arg[1:], "0123456789")
+	number, suffix, lines := arg[1:len(arg)-len(letters)], "", true
+	for _, c := range letters {
+		switch c {
+		case 'c':
+			lines, suffix = false, ""
+		case 'b', 'k', 'm':
+			lines, suffix = false, string(c)
+		case 'l':
+			lines = true
+		case 'q':
+			silent, verbose = true, false
+		case 'v':
+			silent, verbose = false, true
+		case 'z':
+			*zero = true
+		default:
+			return true, fmt.Errorf("invalid trailing option -- %c", c)
+		}
 	}
+	count, bytesF = number+suffix, !lines
+	return true, nil
 }
 
-// multipleFiles will launch the proper function for looping through all files.
-func multipleFiles() {
-	if *bytesF == 0 {
-		multiFileLineProcessor()
-	} else {
-		multiFileByteProcessor()
+// headFile prints the head of the named file, or of standard input for "-",
+// preceded by a header if header is true. It returns false if the file
+// cannot be opened.
+func headFile(name string, header, first bool) bool {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		fp, err := os.Open(name)
+		if err != nil {
+			report.Errorf("cannot open %s for reading: %s", diag.Quote(name), diag.Strerror(err))
+			return false
+		}
+		defer fp.Close()
+		r = fp
+	}
+	if header {
+		if !first {
+			fmt.Println()
+		}
+		if name == "-" {
+			fmt.Println("==> standard input <==")
+		} else {
+			fmt.Printf("==> %s <==\n", name)
+		}
 	}
-}
 
-func main() {
+	n, allBut, _ := parseCount(count)
+	var err error
 	switch {
-	case flag.NArg() == 0 || flag.Arg(0) == "-":
-		getStdin()
-	case flag.NArg() == 1:
-		oneFile()
+	case bytesF && allBut:
+		err = AllButBytes(os.Stdout, r, n)
+	case bytesF:
+		err = Bytes(os.Stdout, r, n)
+	case allBut:
+		err = AllButLines(os.Stdout, r, n, eol)
 	default:
-		multipleFiles()
+		err = Lines(os.Stdout, r, n, eol)
+	}
+	if err != nil {
+		report.Errorf("error reading %s: %s", diag.Quote(name), diag.Strerror(err))
 	}
+	return true
 }
 
 func init() {
-	flag.Parse()
-	if *linesLong != 10 {
-		*lines = *linesLong
+	applet.Register("head", Main)
+}
+
+// Main runs head with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report = diag.New("head")
+	opts := getopt.New("head")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	count, bytesF = "10", false
+	opts.Func('n', "lines", getopt.RequiredArgument, func(value string) error {
+		count, bytesF = value, false
+		return nil
+	})
+	opts.Func('c', "bytes", getopt.RequiredArgument, func(value string) error {
+		count, bytesF = value, true
+		return nil
+	})
+	silent, verbose = false, false
+	quiet := func(string) error {
+		silent, verbose = true, false
+		return nil
 	}
-	if *silentLong || *silentLong2 {
-		*silent = true
+	opts.Func('q', "quiet", getopt.NoArgument, quiet)
+	opts.Func(0, "silent", getopt.NoArgument, quiet)
+	opts.Func('v', "verbose", getopt.NoArgument, func(string) error {
+		silent, verbose = false, true
+		return nil
+	})
+	zero := opts.Bool('z', "zero-terminated")
+	// A count is only allowed in the obsolete form, as the first argument.
+	for d := '0'; d <= '9'; d++ {
+		d := d
+		opts.Func(d, "", getopt.NoArgument, func(string) error {
+			return fmt.Errorf("invalid trailing option -- %c", d)
+		})
 	}
-	if *bytesFLong != 0 {
-		*bytesF = *bytesFLong
+	if len(args) > 0 {
+		if ok, err := obsolete(args[0], zero); err != nil {
+			return report.Usage(err)
+		} else if ok {
+			args = args[1:]
+		}
+	}
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
 	}
 	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
+		fmt.Print(help_text)
+		return 0
 	}
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+	if _, _, err := parseCount(count); err != nil {
+		what := "lines"
+		if bytesF {
+			what = "bytes"
+		}
+		// As in GNU, the '-' for all but the last K is not part of the count.
+		value := strings.TrimPrefix(count, "-")
+		if err == units.ErrRange {
+			report.Errorf("invalid number of %s: %s: %s", what, diag.Quote(value), diag.Strerror(err))
+		} else {
+			report.Errorf("invalid number of %s: %s", what, diag.Quote(value))
+		}
+		return report.Status()
+	}
+	eol = '\n'
+	if *zero {
+		eol = 0
+	}
+
+	files := opts.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	first := true
+	for _, file := range files {
+		if headFile(file, verbose || len(files) > 1 && !silent, first) {
+			first = false
+		}
 	}
+	return report.Status()
 }
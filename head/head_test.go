@@ -0,0 +1,58 @@
+//
+// head_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package head
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "head", []conformance.Case{
+		{Name: "default", Args: []string{"a.txt"}},
+		{Name: "lines", Args: []string{"-n", "3", "a.txt"}},
+		{Name: "lines-long", Args: []string{"--lines=2", "a.txt"}},
+		{Name: "lines-zero", Args: []string{"-n", "0", "a.txt"}},
+		{Name: "bytes", Args: []string{"-c", "5", "a.txt"}},
+		{Name: "no-trailing-newline", Args: []string{"-n", "5", "nonl.txt"}},
+		{Name: "empty", Args: []string{"empty.txt"}},
+		{Name: "stdin", Args: []string{"-n", "1"}, Stdin: "a\nb\n"},
+		{Name: "files", Args: []string{"-n", "1", "a.txt", "nonl.txt"}},
+		{Name: "files-stdin", Args: []string{"-n", "1", "a.txt", "-"}, Stdin: "a\nb\n"},
+		{Name: "files-quiet", Args: []string{"-q", "-n", "1", "a.txt", "nonl.txt"}},
+		{Name: "all-but-lines", Args: []string{"-n", "-3", "a.txt"}},
+		{Name: "all-but-bytes", Args: []string{"-c", "-5", "a.txt"}},
+		{Name: "all-but-lines-pipe", Args: []string{"-n", "-2"}, Stdin: "a\nb\nc\nd"},
+		{Name: "all-but-bytes-pipe", Args: []string{"--bytes=-3"}, Stdin: "abcdefgh"},
+		{Name: "all-but-too-many", Args: []string{"-n", "-100", "a.txt"}},
+		{Name: "plus-lines", Args: []string{"-n", "+2", "a.txt"}},
+		{Name: "bytes-suffix", Args: []string{"-c", "1K", "a.txt"}},
+		{Name: "bytes-suffix-decimal", Args: []string{"-c", "1kB", "a.txt"}},
+		{Name: "bytes-suffix-binary", Args: []string{"-c", "1KiB", "a.txt"}},
+		{Name: "bytes-suffix-blocks", Args: []string{"-c", "1b", "a.txt"}},
+		{Name: "lines-then-bytes", Args: []string{"-n", "2", "-c", "3", "a.txt"}},
+		{Name: "zero-terminated", Args: []string{"-z", "-n", "2"}, Stdin: "a\x00b\x00c\x00"},
+		{Name: "zero-terminated-all-but", Args: []string{"-z", "-n", "-1"}, Stdin: "a\x00b\x00c"},
+		{Name: "verbose", Args: []string{"-v", "-n", "1", "a.txt"}},
+		{Name: "verbose-then-quiet", Args: []string{"-v", "-q", "-n", "1", "a.txt", "nonl.txt"}},
+		{Name: "invalid-suffix", Args: []string{"-c", "1e", "a.txt"}},
+		{Name: "invalid-bytes", Args: []string{"-c", "2bB", "a.txt"}},
+		{Name: "too-large", Args: []string{"-n", "99999999999999999999", "a.txt"}},
+		{Name: "missing-file", Args: []string{"-n", "1", "missing", "a.txt"}},
+		{Name: "invalid-lines", Args: []string{"-n", "x", "a.txt"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+		{Name: "all-but-too-large", Args: []string{"-n", "-99999999999999999999", "a.txt"}},
+		{Name: "all-but-invalid", Args: []string{"-c", "-x", "a.txt"}},
+		{Name: "obsolete-lines", Args: []string{"-2", "a.txt"}},
+		{Name: "obsolete-bytes-verbose", Args: []string{"-3cv", "a.txt"}},
+		{Name: "obsolete-blocks", Args: []string{"-1b", "a.txt", "nonl.txt"}},
+		{Name: "obsolete-quiet-files", Args: []string{"-1q", "a.txt", "nonl.txt"}},
+		{Name: "obsolete-then-lines", Args: []string{"-5", "-n", "2", "a.txt"}},
+		{Name: "obsolete-not-first", Args: []string{"-n", "2", "-5", "a.txt"}},
+		{Name: "obsolete-invalid-letter", Args: []string{"-5x", "a.txt"}},
+		{Name: "obsolete-too-large", Args: []string{"-99999999999999999999", "a.txt"}},
+	})
+}
@@ -0,0 +1,44 @@
+//
+// applet.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package applet keeps the table of utilities that are linked into the
+// coreutils multicall binary. Every utility registers its entry point from
+// an init function, so importing a utility's package is enough to make it
+// available.
+package applet
+
+import "fmt"
+import "sort"
+
+// Main is the entry point of a utility. It receives the command line
+// arguments without the program name and returns the exit status.
+type Main func(args []string) int
+
+var applets = make(map[string]Main)
+
+// Register makes a utility available under name. It panics if the name is
+// already taken, since that can only be a programming error.
+func Register(name string, main Main) {
+	if _, dup := applets[name]; dup {
+		panic(fmt.Sprintf("applet: %s registered twice", name))
+	}
+	applets[name] = main
+}
+
+// Lookup returns the entry point registered under name.
+func Lookup(name string) (Main, bool) {
+	main, ok := applets[name]
+	return main, ok
+}
+
+// Names returns the names of all registered utilities in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(applets))
+	for name := range applets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,336 @@
+//
+// conformance.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package conformance runs the utilities against fixture inputs and
+// compares their standard output, standard error and exit status with
+// golden files recorded from GNU coreutils.
+//
+// A utility's test file lists its cases and hands them to Run:
+//
+//	func TestMain(m *testing.M) { conformance.Main(m, Main) }
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, "wc", []conformance.Case{
+//			{Name: "lines", Args: []string{"-l", "a.txt"}},
+//		})
+//	}
+//
+// Each case runs in a fresh copy of the package's testdata/input directory,
+// in the C locale and the UTC time zone, with the files' times set to
+// FixtureTime. The expected results live in testdata/golden/NAME.golden.
+// Running
+//
+//	go test ./wc -update
+//
+// records them again from the GNU utility of the same name found in PATH,
+// and -skipped also compares the cases marked as known to differ.
+package conformance
+
+import "bytes"
+import "encoding/json"
+import "flag"
+import "fmt"
+import "io"
+import "os"
+import "os/exec"
+import "path/filepath"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+
+var (
+	update  = flag.Bool("update", false, "record the golden files from GNU coreutils")
+	skipped = flag.Bool("skipped", false, "also compare the cases known to differ from GNU coreutils")
+)
+
+// argsVar passes the arguments of a case to the re-executed test binary.
+const argsVar = "GO_COREUTILS_CONFORMANCE_ARGS"
+
+// FixtureTime is the access and modification time of every fixture file.
+var FixtureTime = time.Date(2014, time.June, 15, 13, 45, 30, 0, time.UTC)
+
+// Case is one invocation of a utility.
+type Case struct {
+	Name  string   // names the golden file, unique within the utility
+	Args  []string // the arguments, without the program name
+	Stdin string   // the standard input
+	Env   []string // added to the environment, such as "LC_ALL=C.UTF-8"
+
+	// StdinFile, if not empty, names a fixture file that is piped to the
+	// standard input instead of Stdin.
+	StdinFile string
+
+	// Skip, if not empty, explains how the utility is known to differ
+	// from GNU coreutils for this case. The case is still recorded by
+	// -update but is not compared.
+	Skip string
+}
+
+// Result is what a utility did for a Case.
+type Result struct {
+	Status int
+	Stdout []byte
+	Stderr []byte
+}
+
+// Main runs the utility main when the test binary was started by Exec,
+// and the tests otherwise. It is meant to be called from TestMain.
+func Main(m *testing.M, main func(args []string) int) {
+	if encoded, ok := os.LookupEnv(argsVar); ok {
+		var args []string
+		if err := json.Unmarshal([]byte(encoded), &args); err != nil {
+			fmt.Fprintf(os.Stderr, "conformance: %s\n", err)
+			os.Exit(125)
+		}
+		os.Exit(main(args))
+	}
+	os.Exit(m.Run())
+}
+
+// environ returns the environment the utilities run in: the caller's
+// without the variables that change their output, in the C locale and the
+// UTC time zone.
+func environ() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		switch name := kv[:strings.IndexByte(kv+"=", '=')]; name {
+		case "BLOCK_SIZE", "BLOCKSIZE", "COLUMNS", "LS_BLOCK_SIZE", "LS_COLORS",
+			"POSIXLY_CORRECT", "QUOTING_STYLE", "TIME_STYLE", "TABSIZE", "TZ":
+		default:
+			if !strings.HasPrefix(name, "LC_") && name != "LANG" && name != "LANGUAGE" {
+				env = append(env, kv)
+			}
+		}
+	}
+	return append(env, "LC_ALL=C", "TZ=UTC0")
+}
+
+// Setup copies the package's testdata/input directory into a new temporary
+// directory and returns the directory. Files named .keep are left out; they
+// only keep empty directories in git.
+func Setup(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	err := filepath.Walk(filepath.Join("testdata", "input"), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		rel, err := filepath.Rel(filepath.Join("testdata", "input"), path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, rel)
+		switch {
+		case info.IsDir():
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case info.Name() == ".keep":
+			return nil
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, data, info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+		return os.Chtimes(target, FixtureTime, FixtureTime)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Directories get their times last, since creating their entries
+	// changed them.
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			os.Chtimes(path, FixtureTime, FixtureTime)
+		}
+		return nil
+	})
+	return dir
+}
+
+// run runs cmd for c in dir. Occurrences of dir in the output are replaced
+// by "$DIR".
+func run(t *testing.T, cmd *exec.Cmd, dir string, c Case) Result {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(c.Stdin)
+	if c.StdinFile != "" {
+		f, err := os.Open(filepath.Join(dir, c.StdinFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		cmd.Stdin = struct{ io.Reader }{f} // hides the file, so that it is piped
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(append(cmd.Env, environ()...), c.Env...)
+	err := cmd.Run()
+	status := 0
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatal(err)
+		}
+		status = exitErr.ExitCode()
+	}
+	clean := func(b []byte) []byte {
+		return bytes.Replace(b, []byte(dir), []byte("$DIR"), -1)
+	}
+	return Result{status, clean(stdout.Bytes()), clean(stderr.Bytes())}
+}
+
+// Exec runs the utility under test for c in dir.
+func Exec(t *testing.T, dir string, c Case) Result {
+	t.Helper()
+	args, err := json.Marshal(append([]string{}, c.Args...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = []string{argsVar + "=" + string(args)}
+	return run(t, cmd, dir, c)
+}
+
+// gnu returns the path of the GNU coreutils program prog.
+func gnu(t *testing.T, prog string) string {
+	t.Helper()
+	path, err := exec.LookPath(prog)
+	if err != nil {
+		t.Fatalf("cannot record golden files: %s", err)
+	}
+	out, _ := exec.Command(path, "--version").Output()
+	if !bytes.Contains(out, []byte("GNU coreutils")) {
+		t.Fatalf("cannot record golden files: %s is not GNU coreutils", path)
+	}
+	return path
+}
+
+// Run runs the cases of the utility prog as subtests and compares their
+// results with the golden files, or records the golden files with -update.
+func Run(t *testing.T, prog string, cases []Case) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			golden := filepath.Join("testdata", "golden", c.Name+".golden")
+			if *update {
+				cmd := exec.Command(gnu(t, prog), c.Args...)
+				cmd.Args[0] = prog
+				want := run(t, cmd, Setup(t), c)
+				if err := writeGolden(golden, prog, c, want); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if c.Skip != "" && !*skipped {
+				t.Skip(c.Skip)
+			}
+			want, err := readGolden(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := Exec(t, Setup(t), c)
+			cmdline := strings.Join(append([]string{prog}, c.Args...), " ")
+			if got.Status != want.Status {
+				t.Errorf("%s: exit status %d, want %d", cmdline, got.Status, want.Status)
+			}
+			if !bytes.Equal(got.Stdout, want.Stdout) {
+				t.Errorf("%s: stdout\n%q\nwant\n%q", cmdline, got.Stdout, want.Stdout)
+			}
+			if !bytes.Equal(got.Stderr, want.Stderr) {
+				t.Errorf("%s: stderr\n%q\nwant\n%q", cmdline, got.Stderr, want.Stderr)
+			}
+		})
+	}
+}
+
+// A golden file holds a Result in a form that can be read in a review:
+//
+//	$ wc -l a.txt
+//	exit status 0
+//	--- stdout (8 bytes)
+//	3 a.txt
+//	--- stderr (0 bytes)
+//
+// Each section holds exactly the number of bytes given in its header,
+// followed by a newline.
+func writeGolden(path, prog string, c Case, r Result) error {
+	var b bytes.Buffer
+	b.WriteString("$ " + prog)
+	for _, arg := range c.Args {
+		b.WriteString(" " + diag.QuoteName(arg))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "exit status %d\n", r.Status)
+	for _, section := range []struct {
+		name string
+		data []byte
+	}{{"stdout", r.Stdout}, {"stderr", r.Stderr}} {
+		fmt.Fprintf(&b, "--- %s (%d bytes)\n", section.name, len(section.data))
+		b.Write(section.data)
+		b.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0644)
+}
+
+// readGolden reads a golden file written by writeGolden.
+func readGolden(path string) (Result, error) {
+	var r Result
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+	line := func() (string, error) {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return "", io.ErrUnexpectedEOF
+		}
+		s := string(data[:i])
+		data = data[i+1:]
+		return s, nil
+	}
+	bad := fmt.Errorf("%s: malformed golden file", path)
+	if _, err := line(); err != nil {
+		return r, bad
+	}
+	s, err := line()
+	if _, scanErr := fmt.Sscanf(s, "exit status %d", &r.Status); err != nil || scanErr != nil {
+		return r, bad
+	}
+	for _, section := range []struct {
+		name string
+		data *[]byte
+	}{{"stdout", &r.Stdout}, {"stderr", &r.Stderr}} {
+		var n int
+		s, err := line()
+		if _, scanErr := fmt.Sscanf(s, "--- "+section.name+" (%d bytes)", &n); err != nil || scanErr != nil {
+			return r, bad
+		}
+		if n+1 > len(data) || data[n] != '\n' {
+			return r, bad
+		}
+		*section.data, data = data[:n], data[n+1:]
+	}
+	return r, nil
+}
@@ -0,0 +1,124 @@
+//
+// diag.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package diag prints diagnostics the way GNU coreutils does and keeps track
+// of the exit status a utility should return.
+//
+// Messages go to standard error as "prog: operand: strerror". A utility
+// reports an error and carries on with its remaining operands; when it is
+// done, Main returns Status, which is 0 unless something was reported.
+package diag
+
+import "errors"
+import "fmt"
+import "io"
+import "os"
+import "syscall"
+import "unicode"
+import "unicode/utf8"
+
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/quotearg"
+
+// Reporter prints the diagnostics of one program.
+type Reporter struct {
+	Prog string
+
+	// FailStatus is the exit status after an error has been reported and
+	// UsageStatus the one for a malformed command line. Both are 1 unless
+	// the utility documents otherwise, as ls and expr do.
+	FailStatus  int
+	UsageStatus int
+
+	w      io.Writer
+	status int
+}
+
+// New returns a Reporter for the program prog that writes to standard
+// error.
+func New(prog string) *Reporter {
+	return &Reporter{Prog: prog, FailStatus: 1, UsageStatus: 1, w: os.Stderr}
+}
+
+// Status returns the exit status for the errors reported so far.
+func (r *Reporter) Status() int {
+	return r.status
+}
+
+// Fail records a failure with the given exit status without printing
+// anything. The highest status recorded wins.
+func (r *Reporter) Fail(status int) {
+	if status > r.status {
+		r.status = status
+	}
+}
+
+// Errorf prints "prog: message" and records a failure.
+func (r *Reporter) Errorf(format string, a ...interface{}) {
+	fmt.Fprintf(r.w, "%s: %s\n", r.Prog, fmt.Sprintf(format, a...))
+	r.Fail(r.FailStatus)
+}
+
+// Error prints "prog: operand: strerror" and records a failure.
+func (r *Reporter) Error(operand string, err error) {
+	r.Errorf("%s: %s", QuoteName(operand), Strerror(err))
+}
+
+// Warnf prints "prog: message" without recording a failure.
+func (r *Reporter) Warnf(format string, a ...interface{}) {
+	fmt.Fprintf(r.w, "%s: %s\n", r.Prog, fmt.Sprintf(format, a...))
+}
+
+// Usage reports a malformed command line, such as the error returned by
+// getopt, followed by a pointer to --help. It returns the usage status.
+func (r *Reporter) Usage(err error) int {
+	msg := err.Error()
+	if e, ok := err.(*getopt.Error); ok {
+		msg = e.Msg
+	}
+	return r.Usagef("%s", msg)
+}
+
+// Usagef is like Usage for a message such as "missing operand".
+func (r *Reporter) Usagef(format string, a ...interface{}) int {
+	fmt.Fprintf(r.w, "%s: %s\nTry '%s --help' for more information.\n",
+		r.Prog, fmt.Sprintf(format, a...), r.Prog)
+	r.Fail(r.UsageStatus)
+	return r.status
+}
+
+// Strerror returns the message GNU tools print for err: the C library text
+// of the underlying errno, like "No such file or directory", or else the
+// error text without the operation and path that os adds.
+func Strerror(err error) string {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		msg := errno.Error()
+		r, n := utf8.DecodeRuneInString(msg)
+		return string(unicode.ToUpper(r)) + msg[n:]
+	}
+	switch e := err.(type) {
+	case *os.PathError:
+		return e.Err.Error()
+	case *os.LinkError:
+		return e.Err.Error()
+	case *os.SyscallError:
+		return e.Err.Error()
+	}
+	return err.Error()
+}
+
+// Quote quotes s for a diagnostic the way GNU's quoteaf does: always in
+// single quotes, in a form that can be pasted back into a shell.
+func Quote(s string) string {
+	return quotearg.Quote(s, quotearg.ShellEscapeAlways, "")
+}
+
+// QuoteName quotes s the way GNU's quotef does for the leading operand of
+// "prog: operand: strerror": only when it contains characters that are
+// special to the shell, or a colon.
+func QuoteName(s string) string {
+	return quotearg.Quote(s, quotearg.ShellEscape, ":")
+}
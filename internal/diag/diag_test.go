@@ -0,0 +1,36 @@
+//
+// diag_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package diag
+
+import "testing"
+
+// The expected values are what GNU coreutils prints for the same names.
+func TestQuote(t *testing.T) {
+	for _, test := range []struct {
+		s, quote, quoteName string
+	}{
+		{"a.txt", "'a.txt'", "a.txt"},
+		{"", "''", "''"},
+		{"a b", "'a b'", "'a b'"},
+		{"a:b", "'a:b'", "'a:b'"},
+		{"it's", `"it's"`, `"it's"`},
+		{"it's $x", `'it'\''s $x'`, `'it'\''s $x'`},
+		{"~a", "'~a'", "'~a'"},
+		{"a~", "'a~'", "a~"},
+		{"a\nb", `'a'$'\n''b'`, `'a'$'\n''b'`},
+		{"\x01", `''$'\001'`, `''$'\001'`},
+		{"#a", "'#a'", "'#a'"},
+		{"a#", "'a#'", "a#"},
+		{"a\tb", `'a'$'\t''b'`, `'a'$'\t''b'`},
+		{"\xff", `''$'\377'`, `''$'\377'`},
+	} {
+		if got := Quote(test.s); got != test.quote {
+			t.Errorf("Quote(%q) = %s, want %s", test.s, got, test.quote)
+		}
+		if got := QuoteName(test.s); got != test.quoteName {
+			t.Errorf("QuoteName(%q) = %s, want %s", test.s, got, test.quoteName)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+//
+// getopt.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package getopt parses command line options the way GNU getopt_long does,
+// so that the utilities accept the same command lines as GNU coreutils:
+//
+//	-la             bundled short options
+//	-n5, -n 5       attached or separate option values
+//	--lines=5       long options with a value, or --lines 5
+//	--lin=5         any unambiguous prefix of a long option
+//	--              end of options; everything after it is an operand
+//	a -l b          operands and options may be mixed
+//
+// Options and operands are permuted unless POSIXLY_CORRECT is set in the
+// environment, in which case the first operand ends option processing.
+package getopt
+
+import "fmt"
+import "os"
+import "strconv"
+import "strings"
+
+// ArgMode tells whether an option takes a value.
+type ArgMode int
+
+const (
+	NoArgument       ArgMode = iota // -a, --all
+	RequiredArgument                // -n 5, -n5, --lines=5, --lines 5
+	OptionalArgument                // -I, -Idate, --iso-8601, --iso-8601=date
+)
+
+// Error is returned by Parse for a malformed command line. Its message
+// matches the diagnostics printed by GNU getopt_long.
+type Error struct {
+	Prog string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return e.Prog + ": " + e.Msg
+}
+
+type option struct {
+	short rune
+	long  string
+	mode  ArgMode
+	set   func(value string) error
+}
+
+// name returns the spelling used for the option in diagnostics.
+func (o *option) name() string {
+	if o.long != "" {
+		return "--" + o.long
+	}
+	return "-" + string(o.short)
+}
+
+// Set is a set of options understood by one program.
+type Set struct {
+	prog    string
+	options []*option
+	args    []string
+	seen    int
+}
+
+// New returns an empty option set for the program prog.
+func New(prog string) *Set {
+	return &Set{prog: prog}
+}
+
+// Func defines an option that calls fn each time it is given. Short may be
+// 0 for a long-only option and long may be empty for a short-only one. For
+// an OptionalArgument option given without a value fn receives "".
+func (s *Set) Func(short rune, long string, mode ArgMode, fn func(value string) error) {
+	s.options = append(s.options, &option{short, long, mode, fn})
+}
+
+// BoolVar defines an option without a value that sets *p to true.
+func (s *Set) BoolVar(p *bool, short rune, long string) {
+	s.Func(short, long, NoArgument, func(string) error {
+		*p = true
+		return nil
+	})
+}
+
+// Bool defines an option without a value and returns a pointer to a
+// variable that is true once the option has been given.
+func (s *Set) Bool(short rune, long string) *bool {
+	p := new(bool)
+	s.BoolVar(p, short, long)
+	return p
+}
+
+// StringVar defines an option with a value that is stored in *p, which
+// starts out as value.
+func (s *Set) StringVar(p *string, short rune, long string, value string) {
+	*p = value
+	s.Func(short, long, RequiredArgument, func(arg string) error {
+		*p = arg
+		return nil
+	})
+}
+
+// String defines an option with a value and returns a pointer to the
+// variable holding the value, which starts out as value.
+func (s *Set) String(short rune, long string, value string) *string {
+	p := new(string)
+	s.StringVar(p, short, long, value)
+	return p
+}
+
+// IntVar defines an option with an integer value that is stored in *p,
+// which starts out as value.
+func (s *Set) IntVar(p *int, short rune, long string, value int) {
+	*p = value
+	opt := &option{short: short, long: long, mode: RequiredArgument}
+	opt.set = func(arg string) error {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("invalid argument '%s' for '%s'", arg, opt.name())
+		}
+		*p = n
+		return nil
+	}
+	s.options = append(s.options, opt)
+}
+
+// Int defines an option with an integer value and returns a pointer to the
+// variable holding the value, which starts out as value.
+func (s *Set) Int(short rune, long string, value int) *int {
+	p := new(int)
+	s.IntVar(p, short, long, value)
+	return p
+}
+
+// Args returns the operands left after parsing.
+func (s *Set) Args() []string { return s.args }
+
+// NArg returns the number of operands left after parsing.
+func (s *Set) NArg() int { return len(s.args) }
+
+// Arg returns the i'th operand, or "" if there is no such operand.
+func (s *Set) Arg(i int) string {
+	if i < 0 || i >= len(s.args) {
+		return ""
+	}
+	return s.args[i]
+}
+
+// NOpt returns the number of options given on the command line.
+func (s *Set) NOpt() int { return s.seen }
+
+func (s *Set) errorf(format string, a ...interface{}) error {
+	return &Error{Prog: s.prog, Msg: fmt.Sprintf(format, a...)}
+}
+
+// apply hands value to the option, turning a rejected value into an Error.
+func (s *Set) apply(opt *option, value string) error {
+	s.seen++
+	if err := opt.set(value); err != nil {
+		if _, ok := err.(*Error); ok {
+			return err
+		}
+		return &Error{Prog: s.prog, Msg: err.Error()}
+	}
+	return nil
+}
+
+func (s *Set) lookupShort(c rune) *option {
+	for _, opt := range s.options {
+		if opt.short != 0 && opt.short == c {
+			return opt
+		}
+	}
+	return nil
+}
+
+// lookupLong finds the option named name, accepting any unambiguous prefix
+// of a long option name. The argument arg, as it was written, is what an
+// unrecognized option is reported as.
+func (s *Set) lookupLong(name, arg string) (*option, error) {
+	var matches []*option
+	for _, opt := range s.options {
+		if opt.long == "" {
+			continue
+		}
+		if opt.long == name {
+			return opt, nil
+		}
+		if strings.HasPrefix(opt.long, name) {
+			matches = append(matches, opt)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, s.errorf("unrecognized option '%s'", arg)
+	case 1:
+		return matches[0], nil
+	}
+	possibilities := make([]string, len(matches))
+	for i, opt := range matches {
+		possibilities[i] = "'--" + opt.long + "'"
+	}
+	return nil, s.errorf("option '--%s' is ambiguous; possibilities: %s",
+		name, strings.Join(possibilities, " "))
+}
+
+// parseLong handles args[i], which starts with "--", and returns the index
+// of the last argument it consumed.
+func (s *Set) parseLong(args []string, i int) (int, error) {
+	name, value := args[i][2:], ""
+	hasValue := false
+	if eq := strings.IndexByte(name, '='); eq >= 0 {
+		name, value, hasValue = name[:eq], name[eq+1:], true
+	}
+	opt, err := s.lookupLong(name, args[i])
+	if err != nil {
+		return i, err
+	}
+	switch opt.mode {
+	case NoArgument:
+		if hasValue {
+			return i, s.errorf("option '--%s' doesn't allow an argument", opt.long)
+		}
+	case RequiredArgument:
+		if !hasValue {
+			if i+1 >= len(args) {
+				return i, s.errorf("option '--%s' requires an argument", opt.long)
+			}
+			i++
+			value = args[i]
+		}
+	}
+	return i, s.apply(opt, value)
+}
+
+// parseShort handles the cluster of short options in args[i] and returns
+// the index of the last argument it consumed.
+func (s *Set) parseShort(args []string, i int) (int, error) {
+	cluster := []rune(args[i][1:])
+	for j, c := range cluster {
+		opt := s.lookupShort(c)
+		if opt == nil {
+			return i, s.errorf("invalid option -- '%c'", c)
+		}
+		rest := string(cluster[j+1:])
+		switch opt.mode {
+		case NoArgument:
+			if err := s.apply(opt, ""); err != nil {
+				return i, err
+			}
+			continue
+		case RequiredArgument:
+			if rest == "" {
+				if i+1 >= len(args) {
+					return i, s.errorf("option requires an argument -- '%c'", c)
+				}
+				i++
+				rest = args[i]
+			}
+		}
+		return i, s.apply(opt, rest)
+	}
+	return i, nil
+}
+
+// Parse parses args, which must not include the program name. Options are
+// applied as they are found; the operands are available through Args.
+func (s *Set) Parse(args []string) error {
+	_, posixlyCorrect := os.LookupEnv("POSIXLY_CORRECT")
+	s.args = s.args[:0]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var err error
+		switch {
+		case arg == "--":
+			s.args = append(s.args, args[i+1:]...)
+			return nil
+		case strings.HasPrefix(arg, "--"):
+			i, err = s.parseLong(args, i)
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			i, err = s.parseShort(args, i)
+		case posixlyCorrect:
+			s.args = append(s.args, args[i:]...)
+			return nil
+		default:
+			s.args = append(s.args, arg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+//
+// getopt_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package getopt
+
+import "errors"
+import "os"
+import "reflect"
+import "strings"
+import "testing"
+
+// newTestSet returns a set with options of every kind, which logs each
+// option given as "name" or "name=value".
+func newTestSet(log *[]string) *Set {
+	s := New("prog")
+	logger := func(name string) func(string) error {
+		return func(value string) error {
+			if value != "" {
+				name += "=" + value
+			}
+			*log = append(*log, name)
+			return nil
+		}
+	}
+	s.Func('a', "all", NoArgument, logger("all"))
+	s.Func('b', "", NoArgument, logger("b"))
+	s.Func(0, "verbose", NoArgument, logger("verbose"))
+	s.Func(0, "version", NoArgument, logger("version"))
+	s.Func('n', "lines", RequiredArgument, logger("lines"))
+	s.Func(0, "lines-max", NoArgument, logger("lines-max"))
+	s.Func('I', "iso-8601", OptionalArgument, logger("iso-8601"))
+	s.Func('x', "fail", RequiredArgument, func(value string) error {
+		return errors.New("invalid value '" + value + "'")
+	})
+	return s
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		posix    bool
+		log      []string
+		operands []string
+		err      string
+	}{
+		// Short options, bundled, with values attached or in the next word.
+		{args: []string{"-a", "-b"}, log: []string{"all", "b"}},
+		{args: []string{"-ab"}, log: []string{"all", "b"}},
+		{args: []string{"-abn5"}, log: []string{"all", "b", "lines=5"}},
+		{args: []string{"-an", "5", "f"}, log: []string{"all", "lines=5"}, operands: []string{"f"}},
+		{args: []string{"-n", "-a"}, log: []string{"lines=-a"}},
+		{args: []string{"-nab"}, log: []string{"lines=ab"}},
+
+		// Long options and their unambiguous prefixes.
+		{args: []string{"--all"}, log: []string{"all"}},
+		{args: []string{"--al"}, log: []string{"all"}},
+		{args: []string{"--verb"}, log: []string{"verbose"}},
+		{args: []string{"--lines=5"}, log: []string{"lines=5"}},
+		{args: []string{"--lines", "5"}, log: []string{"lines=5"}},
+		{args: []string{"--lines="}, log: []string{"lines"}},
+		{args: []string{"--line=5"}, err: "prog: option '--line' is ambiguous; possibilities: '--lines' '--lines-max'"},
+		{args: []string{"--lines-m"}, log: []string{"lines-max"}},
+		{args: []string{"--ver"}, err: "prog: option '--ver' is ambiguous; possibilities: '--verbose' '--version'"},
+		{args: []string{"--"}, log: nil},
+
+		// An optional value is only taken when it is attached.
+		{args: []string{"-I"}, log: []string{"iso-8601"}},
+		{args: []string{"-Idate"}, log: []string{"iso-8601=date"}},
+		{args: []string{"-I", "date"}, log: []string{"iso-8601"}, operands: []string{"date"}},
+		{args: []string{"-aIdate"}, log: []string{"all", "iso-8601=date"}},
+		{args: []string{"--iso-8601"}, log: []string{"iso-8601"}},
+		{args: []string{"--iso=date"}, log: []string{"iso-8601=date"}},
+		{args: []string{"--iso-8601", "date"}, log: []string{"iso-8601"}, operands: []string{"date"}},
+
+		// Operands are permuted after the options, unless POSIXLY_CORRECT
+		// is set; -- ends the options and - is an operand.
+		{args: []string{"f", "-a", "g", "--lines", "3", "h"}, log: []string{"all", "lines=3"},
+			operands: []string{"f", "g", "h"}},
+		{args: []string{"f", "-a", "g"}, posix: true, operands: []string{"f", "-a", "g"}},
+		{args: []string{"-a", "f", "-b"}, posix: true, log: []string{"all"}, operands: []string{"f", "-b"}},
+		{args: []string{"-a", "--", "-b", "--all"}, log: []string{"all"}, operands: []string{"-b", "--all"}},
+		{args: []string{"-", "-a"}, log: []string{"all"}, operands: []string{"-"}},
+		{args: []string{"-", "-a"}, posix: true, operands: []string{"-", "-a"}},
+
+		// The diagnostics of GNU getopt_long.
+		{args: []string{"--bogus"}, err: "prog: unrecognized option '--bogus'"},
+		{args: []string{"--bogus=1"}, err: "prog: unrecognized option '--bogus=1'"},
+		{args: []string{"-z"}, err: "prog: invalid option -- 'z'"},
+		{args: []string{"-az"}, log: []string{"all"}, err: "prog: invalid option -- 'z'"},
+		{args: []string{"-n"}, err: "prog: option requires an argument -- 'n'"},
+		{args: []string{"--lines"}, err: "prog: option '--lines' requires an argument"},
+		{args: []string{"--all=yes"}, err: "prog: option '--all' doesn't allow an argument"},
+		{args: []string{"-x", "1"}, err: "prog: invalid value '1'"},
+	} {
+		t.Setenv("POSIXLY_CORRECT", "1")
+		if !test.posix {
+			os.Unsetenv("POSIXLY_CORRECT")
+		}
+		var log []string
+		s := newTestSet(&log)
+		err := s.Parse(test.args)
+		if test.err != "" {
+			var e *Error
+			if !errors.As(err, &e) || err.Error() != test.err {
+				t.Errorf("Parse(%q): error %v, want %s", test.args, err, test.err)
+			}
+		} else if err != nil {
+			t.Errorf("Parse(%q): %v", test.args, err)
+		}
+		if !reflect.DeepEqual(log, test.log) {
+			t.Errorf("Parse(%q): options %q, want %q", test.args, log, test.log)
+		}
+		if test.err == "" && strings.Join(s.Args(), " ") != strings.Join(test.operands, " ") {
+			t.Errorf("Parse(%q): operands %q, want %q", test.args, s.Args(), test.operands)
+		}
+	}
+}
+
+func TestTypedOptions(t *testing.T) {
+	s := New("prog")
+	all := s.Bool('a', "all")
+	var quiet bool
+	s.BoolVar(&quiet, 'q', "quiet")
+	name := s.String('N', "name", "none")
+	count := s.Int('c', "count", 7)
+	width := s.Int('w', "", 1)
+	if *name != "none" || *count != 7 {
+		t.Fatalf("defaults: name %q, count %d", *name, *count)
+	}
+	if err := s.Parse([]string{"-aN", "x", "op", "--count=12", "--name", "y", "-w3", "-c", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if !*all || quiet || *name != "y" || *count != 2 || *width != 3 {
+		t.Errorf("got all %v, quiet %v, name %q, count %d, width %d", *all, quiet, *name, *count, *width)
+	}
+	if s.NArg() != 1 || s.Arg(0) != "op" || s.Arg(1) != "" || s.Arg(-1) != "" {
+		t.Errorf("operands %q", s.Args())
+	}
+	if s.NOpt() != 6 {
+		t.Errorf("NOpt() = %d, want 6", s.NOpt())
+	}
+
+	for _, test := range []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--count=many"}, "prog: invalid argument 'many' for '--count'"},
+		{[]string{"-wide"}, "prog: invalid argument 'ide' for '-w'"},
+	} {
+		if err := s.Parse(test.args); err == nil || err.Error() != test.err {
+			t.Errorf("Parse(%q): error %v, want %s", test.args, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+//
+// lscolors.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package lscolors reads the colors of ls from the LS_COLORS environment
+// variable, and compiles the databases of dircolors into values for it.
+//
+// LS_COLORS is a list of KEY=VALUE entries separated by colons. A KEY is
+// either one of the two letter indicators, such as "di" for directories,
+// or "*" followed by the end of the file names it applies to, such as
+// "*.tar". A VALUE is the text put between the "lc" and "rc" indicators,
+// "\033[" and "m" by default, to color a name, and may contain escapes:
+// \a, \b, \e, \f, \n, \r, \t, \v, \? for delete, \_ for a space, octal
+// \NNN, hexadecimal \xHH, ^X for a control character, and a backslash
+// before any other character for that character.
+package lscolors
+
+import "bufio"
+import "errors"
+import "io"
+import "path"
+import "strings"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+
+// Indicators are the two letter keys of LS_COLORS, in the order GNU ls
+// knows them.
+var Indicators = []string{
+	"lc", "rc", "ec", "rs", "no", "fi", "di", "ln", "pi", "so", "bd", "cd",
+	"mi", "or", "ex", "do", "su", "sg", "st", "ow", "tw", "ca", "mh", "cl",
+}
+
+// defaults are the colors of GNU ls when LS_COLORS is not set.
+var defaults = map[string]string{
+	"lc": "\033[", "rc": "m", "rs": "0",
+	"di": "01;34", "ln": "01;36", "pi": "33", "so": "01;35", "bd": "01;33", "cd": "01;33",
+	"ex": "01;32", "do": "01;35", "su": "37;41", "sg": "30;43", "st": "37;44",
+	"ow": "34;42", "tw": "30;42", "ca": "30;41", "cl": "\033[K",
+}
+
+// ext is an entry of LS_COLORS for the names ending with suffix.
+type ext struct {
+	suffix, seq string
+}
+
+// Colors are the colors of the names listed by ls.
+type Colors struct {
+	indicators map[string]string
+	exts       []ext // the last entry of LS_COLORS first
+}
+
+// Default returns the colors GNU ls uses when LS_COLORS is not set.
+func Default() *Colors {
+	c := &Colors{indicators: make(map[string]string)}
+	for code, seq := range defaults {
+		c.indicators[code] = seq
+	}
+	return c
+}
+
+// PrefixError is the error of Parse for a two letter key that is not an
+// indicator, or whose value is malformed, which GNU ls does not tell apart.
+type PrefixError struct {
+	Key string
+}
+
+func (e *PrefixError) Error() string {
+	return "unrecognized prefix: " + diag.Quote(e.Key)
+}
+
+// ErrUnparsable is the error of Parse for a malformed value.
+var ErrUnparsable = errors.New("unparsable value for LS_COLORS environment variable")
+
+// Parse returns the colors set by the value of LS_COLORS over the defaults.
+// The error is a *PrefixError or ErrUnparsable; GNU ls prints the message
+// of ErrUnparsable after that of a *PrefixError.
+func Parse(value string) (*Colors, error) {
+	c := Default()
+	for value != "" {
+		if value[0] == ':' {
+			value = value[1:]
+			continue
+		}
+		if value[0] == '*' {
+			suffix, rest, ok := unescape(value[1:], true)
+			if !ok || !strings.HasPrefix(rest, "=") {
+				return nil, ErrUnparsable
+			}
+			seq, rest, ok := unescape(rest[1:], false)
+			if !ok {
+				return nil, ErrUnparsable
+			}
+			c.exts = append([]ext{{suffix, seq}}, c.exts...)
+			value = rest
+			continue
+		}
+		if len(value) < 3 || value[2] != '=' {
+			return nil, ErrUnparsable
+		}
+		key := value[:2]
+		seq, rest, ok := unescape(value[3:], false)
+		if !isIndicator(key) || !ok {
+			return nil, &PrefixError{key}
+		}
+		c.indicators[key] = seq
+		value = rest
+	}
+	return c, nil
+}
+
+func isIndicator(key string) bool {
+	for _, code := range Indicators {
+		if code == key {
+			return true
+		}
+	}
+	return false
+}
+
+// unescape reads a key or value of LS_COLORS from the start of s, up to a
+// colon, or an equals sign if equalsEnd is set. It returns the text with
+// its escapes replaced, the rest of s, and false for a malformed escape.
+func unescape(s string, equalsEnd bool) (string, string, bool) {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ':' || c == '=' && equalsEnd:
+			return b.String(), s[i:], true
+		case c == '\\':
+			i++
+			if i == len(s) {
+				return "", "", false
+			}
+			switch c = s[i]; {
+			case '0' <= c && c <= '7':
+				n := byte(0)
+				for ; i < len(s) && '0' <= s[i] && s[i] <= '7'; i++ {
+					n = n<<3 + s[i] - '0'
+				}
+				b.WriteByte(n)
+				continue
+			case c == 'x':
+				n := byte(0)
+				for i++; i < len(s); i++ {
+					if d := strings.IndexByte("0123456789abcdef", lower(s[i])); d >= 0 {
+						n = n<<4 + byte(d)
+					} else {
+						break
+					}
+				}
+				b.WriteByte(n)
+				continue
+			default:
+				if e := strings.IndexByte("abefnrtv?_", c); e >= 0 {
+					c = "\a\b\033\f\n\r\t\v\177 "[e]
+				}
+				b.WriteByte(c)
+			}
+		case c == '^':
+			i++
+			switch {
+			case i < len(s) && '@' <= s[i] && s[i] <= '~':
+				b.WriteByte(s[i] & 037)
+			case i < len(s) && s[i] == '?':
+				b.WriteByte(127)
+			default:
+				return "", "", false
+			}
+		default:
+			b.WriteByte(c)
+		}
+		i++
+	}
+	return b.String(), "", true
+}
+
+func lower(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// Indicator returns the sequence of an indicator, and false if it has none.
+// An indicator set to an empty value by LS_COLORS has one.
+func (c *Colors) Indicator(code string) (string, bool) {
+	seq, ok := c.indicators[code]
+	return seq, ok
+}
+
+// IsColored reports whether an indicator has a sequence that changes the
+// color, which "0" and "00" do not.
+func (c *Colors) IsColored(code string) bool {
+	seq := c.indicators[code]
+	return seq != "" && seq != "0" && seq != "00"
+}
+
+// Extension returns the sequence for a name from the entries of LS_COLORS
+// for the ends of names, which match regardless of case. The last entry
+// that matches wins.
+func (c *Colors) Extension(name string) (string, bool) {
+	for _, e := range c.exts {
+		if len(e.suffix) <= len(name) && strings.EqualFold(name[len(name)-len(e.suffix):], e.suffix) {
+			return e.seq, true
+		}
+	}
+	return "", false
+}
+
+// KnownTerm reports whether the TERM lines of the built-in database match
+// term, so that ls colors names when LS_COLORS is not set.
+func KnownTerm(term string) bool {
+	if term == "" {
+		return false
+	}
+	for _, line := range strings.Split(Database, "\n") {
+		if pattern, ok := strings.CutPrefix(line, "TERM "); ok {
+			if matched, _ := path.Match(pattern, term); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keywords maps the keywords of a database, in upper case, to their keys in
+// LS_COLORS.
+var keywords = map[string]string{
+	"NORMAL": "no", "NORM": "no", "FILE": "fi", "RESET": "rs", "DIR": "di",
+	"LNK": "ln", "LINK": "ln", "SYMLINK": "ln", "ORPHAN": "or", "MISSING": "mi",
+	"FIFO": "pi", "PIPE": "pi", "SOCK": "so", "BLK": "bd", "BLOCK": "bd",
+	"CHR": "cd", "CHAR": "cd", "DOOR": "do", "EXEC": "ex", "LEFT": "lc",
+	"LEFTCODE": "lc", "RIGHT": "rc", "RIGHTCODE": "rc", "END": "ec",
+	"ENDCODE": "ec", "SUID": "su", "SETUID": "su", "SGID": "sg", "SETGID": "sg",
+	"STICKY": "st", "OTHER_WRITABLE": "ow", "OWR": "ow",
+	"STICKY_OTHER_WRITABLE": "tw", "OWT": "tw", "CAPABILITY": "ca",
+	"MULTIHARDLINK": "mh", "CLRTOEOL": "cl",
+}
+
+// An Entry is an entry of LS_COLORS compiled from a database.
+type Entry struct {
+	Key   string // such as "di" or "*.tar"
+	Value string // as written in the database, with its escapes
+}
+
+// Compile reads a database of dircolors from r and returns the entries of
+// LS_COLORS it gives for the terminal type term and the value colorterm of
+// COLORTERM. A line of the database holds a keyword and its value, and
+// "#" starts a comment. The entries that follow TERM or COLORTERM lines,
+// whose values are patterns, apply only if one of those lines matches. A
+// keyword that starts with "." stands for "*" followed by the keyword.
+//
+// Each malformed line is reported to warn, with its line number, and makes
+// ok false.
+func Compile(r io.Reader, term, colorterm string, warn func(line int, msg string)) (entries []Entry, ok bool, err error) {
+	const (
+		global   = iota // before any TERM or COLORTERM line
+		termNo          // after lines that do not match
+		termSure        // after a line that matches
+		termYes         // in the entries after a line that matches
+	)
+	if term == "" {
+		term = "none"
+	}
+	state := global
+	ok = true
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30)
+	for n := 1; scanner.Scan(); n++ {
+		keyword, value := parseLine(scanner.Text())
+		if keyword == "" {
+			continue
+		}
+		if value == "" {
+			warn(n, "invalid line;  missing second token")
+			ok = false
+			continue
+		}
+		upper := strings.ToUpper(keyword)
+		switch {
+		case upper == "TERM" || upper == "COLORTERM":
+			subject := term
+			if upper == "COLORTERM" {
+				subject = colorterm
+			}
+			if state != termSure {
+				state = termNo
+				if matched, _ := path.Match(value, subject); matched {
+					state = termSure
+				}
+			}
+			continue
+		case state == termSure:
+			state = termYes
+		}
+		if state == termNo {
+			continue
+		}
+		switch {
+		case keyword[0] == '.':
+			entries = append(entries, Entry{"*" + keyword, value})
+		case keyword[0] == '*':
+			entries = append(entries, Entry{keyword, value})
+		case upper == "OPTIONS" || upper == "COLOR" || upper == "EIGHTBIT":
+		case keywords[upper] != "":
+			entries = append(entries, Entry{keywords[upper], value})
+		case state == termSure || state == termYes:
+			warn(n, "unrecognized keyword "+keyword)
+			ok = false
+		}
+	}
+	return entries, ok, scanner.Err()
+}
+
+// parseLine returns the keyword of a line of a database and its value, the
+// rest of the line up to a comment, without the blanks around it.
+func parseLine(line string) (keyword, value string) {
+	line = strings.TrimLeft(line, " \t\n\v\f\r")
+	if line == "" || line[0] == '#' {
+		return "", ""
+	}
+	end := strings.IndexAny(line, " \t\n\v\f\r")
+	if end < 0 {
+		return line, ""
+	}
+	keyword, line = line[:end], strings.TrimLeft(line[end:], " \t\n\v\f\r")
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	return keyword, strings.TrimRight(line, " \t\n\v\f\r")
+}
+
+// Quote quotes the key or value of an entry for LS_COLORS: a colon or an
+// equals sign gets a backslash unless it is escaped already, and a single
+// quote is written so that it can be put between single quotes in a shell.
+func Quote(s string) string {
+	var b strings.Builder
+	needBackslash := true
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\'':
+			b.WriteString(`'\'`)
+			needBackslash = true
+		case '\\', '^':
+			needBackslash = !needBackslash
+		case ':', '=':
+			if needBackslash {
+				b.WriteByte('\\')
+			}
+			needBackslash = true
+		default:
+			needBackslash = true
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+//
+// lscolors_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package lscolors
+
+import "fmt"
+import "strings"
+import "testing"
+
+func TestParse(t *testing.T) {
+	c, err := Parse(`di=\e[1\x41\101^[\?\_\:\=:*.TAR=7:*.tar=8:fi=:ln=target:no=00`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		code, seq string
+		ok        bool
+		colored   bool
+	}{
+		{"di", "\033[1AA\033\177 :=", true, true},
+		{"fi", "", true, false},
+		{"no", "00", true, false},
+		{"ln", "target", true, true},
+		{"ex", "01;32", true, true},
+		{"or", "", false, false},
+	} {
+		seq, ok := c.Indicator(test.code)
+		if seq != test.seq || ok != test.ok || c.IsColored(test.code) != test.colored {
+			t.Errorf("%s: got %q, %v, %v; want %q, %v, %v", test.code,
+				seq, ok, c.IsColored(test.code), test.seq, test.ok, test.colored)
+		}
+	}
+	// The last entry that matches wins, regardless of case.
+	for name, want := range map[string]string{"a.tar": "8", "B.TAR": "8", "tar": "", "a.tar.gz": ""} {
+		if seq, _ := c.Extension(name); seq != want {
+			t.Errorf("Extension(%q) = %q, want %q", name, seq, want)
+		}
+	}
+
+	for value, want := range map[string]string{
+		"xx=1":      "unrecognized prefix: 'xx'",
+		"di=1:di=^": "unrecognized prefix: 'di'",
+		"di=\\":     "unrecognized prefix: 'di'",
+		"*.c":       ErrUnparsable.Error(),
+		"*.c=\\":    ErrUnparsable.Error(),
+		"d":         ErrUnparsable.Error(),
+		"di:":       ErrUnparsable.Error(),
+		"::di=1::":  "",
+	} {
+		_, err := Parse(value)
+		if got := ""; err != nil {
+			got = err.Error()
+			if got != want {
+				t.Errorf("Parse(%q): got %q, want %q", value, got, want)
+			}
+		} else if want != "" {
+			t.Errorf("Parse(%q): got no error, want %q", value, want)
+		}
+	}
+}
+
+func TestKnownTerm(t *testing.T) {
+	for term, want := range map[string]bool{"xterm": true, "xterm-256color": true, "screen.linux": true, "dumb": false, "": false} {
+		if got := KnownTerm(term); got != want {
+			t.Errorf("KnownTerm(%q) = %v, want %v", term, got, want)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	const database = `# a comment
+bogus 1
+TERM vt*
+TERM xterm*
+  DIR   01;34   # directories
+.c 35
+*~ 90
+TERM linux
+LINK 36
+colorterm ?*
+eightbit yes
+EXEC
+WHAT 1
+`
+	var warnings []string
+	warn := func(line int, msg string) { warnings = append(warnings, fmt.Sprintf("%d: %s", line, msg)) }
+	for _, test := range []struct {
+		term, colorterm string
+		entries         string
+		warnings        []string
+	}{
+		{"xterm-color", "", "di=01;34 *.c=35 *~=90", []string{"12: invalid line;  missing second token"}},
+		{"linux", "", "ln=36", []string{"12: invalid line;  missing second token"}},
+		{"dumb", "1", "", []string{"12: invalid line;  missing second token",
+			"13: unrecognized keyword WHAT"}},
+	} {
+		warnings = nil
+		entries, ok, err := Compile(strings.NewReader(database), test.term, test.colorterm, warn)
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Key+"="+e.Value)
+		}
+		if err != nil || ok || strings.Join(got, " ") != test.entries || strings.Join(warnings, "|") != strings.Join(test.warnings, "|") {
+			t.Errorf("TERM=%s COLORTERM=%s: got %q, %v, %v, %q", test.term, test.colorterm, got, ok, err, warnings)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	for s, want := range map[string]string{
+		"01;34": "01;34",
+		"x:y":   `x\:y`,
+		"1=2":   `1\=2`,
+		`p\:q`:  `p\:q`,
+		`p\\:q`: `p\\\:q`,
+		"^=":    "^=",
+		"a'b":   `a'\''b`,
+		"*.tar": "*.tar",
+	} {
+		if got := Quote(s); got != want {
+			t.Errorf("Quote(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+//
+// parallel.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package parallel lets a utility work on several of its operands at once
+// while still printing its results in the order of the operands.
+package parallel
+
+import "runtime"
+
+// DefaultJobs is the number of operands worked on at once unless the user
+// asks for another number, one for each processor Go may use.
+func DefaultJobs() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+/* Do calls work(i) for each i from 0 to n-1, on at most jobs goroutines at
+ * a time, and done(i) on the calling goroutine in the order of i, each as
+ * soon as work(i) and all the done calls before it have returned. work
+ * typically stores its result in the i'th element of a slice, which done
+ * then prints.
+ *
+ * With a single job everything runs on the calling goroutine, one operand
+ * after the other. */
+func Do(n, jobs int, work, done func(i int)) {
+	if jobs <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			work(i)
+			done(i)
+		}
+		return
+	}
+
+	finished := make([]chan struct{}, n)
+	for i := range finished {
+		finished[i] = make(chan struct{})
+	}
+	running := make(chan struct{}, jobs)
+	go func() {
+		for i := 0; i < n; i++ {
+			running <- struct{}{}
+			go func(i int) {
+				work(i)
+				close(finished[i])
+				<-running
+			}(i)
+		}
+	}()
+	for i := 0; i < n; i++ {
+		<-finished[i]
+		done(i)
+	}
+}
@@ -0,0 +1,137 @@
+//
+// parallel_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package parallel
+
+import "fmt"
+import "reflect"
+import "sync"
+import "sync/atomic"
+import "testing"
+import "time"
+
+// Each job waits for the one after it, so that they finish in reverse
+// order, and done must still see them in order.
+func TestOrder(t *testing.T) {
+	for _, jobs := range []int{2, 4, 8, 100} {
+		n := 8
+		if jobs < n {
+			n = jobs // all the jobs that wait for each other must be running
+		}
+		finished := make([]chan struct{}, n+1)
+		for i := range finished {
+			finished[i] = make(chan struct{})
+		}
+		close(finished[n])
+		var mu sync.Mutex
+		var workOrder []int
+		results := make([]int, n)
+		var doneOrder []int
+		Do(n, jobs, func(i int) {
+			<-finished[i+1]
+			results[i] = i * i
+			mu.Lock()
+			workOrder = append(workOrder, i)
+			mu.Unlock()
+			close(finished[i])
+		}, func(i int) {
+			if results[i] != i*i {
+				t.Errorf("jobs=%d: done(%d) before work(%d) stored its result", jobs, i, i)
+			}
+			doneOrder = append(doneOrder, i)
+		})
+		want := make([]int, n)
+		for i := range want {
+			want[i] = i
+		}
+		if !reflect.DeepEqual(doneOrder, want) {
+			t.Errorf("jobs=%d: done order %v, want %v", jobs, doneOrder, want)
+		}
+		for i := range want {
+			want[i] = n - 1 - i
+		}
+		if !reflect.DeepEqual(workOrder, want) {
+			t.Errorf("jobs=%d: work order %v, want %v", jobs, workOrder, want)
+		}
+	}
+}
+
+// At most jobs operands are worked on at once, however many there are.
+func TestBound(t *testing.T) {
+	for _, test := range []struct{ n, jobs int }{{50, 3}, {3, 16}, {10, 10}, {1, 4}} {
+		var running, most int32
+		var count int
+		Do(test.n, test.jobs, func(i int) {
+			now := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&most)
+				if now <= old || atomic.CompareAndSwapInt32(&most, old, now) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}, func(i int) {
+			if i != count {
+				t.Errorf("n=%d jobs=%d: done(%d), want done(%d)", test.n, test.jobs, i, count)
+			}
+			count++
+		})
+		if count != test.n {
+			t.Errorf("n=%d jobs=%d: done called %d times", test.n, test.jobs, count)
+		}
+		if most > int32(test.jobs) || most > int32(test.n) {
+			t.Errorf("n=%d jobs=%d: %d operands worked on at once", test.n, test.jobs, most)
+		}
+	}
+}
+
+// With one job, or no more than one operand, each operand is worked on and
+// done on the calling goroutine before the next one.
+func TestSequential(t *testing.T) {
+	for _, test := range []struct {
+		n, jobs int
+		want    string
+	}{
+		{3, 1, "w0 d0 w1 d1 w2 d2 "},
+		{3, 0, "w0 d0 w1 d1 w2 d2 "},
+		{1, 8, "w0 d0 "},
+		{0, 8, ""},
+		{0, 1, ""},
+	} {
+		got := ""
+		Do(test.n, test.jobs, func(i int) {
+			got += fmt.Sprintf("w%d ", i)
+		}, func(i int) {
+			got += fmt.Sprintf("d%d ", i)
+		})
+		if got != test.want {
+			t.Errorf("Do(%d, %d): %q, want %q", test.n, test.jobs, got, test.want)
+		}
+	}
+}
+
+// done is called as soon as the operands before it are done, not once they
+// all are: here the last operand waits for the first one to be done.
+func TestDoneEarly(t *testing.T) {
+	first := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		Do(2, 2, func(i int) {
+			if i == 1 {
+				<-first
+			}
+		}, func(i int) {
+			if i == 0 {
+				close(first)
+			}
+		})
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("done(0) waited for work(1)")
+	}
+}
@@ -0,0 +1,279 @@
+//
+// quotearg.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package quotearg quotes strings in the quoting styles of GNU's quotearg,
+// which ls --quoting-style names:
+//
+//	literal              as it is
+//	shell                in single quotes if the shell needs them
+//	shell-always         in single quotes
+//	shell-escape         like shell, with $'\n' escapes for unprintable characters
+//	shell-escape-always  like shell-always, with $'\n' escapes
+//	c                    in double quotes, with C escapes
+//	c-maybe              like c, in double quotes only if needed
+//	escape               with C escapes, without quotes
+//	locale               in single quotes, with C escapes
+//	clocale              in double quotes, with C escapes
+//
+// The quotes of locale and clocale are those of the C locale. A valid UTF-8
+// sequence for a printable character is printable, as in a UTF-8 locale,
+// and every other byte is not.
+package quotearg
+
+import "strings"
+import "unicode"
+import "unicode/utf8"
+
+// Style is a way of quoting.
+type Style int
+
+// The quoting styles, in the order of Names.
+const (
+	Literal Style = iota
+	Shell
+	ShellAlways
+	ShellEscape
+	ShellEscapeAlways
+	C
+	CMaybe
+	Escape
+	Locale
+	CLocale
+)
+
+// Names holds the names of the styles, indexed by Style.
+var Names = []string{"literal", "shell", "shell-always", "shell-escape", "shell-escape-always",
+	"c", "c-maybe", "escape", "locale", "clocale"}
+
+// Quote returns s quoted in style. The bytes in extra are quoted too: with
+// a backslash in the styles with escapes, and by quoting all of s in the
+// shell styles that only quote when needed.
+func Quote(s string, style Style, extra string) string {
+	return quote(s, style, extra, false)
+}
+
+// quote is GNU's quotearg_buffer_restyled. With elide, the outer quotes are
+// left out unless something needs them, in which case s is quoted again
+// with them.
+func quote(s string, style Style, extra string, elide bool) string {
+	var out []byte
+	backslashEscapes := false
+	quoteString := ""
+	pendingShellEscapeEnd := false
+	encounteredSingleQuote := false
+	allCompatible := true // Whether the double quotes of C would do for the shell.
+	reprocessed := false
+
+	// force quotes s again with its outer quotes, in the style it has come to.
+	force := func() string {
+		if style == ShellAlways && backslashEscapes {
+			style = ShellEscapeAlways
+		}
+		return quote(s, style, "", false)
+	}
+
+processInput:
+	switch style {
+	case CMaybe:
+		style, elide = C, true
+		fallthrough
+	case C:
+		if !elide {
+			out = append(out, '"')
+		}
+		backslashEscapes = true
+		quoteString = `"`
+	case Escape:
+		backslashEscapes, elide = true, false
+	case Locale, CLocale:
+		quote := "'"
+		if style == CLocale {
+			quote = `"`
+		}
+		if !elide {
+			out = append(out, quote...)
+		}
+		backslashEscapes = true
+		quoteString = quote
+	case ShellEscape:
+		backslashEscapes = true
+		fallthrough
+	case Shell:
+		elide = true
+		fallthrough
+	case ShellEscapeAlways:
+		if !elide {
+			backslashEscapes = true
+		}
+		fallthrough
+	case ShellAlways:
+		style = ShellAlways
+		if !elide {
+			out = append(out, '\'')
+		}
+		quoteString = "'"
+	case Literal:
+		elide = false
+	}
+
+	for i := 0; i < len(s); i++ {
+		isRightQuote, escaping, compatible := false, false, false
+		// startEscape starts an escape, which needs the outer quotes.
+		startEscape := func() bool {
+			if elide {
+				return false
+			}
+			escaping = true
+			if style == ShellAlways && !pendingShellEscapeEnd {
+				out = append(out, "'$'"...)
+				pendingShellEscapeEnd = true
+			}
+			out = append(out, '\\')
+			return true
+		}
+
+		if backslashEscapes && style != ShellAlways && quoteString != "" && strings.HasPrefix(s[i:], quoteString) {
+			if elide {
+				return force()
+			}
+			isRightQuote = true
+		}
+
+		c := s[i]
+		var esc byte
+		needsEscape := false
+		switch c {
+		case '?':
+			if style == ShellAlways && elide {
+				return force()
+			}
+		case '\a', '\b', '\f', '\v':
+			esc = map[byte]byte{'\a': 'a', '\b': 'b', '\f': 'f', '\v': 'v'}[c]
+			needsEscape = backslashEscapes
+		case '\n', '\r', '\t', '\\':
+			esc = map[byte]byte{'\n': 'n', '\r': 'r', '\t': 't', '\\': '\\'}[c]
+			if c == '\\' && style == ShellAlways {
+				if elide {
+					return force()
+				}
+				goto storeC // A backslash needs no escape in single quotes.
+			}
+			if c == '\\' && backslashEscapes && elide {
+				goto storeC // Nor does it without quotes, if nothing else needs them.
+			}
+			if style == ShellAlways && elide {
+				return force()
+			}
+			needsEscape = backslashEscapes
+		case '{', '}', '#', '~', ' ', '!', '"', '$', '&', '(', ')', '*', ';', '<', '=', '>', '[', '^', '`', '|':
+			// Braces are special on their own, and # and ~ at the start.
+			if (c == '{' || c == '}') && len(s) != 1 || (c == '#' || c == '~') && i != 0 {
+				break
+			}
+			compatible = c == ' ' || c == '{' || c == '}' || c == '#' || c == '~'
+			if style == ShellAlways && elide {
+				return force()
+			}
+		case '\'':
+			encounteredSingleQuote, compatible = true, true
+			if style == ShellAlways {
+				if elide {
+					return force()
+				}
+				out = append(out, `'\'`...) // The apostrophe itself opens the quotes again.
+				pendingShellEscapeEnd = false
+			}
+		default:
+			if isSafe(c) {
+				compatible = true
+				break
+			}
+			r, n := utf8.DecodeRuneInString(s[i:])
+			printable := !(r == utf8.RuneError && n == 1) && IsPrint(r)
+			compatible = printable
+			if n > 1 || backslashEscapes && !printable {
+				// Write a multibyte character, or an unprintable one as octal
+				// escapes of its bytes.
+				last := i + n - 1
+				for {
+					if backslashEscapes && !printable {
+						if !startEscape() {
+							return force()
+						}
+						out = append(out, '0'+c>>6, '0'+c>>3&7)
+						c = '0' + c&7
+					} else if isRightQuote {
+						out = append(out, '\\')
+						isRightQuote = false
+					}
+					if i >= last {
+						break
+					}
+					if pendingShellEscapeEnd && !escaping {
+						out = append(out, "''"...)
+						pendingShellEscapeEnd = false
+					}
+					out = append(out, c)
+					i++
+					c = s[i]
+				}
+				goto storeC
+			}
+		}
+
+		if needsEscape {
+			c = esc
+		} else if !((backslashEscapes && style != ShellAlways || elide) && strings.IndexByte(extra, c) >= 0) && !isRightQuote {
+			goto storeC
+		}
+		if !startEscape() {
+			return force()
+		}
+
+	storeC:
+		if pendingShellEscapeEnd && !escaping {
+			out = append(out, "''"...)
+			pendingShellEscapeEnd = false
+		}
+		out = append(out, c)
+		if !compatible {
+			allCompatible = false
+		}
+	}
+
+	if len(out) == 0 && style == ShellAlways && elide {
+		return force()
+	}
+	// An apostrophe is common enough in names that double quotes are used
+	// if they will do. Otherwise the input is quoted again, as GNU does, in
+	// the state the first pass left.
+	if style == ShellAlways && !elide && encounteredSingleQuote {
+		if allCompatible {
+			return quote(s, C, extra, false)
+		}
+		if !reprocessed {
+			reprocessed = true
+			out = out[:0]
+			goto processInput
+		}
+	}
+	if quoteString != "" && !elide {
+		out = append(out, quoteString...)
+	}
+	return string(out)
+}
+
+// IsPrint reports whether r is printable in a UTF-8 locale of glibc, where
+// every assigned character is except for the controls, and the invisible
+// format characters are too.
+func IsPrint(r rune) bool {
+	return unicode.IsGraphic(r) || unicode.Is(unicode.Cf, r)
+}
+
+// isSafe reports whether c needs no quoting in any style.
+func isSafe(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		strings.IndexByte("%+,-./:]_", c) >= 0
+}
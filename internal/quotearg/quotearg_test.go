@@ -0,0 +1,65 @@
+//
+// quotearg_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package quotearg
+
+import "testing"
+
+// The expected values are what GNU ls --quoting-style prints for the same
+// names, in the order of Names, in a UTF-8 locale; the locale styles are
+// from the C locale, whose quotes they use, so names that are not ASCII
+// are left out of them.
+func TestQuote(t *testing.T) {
+	for _, test := range []struct {
+		s    string
+		want []string
+	}{
+		{"a.txt", []string{"a.txt", "a.txt", "'a.txt'", "a.txt", "'a.txt'", `"a.txt"`, "a.txt", "a.txt", "'a.txt'", `"a.txt"`}},
+		{"a b", []string{"a b", "'a b'", "'a b'", "'a b'", "'a b'", `"a b"`, "a b", "a b", "'a b'", `"a b"`}},
+		{"it's", []string{"it's", `"it's"`, `"it's"`, `"it's"`, `"it's"`, `"it's"`, "it's", "it's", `'it\'s'`, `"it's"`}},
+		{"it's\n", []string{"it's\n", "'it'\\''s\n'", "'it'\\''s\n'", `'''it'\''s'$'\n'`, `'''it'\''s'$'\n'`, `"it's\n"`, `"it's\n"`, `it's\n`, `'it\'s\n'`, `"it's\n"`}},
+		{`both'"`, []string{`both'"`, `'both'\''"'`, `'both'\''"'`, `'both'\''"'`, `'both'\''"'`, `"both'\""`, `"both'\""`, `both'"`, `'both\'"'`, `"both'\""`}},
+		{`back\slash`, []string{`back\slash`, `'back\slash'`, `'back\slash'`, `'back\slash'`, `'back\slash'`, `"back\\slash"`, `back\slash`, `back\\slash`, `'back\\slash'`, `"back\\slash"`}},
+		{`d"q`, []string{`d"q`, `'d"q'`, `'d"q'`, `'d"q'`, `'d"q'`, `"d\"q"`, `"d\"q"`, `d"q`, `'d"q'`, `"d\"q"`}},
+		{"\x01ctl", []string{"\x01ctl", "\x01ctl", "'\x01ctl'", `''$'\001''ctl'`, `''$'\001''ctl'`, `"\001ctl"`, `"\001ctl"`, `\001ctl`, `'\001ctl'`, `"\001ctl"`}},
+		{"{", []string{"{", "'{'", "'{'", "'{'", "'{'", `"{"`, "{", "{", "'{'", `"{"`}},
+		{"a{", []string{"a{", "a{", "'a{'", "a{", "'a{'", `"a{"`, "a{", "a{", "'a{'", `"a{"`}},
+		{"~t", []string{"~t", "'~t'", "'~t'", "'~t'", "'~t'", `"~t"`, "~t", "~t", "'~t'", `"~t"`}},
+		{"x~", []string{"x~", "x~", "'x~'", "x~", "'x~'", `"x~"`, "x~", "x~", "'x~'", `"x~"`}},
+		{"q?", []string{"q?", "'q?'", "'q?'", "'q?'", "'q?'", `"q?"`, "q?", "q?", "'q?'", `"q?"`}},
+		{"tab\tx", []string{"tab\tx", "'tab\tx'", "'tab\tx'", `'tab'$'\t''x'`, `'tab'$'\t''x'`, `"tab\tx"`, `"tab\tx"`, `tab\tx`, `'tab\tx'`, `"tab\tx"`}},
+		{"é", []string{"é", "é", "'é'", "é", "'é'", `"é"`, "é", "é"}},
+		{"\xff", []string{"\xff", "\xff", "'\xff'", `''$'\377'`, `''$'\377'`, `"\377"`, `"\377"`, `\377`}},
+	} {
+		for style, want := range test.want {
+			if got := Quote(test.s, Style(style), ""); got != want {
+				t.Errorf("Quote(%q, %s) = %s, want %s", test.s, Names[style], got, want)
+			}
+		}
+	}
+}
+
+// The extra characters are those ls quotes for its indicators, for the
+// escape style and in directory names.
+func TestQuoteExtra(t *testing.T) {
+	for _, test := range []struct {
+		s     string
+		style Style
+		extra string
+		want  string
+	}{
+		{"a b", Escape, " ", `a\ b`},
+		{"at@x", Escape, "=>@|", `at\@x`},
+		{"at@x", Shell, "=>@|", "'at@x'"},
+		{"st*r", ShellEscape, "*=>@|", "'st*r'"},
+		{"st*r", C, "=>@|", `"st*r"`},
+		{"x:y", C, ":", `"x\:y"`},
+		{"x:y", ShellAlways, ":", "'x:y'"},
+		{"x:y", Literal, ":", "x:y"},
+	} {
+		if got := Quote(test.s, test.style, test.extra); got != test.want {
+			t.Errorf("Quote(%q, %s, %q) = %s, want %s", test.s, Names[test.style], test.extra, got, test.want)
+		}
+	}
+}
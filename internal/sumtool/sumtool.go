@@ -0,0 +1,483 @@
+//
+// sumtool.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package sumtool implements the command line shared by md5sum, sha1sum,
+// the other *sum utilities and cksum on top of package checksum.
+package sumtool
+
+import "encoding/hex"
+import "errors"
+import "fmt"
+import "io"
+import "io/fs"
+import "io/ioutil"
+import "os"
+import "path"
+import "path/filepath"
+import "strconv"
+import "strings"
+
+import "github.com/aisola/go-coreutils/checksum"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/parallel"
+
+// open opens the named file, or standard input for "-".
+func open(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// result is the checksum of one file, or why it could not be computed.
+type result struct {
+	sum  []byte
+	size int64
+	err  error
+}
+
+// sumFile computes the checksum of the named file.
+func sumFile(alg checksum.Algorithm, name string) result {
+	fp, err := open(name)
+	if err != nil {
+		return result{err: err}
+	}
+	defer fp.Close()
+	h := alg.New()
+	size, err := io.Copy(h, fp)
+	if err != nil {
+		return result{err: err}
+	}
+	return result{h.Sum(nil), size, nil}
+}
+
+// printer formats the line printed for each file.
+type printer struct {
+	alg    checksum.Algorithm
+	tag    bool // print BSD-style lines, "TAG (NAME) = SUM"
+	binary int  // 1 for --binary, 0 for --text, -1 if neither was given
+	zero   bool // end the lines with NUL and do not escape the names
+	names  bool // name the files even for the checksums of cksum and sum
+}
+
+// format formats the line for the named file.
+func (p *printer) format(r result, name string) string {
+	end := "\n"
+	if p.zero {
+		end = "\x00"
+	}
+	if p.alg.Format != nil {
+		s := p.alg.Format(r.sum, r.size)
+		if p.names {
+			s += " " + name
+		}
+		return s + end
+	}
+	line := checksum.Line{Sum: hex.EncodeToString(r.sum), Name: name, Binary: p.binary == 1}
+	if p.tag {
+		line.Tag = p.alg.LineTag()
+	}
+	return line.Format(!p.zero) + end
+}
+
+// verifier checks the lists given to --check.
+type verifier struct {
+	alg    checksum.Algorithm
+	detect bool // each line names its algorithm, as for cksum without -a
+	report *diag.Reporter
+
+	verbosity     int  // what to print, as the last of --status, --quiet and --warn says
+	strict        bool // fail if any line is improperly formatted
+	ignoreMissing bool // skip the listed files that do not exist
+
+	// tree is set by --recursive, to also report the listed files removed
+	// and the files added under roots, the directories given with --root.
+	tree  *walker
+	roots []string
+}
+
+// How much checking prints. As in GNU, --status, --quiet and --warn each
+// override the ones given before them.
+const (
+	verbosityStatus = iota // print nothing, only exit with the status
+	verbosityQuiet         // do not print the files that matched
+	verbosityNormal
+	verbosityWarn // also warn about each improperly formatted line
+)
+
+// verbosityNames are the options that set each verbosity.
+var verbosityNames = map[int]string{verbosityStatus: "status", verbosityQuiet: "quiet", verbosityWarn: "warn"}
+
+// nameEscaper escapes the names with a line break in them when printing
+// the results, so that each result stays on one line.
+var nameEscaper = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+
+// print prints the result for the named file, unless only the status is
+// wanted.
+func (v *verifier) print(name, result string) {
+	if v.verbosity == verbosityStatus {
+		return
+	}
+	if strings.Contains(name, "\n") {
+		name = "\\" + nameEscaper.Replace(name)
+	}
+	fmt.Printf("%s: %s\n", name, result)
+}
+
+// check verifies the files named in the list and reports whether they all
+// matched, the way GNU coreutils does.
+func (v *verifier) check(list string) bool {
+	fp, err := open(list)
+	if err != nil {
+		v.report.Error(list, err)
+		return false
+	}
+	defer fp.Close()
+	name := list
+	if list == "-" {
+		name = "standard input"
+	}
+
+	var misformatted, unread, mismatched, removed, added int
+	var formatted, matched bool
+	listed := make(map[string]bool)
+	checker := checksum.Checker{Algorithm: v.alg, Detect: v.detect, Open: open, Stdin: list == "-"}
+	err = checker.Check(fp, func(result checksum.Result) {
+		switch {
+		case result.Err == checksum.ErrFormat:
+			misformatted++
+			if v.verbosity == verbosityWarn {
+				v.report.Warnf("%s: %d: improperly formatted %s checksum line",
+					diag.QuoteName(name), result.Line, result.Tag)
+			}
+			return
+		case result.Err != nil && v.ignoreMissing && errors.Is(result.Err, fs.ErrNotExist):
+		case result.Err != nil && v.tree != nil && errors.Is(result.Err, fs.ErrNotExist):
+			v.print(result.Name, "REMOVED")
+			removed++
+		case result.Err != nil:
+			v.report.Error(result.Name, result.Err)
+			v.print(result.Name, "FAILED open or read")
+			unread++
+		case result.OK:
+			if v.verbosity >= verbosityNormal {
+				v.print(result.Name, "OK")
+			}
+			matched = true
+		default:
+			v.print(result.Name, "FAILED")
+			mismatched++
+		}
+		listed[filepath.Clean(result.Name)] = true
+		formatted = true
+	})
+	if err != nil {
+		v.report.Errorf("%s: read error", diag.QuoteName(name))
+		return false
+	}
+
+	if !formatted {
+		v.report.Errorf("%s: no properly formatted checksum lines found", diag.QuoteName(name))
+		return false
+	}
+	if v.tree != nil {
+		added = v.added(listed, regularFile(list, os.Stdin))
+	}
+	if v.verbosity != verbosityStatus {
+		if misformatted > 0 {
+			v.report.Warnf("WARNING: %s improperly formatted", plural(misformatted, "line is", "lines are"))
+		}
+		if unread > 0 {
+			v.report.Warnf("WARNING: %s could not be read", plural(unread, "listed file", "listed files"))
+		}
+		if mismatched > 0 {
+			v.report.Warnf("WARNING: %s did NOT match", plural(mismatched, "computed checksum", "computed checksums"))
+		}
+		if removed > 0 {
+			v.report.Warnf("WARNING: %s removed", plural(removed, "listed file was", "listed files were"))
+		}
+		if added > 0 {
+			v.report.Warnf("WARNING: %s added", plural(added, "file was", "files were"))
+		}
+		if v.ignoreMissing && !matched {
+			v.report.Errorf("%s: no file was verified", diag.QuoteName(name))
+		}
+	}
+	return unread == 0 && mismatched == 0 && removed == 0 && added == 0 &&
+		(!v.strict || misformatted == 0) && (!v.ignoreMissing || matched)
+}
+
+// added prints the files under the roots that are not listed, other than
+// the list, and returns how many there are.
+func (v *verifier) added(listed map[string]bool, list os.FileInfo) int {
+	tree := *v.tree
+	if list != nil {
+		tree.skip = append(tree.skip[:len(tree.skip):len(tree.skip)], list)
+	}
+	n := 0
+	for _, root := range v.roots {
+		for _, name := range tree.files(root) {
+			if !listed[name] {
+				v.print(name, "ADDED")
+				listed[name] = true // under more than one root
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// plural formats a count for the warnings printed after checking a list,
+// such as "2 lines are".
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, one)
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// Tool describes one of the utilities.
+type Tool struct {
+	Name      string             // the program name, such as "md5sum"
+	Algorithm checksum.Algorithm // what it computes, unless -a says otherwise
+	Help      string             // printed for --help
+	Version   string             // printed for --version
+
+	// Cksum gives the tool the options of cksum: -a selects any of the
+	// registered algorithms, whose lines are tagged unless --untagged is
+	// given, and the lists to check name the algorithm of each line.
+	Cksum bool
+}
+
+// lookup returns the algorithm named by the argument of -a.
+func lookup(name string) (checksum.Algorithm, error) {
+	if alg, ok := checksum.Lookup(name); ok {
+		return alg, nil
+	}
+	msg := fmt.Sprintf("invalid argument %s for '--algorithm'\nValid arguments are:", diag.Quote(name))
+	for _, alg := range checksum.Algorithms() {
+		msg += fmt.Sprintf("\n  - '%s'", alg.Name)
+	}
+	return checksum.Algorithm{}, errors.New(msg)
+}
+
+// resize returns alg resized to the length given to -l, in bits, or
+// reports why it cannot be.
+func resize(report *diag.Reporter, alg checksum.Algorithm, length string) (checksum.Algorithm, bool) {
+	bits, err := strconv.ParseUint(length, 10, 31)
+	switch {
+	case length == "":
+		return alg, true
+	case err != nil:
+		report.Errorf("invalid length: %s", diag.Quote(length))
+		return alg, false
+	case bits%8 != 0:
+		report.Errorf("invalid length: %s", diag.Quote(length))
+		report.Errorf("length is not a multiple of 8")
+		return alg, false
+	case bits == 0:
+		return alg, true
+	case alg.Resize == nil:
+		report.Errorf("--length is only supported with --algorithm=blake2b or sha3")
+		return alg, false
+	}
+	resized, err := alg.Resize(int(bits))
+	if err != nil {
+		report.Errorf("invalid length: %s", diag.Quote(length))
+		report.Errorf("%s", err)
+		return alg, false
+	}
+	return resized, true
+}
+
+// Main runs the utility t with the given arguments and returns its exit
+// status.
+func Main(t Tool, args []string) int {
+	report := diag.New(t.Name)
+	opts := getopt.New(t.Name)
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	check := opts.Bool('c', "check")
+	jobs := opts.Int('j', "jobs", parallel.DefaultJobs())
+	p := printer{alg: t.Algorithm, tag: t.Cksum, binary: -1}
+	opts.Func('b', "binary", getopt.NoArgument, func(string) error {
+		p.binary = 1
+		return nil
+	})
+	opts.Func('t', "text", getopt.NoArgument, func(string) error {
+		p.binary = 0
+		return nil
+	})
+	opts.Func(0, "tag", getopt.NoArgument, func(string) error {
+		// cksum tags its lines by default, and does not change the mode.
+		p.tag = true
+		if !t.Cksum {
+			p.binary = 1
+		}
+		return nil
+	})
+	opts.BoolVar(&p.zero, 'z', "zero")
+	selected := false
+	if t.Cksum {
+		opts.Func('a', "algorithm", getopt.RequiredArgument, func(name string) (err error) {
+			p.alg, err = lookup(name)
+			selected = true
+			return err
+		})
+		opts.Func(0, "untagged", getopt.NoArgument, func(string) error {
+			p.tag = false
+			return nil
+		})
+	}
+	length := ""
+	if t.Cksum || t.Algorithm.Resize != nil {
+		opts.StringVar(&length, 'l', "length", "")
+	}
+	v := verifier{report: report, verbosity: verbosityNormal}
+	verbosity := func(level int) func(string) error {
+		return func(string) error {
+			v.verbosity = level
+			return nil
+		}
+	}
+	opts.BoolVar(&v.ignoreMissing, 0, "ignore-missing")
+	opts.Func(0, "quiet", getopt.NoArgument, verbosity(verbosityQuiet))
+	opts.Func(0, "status", getopt.NoArgument, verbosity(verbosityStatus))
+	opts.BoolVar(&v.strict, 0, "strict")
+	opts.Func('w', "warn", getopt.NoArgument, verbosity(verbosityWarn))
+	recursive := opts.Bool('r', "recursive")
+	w := walker{report: report}
+	opts.Func(0, "exclude", getopt.RequiredArgument, func(pattern string) error {
+		w.exclude = append(w.exclude, pattern)
+		return nil
+	})
+	opts.Func(0, "root", getopt.RequiredArgument, func(dir string) error {
+		v.roots = append(v.roots, filepath.Clean(dir))
+		return nil
+	})
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
+	if *jobs < 1 {
+		return report.Usagef("invalid number of jobs: %s", diag.Quote(strconv.Itoa(*jobs)))
+	}
+
+	if *help {
+		fmt.Println(t.Help)
+		return 0
+	}
+
+	if *version {
+		fmt.Print(t.Version)
+		return 0
+	}
+
+	// The lists are checked with the algorithm of any length; -l only
+	// sets the length of the sums printed.
+	v.alg, v.detect = p.alg, t.Cksum && !selected
+	var ok bool
+	if p.alg, ok = resize(report, p.alg, length); !ok {
+		return report.Status()
+	}
+	switch {
+	case *check && selected && p.alg.Format != nil:
+		report.Errorf("--check is not supported with --algorithm={bsd,sysv,crc}")
+		return report.Status()
+	case !*check && p.alg.New == nil:
+		report.Errorf("--algorithm=%s requires --length", p.alg.Name)
+		return report.Status()
+	}
+
+	switch {
+	case p.tag && p.binary == 0 && t.Cksum:
+		return report.Usagef("--text mode is only supported with --untagged")
+	case p.tag && p.binary == 0:
+		return report.Usagef("--tag does not support --text mode")
+	case *check && p.zero:
+		return report.Usagef("the --zero option is not supported when verifying checksums")
+	case *check && p.tag && !t.Cksum:
+		return report.Usagef("the --tag option is meaningless when verifying checksums")
+	case *check && p.binary >= 0:
+		return report.Usagef("the --binary and --text options are meaningless when verifying checksums")
+	}
+	if !*check {
+		for _, o := range []struct {
+			set  bool
+			name string
+		}{{v.ignoreMissing, "ignore-missing"}, {v.verbosity != verbosityNormal, verbosityNames[v.verbosity]},
+			{v.strict, "strict"}} {
+			if o.set {
+				return report.Usagef("the --%s option is meaningful only when verifying checksums", o.name)
+			}
+		}
+	}
+	if len(w.exclude) > 0 && !*recursive {
+		return report.Usagef("the --exclude option is meaningful only with --recursive")
+	}
+	switch {
+	case len(v.roots) > 0 && !*check:
+		return report.Usagef("the --root option is meaningful only when verifying checksums")
+	case len(v.roots) > 0 && !*recursive:
+		return report.Usagef("the --root option is meaningful only with --recursive")
+	case *check && *recursive && len(v.roots) == 0:
+		// The directories walked are not in the lists, and cannot be
+		// told from the names in them.
+		return report.Usagef("--check --recursive requires --root=DIR")
+	}
+	for _, pattern := range w.exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			report.Errorf("invalid pattern: %s", diag.Quote(pattern))
+			return report.Status()
+		}
+	}
+	if *recursive {
+		// Output redirected into the tree is not part of it.
+		if info := regularFile("-", os.Stdout); info != nil {
+			w.skip = append(w.skip, info)
+		}
+		v.tree = &w
+	}
+
+	files := opts.Args()
+	p.names = len(files) > 0
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	// If you are NOT checking...
+	if !*check {
+		if *recursive {
+			files = w.expand(files)
+		}
+		sums := make([]result, len(files))
+		// Files are hashed in parallel, but standard input is read in
+		// turn, as it may be named more than once.
+		parallel.Do(len(files), *jobs, func(i int) {
+			if files[i] != "-" {
+				sums[i] = sumFile(p.alg, files[i])
+			}
+		}, func(i int) {
+			r := sums[i]
+			if files[i] == "-" {
+				r = sumFile(p.alg, files[i])
+			}
+			if r.err != nil {
+				report.Error(files[i], r.err)
+				return
+			}
+			fmt.Print(p.format(r, files[i]))
+		})
+		return report.Status()
+	}
+
+	// Check the files...
+	for _, file := range files {
+		if !v.check(file) {
+			report.Fail(1)
+		}
+	}
+	return report.Status()
+}
@@ -0,0 +1,39 @@
+//
+// sumtool_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sumtool
+
+import "bytes"
+import "fmt"
+import "os"
+import "path/filepath"
+import "testing"
+
+import "github.com/aisola/go-coreutils/checksum"
+import "github.com/aisola/go-coreutils/internal/parallel"
+
+// BenchmarkSumFiles hashes 64 files of 1 MiB with up to 8 jobs. The
+// speedup depends on the number of processors.
+func BenchmarkSumFiles(b *testing.B) {
+	dir := b.TempDir()
+	data := bytes.Repeat([]byte{0x5a}, 1<<20)
+	names := make([]string, 64)
+	for i := range names {
+		names[i] = filepath.Join(dir, fmt.Sprint(i))
+		if err := os.WriteFile(names[i], data, 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	for _, jobs := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			b.SetBytes(int64(len(data) * len(names)))
+			sums := make([]result, len(names))
+			for n := 0; n < b.N; n++ {
+				parallel.Do(len(names), jobs, func(i int) {
+					sums[i] = sumFile(checksum.SHA256, names[i])
+				}, func(i int) {})
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+//
+// walk.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package sumtool
+
+import "io/fs"
+import "os"
+import "path"
+import "path/filepath"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+
+// walker finds the files under the directories given with --recursive.
+type walker struct {
+	exclude []string      // the patterns of --exclude
+	skip    []os.FileInfo // files that are not part of the tree, such as the output
+	report  *diag.Reporter
+}
+
+// excluded reports whether the file or directory at rel, relative to the
+// directory walked, is left out by --exclude. A pattern matches either the
+// base name or the whole relative path, with slashes between its elements.
+func (w *walker) excluded(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range w.exclude {
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// skipped reports whether info is one of the files left out of the tree.
+func (w *walker) skipped(info os.FileInfo) bool {
+	for _, other := range w.skip {
+		if os.SameFile(info, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// files returns the regular files under root in sorted order, named by
+// joining root and their path within it. Links are followed to files, not
+// to directories.
+func (w *walker) files(root string) []string {
+	var files []string
+	filepath.WalkDir(root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			w.report.Error(name, err)
+			return nil
+		}
+		if name == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, name)
+		if err == nil && w.excluded(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			w.report.Error(name, err)
+			return nil
+		}
+		if info.Mode().IsRegular() && !w.skipped(info) {
+			files = append(files, name)
+		}
+		return nil
+	})
+	return files
+}
+
+// expand replaces the directories among the operands by the files under
+// them, leaving the other operands as they are.
+func (w *walker) expand(operands []string) []string {
+	var files []string
+	for _, name := range operands {
+		if info, err := os.Stat(name); name != "-" && err == nil && info.IsDir() {
+			files = append(files, w.files(name)...)
+		} else {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// regularFile returns the information of the named file, or of standard
+// input or output for "-", if it is a regular file that could end up in the
+// tree walked.
+func regularFile(name string, std *os.File) os.FileInfo {
+	var info os.FileInfo
+	var err error
+	if name == "-" {
+		info, err = std.Stat()
+	} else {
+		info, err = os.Stat(name)
+	}
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+	return info
+}
@@ -0,0 +1,105 @@
+//
+// units.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package units parses counts and sizes with the multiplier suffixes that
+// GNU coreutils accepts, such as the "1M" in "head -c 1M":
+//
+//	b            512
+//	kB KB        1000
+//	K k KiB      1024
+//	MB           1000*1000
+//	M m MiB      1024*1024
+//
+// and so on for G, T, P, E, Z, Y, R and Q. A suffix may also end in "D" for
+// a power of 1000, as in "KD".
+package units
+
+import "errors"
+import "math"
+import "math/bits"
+import "strings"
+import "syscall"
+
+var (
+	// ErrSyntax means the string is not a count.
+	ErrSyntax = errors.New("invalid number")
+
+	// ErrRange means the count does not fit in 64 bits. Its message is the
+	// one GNU prints, "Value too large for defined data type".
+	ErrRange error = syscall.EOVERFLOW
+)
+
+// powers lists the suffixes that stand for a power of 1000 or 1024.
+const powers = "KMGTPEZYRQ"
+
+// ParseSize parses s, a decimal number followed by an optional multiplier
+// suffix. Leading white space and a plus sign are allowed, as strtoul
+// allows them.
+func ParseSize(s string) (uint64, error) {
+	s = strings.TrimLeft(s, " \t\n\v\f\r")
+	s = strings.TrimPrefix(s, "+")
+	digits := 0
+	for digits < len(s) && '0' <= s[digits] && s[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 {
+		return 0, ErrSyntax
+	}
+
+	var n uint64
+	overflow := false
+	for _, c := range []byte(s[:digits]) {
+		hi, lo := bits.Mul64(n, 10)
+		n = lo + uint64(c-'0')
+		overflow = overflow || hi != 0 || n < lo
+	}
+	suffix := s[digits:]
+	if suffix == "" {
+		return checkRange(n, overflow)
+	}
+
+	var multiplier uint64
+	var power int
+	switch c := suffix[0]; {
+	case c == 'b':
+		multiplier, suffix = 512, suffix[1:]
+	case c == 'k' || c == 'm':
+		power, suffix = strings.IndexByte(powers, c-'a'+'A')+1, suffix[1:]
+	case strings.IndexByte(powers, c) >= 0:
+		power, suffix = strings.IndexByte(powers, c)+1, suffix[1:]
+	default:
+		return 0, ErrSyntax
+	}
+	if power > 0 {
+		base := uint64(1024)
+		switch suffix {
+		case "":
+		case "iB":
+		case "B", "D":
+			base = 1000
+		default:
+			return 0, ErrSyntax
+		}
+		suffix = ""
+		multiplier = 1
+		for i := 0; i < power; i++ {
+			hi, lo := bits.Mul64(multiplier, base)
+			multiplier = lo
+			overflow = overflow || hi != 0
+		}
+	}
+	if suffix != "" {
+		return 0, ErrSyntax
+	}
+	hi, lo := bits.Mul64(n, multiplier)
+	return checkRange(lo, overflow || hi != 0)
+}
+
+func checkRange(n uint64, overflow bool) (uint64, error) {
+	if overflow {
+		return math.MaxUint64, ErrRange
+	}
+	return n, nil
+}
@@ -0,0 +1,171 @@
+//
+// users.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package users resolves the IDs of users and groups to their names and
+// back, for the utilities that print or take owners, such as ls and stat.
+//
+// A lookup goes through package os/user, which asks the C library, and so
+// the name services such as LDAP, when cgo is available. If that fails, the
+// /etc/passwd and /etc/group files are read instead, once. Every result is
+// remembered, so that a long listing looks each owner up only once, and the
+// functions are safe to call from several goroutines.
+//
+// IDs are strings, as in os/user: decimal numbers on Unix and security
+// identifiers on Windows.
+package users
+
+import "bufio"
+import "os"
+import "os/user"
+import "strings"
+import "sync"
+
+// table looks up the entries of one database, of users or of groups.
+type table struct {
+	file       string // the fallback, such as /etc/passwd
+	lookupName func(id string) (string, error)
+	lookupID   func(name string) (string, error)
+
+	mu      sync.Mutex
+	names   map[string]result // the names found by ID
+	ids     map[string]result // the IDs found by name
+	entries []entry           // the entries of the file, once read
+	read    bool
+}
+
+type result struct {
+	value string
+	ok    bool
+}
+
+// entry is the name and ID on a line of the file.
+type entry struct {
+	name, id string
+}
+
+var passwd = &table{
+	file: "/etc/passwd",
+	lookupName: func(id string) (string, error) {
+		u, err := user.LookupId(id)
+		if err != nil {
+			return "", err
+		}
+		return u.Username, nil
+	},
+	lookupID: func(name string) (string, error) {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return u.Uid, nil
+	},
+}
+
+var group = &table{
+	file: "/etc/group",
+	lookupName: func(id string) (string, error) {
+		g, err := user.LookupGroupId(id)
+		if err != nil {
+			return "", err
+		}
+		return g.Name, nil
+	},
+	lookupID: func(name string) (string, error) {
+		g, err := user.LookupGroup(name)
+		if err != nil {
+			return "", err
+		}
+		return g.Gid, nil
+	},
+}
+
+// UserName returns the name of the user with the given ID, and false if
+// there is none.
+func UserName(uid string) (string, bool) { return passwd.name(uid) }
+
+// UserID returns the ID of the named user, and false if there is none.
+func UserID(name string) (string, bool) { return passwd.id(name) }
+
+// GroupName returns the name of the group with the given ID, and false if
+// there is none.
+func GroupName(gid string) (string, bool) { return group.name(gid) }
+
+// GroupID returns the ID of the named group, and false if there is none.
+func GroupID(name string) (string, bool) { return group.id(name) }
+
+func (t *table) name(id string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r, ok := t.names[id]; ok {
+		return r.value, r.ok
+	}
+	var r result
+	if name, err := t.lookupName(id); err == nil {
+		r = result{name, true}
+	} else {
+		for _, e := range t.fileEntries() {
+			if e.id == id {
+				r = result{e.name, true}
+				break
+			}
+		}
+	}
+	if t.names == nil {
+		t.names = make(map[string]result)
+	}
+	t.names[id] = r
+	return r.value, r.ok
+}
+
+func (t *table) id(name string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r, ok := t.ids[name]; ok {
+		return r.value, r.ok
+	}
+	var r result
+	if id, err := t.lookupID(name); err == nil {
+		r = result{id, true}
+	} else {
+		for _, e := range t.fileEntries() {
+			if e.name == name {
+				r = result{e.id, true}
+				break
+			}
+		}
+	}
+	if t.ids == nil {
+		t.ids = make(map[string]result)
+	}
+	t.ids[name] = r
+	return r.value, r.ok
+}
+
+// fileEntries reads the file on the first call. Its lines hold fields
+// separated by colons, the name first and the ID third; the first line for
+// a name or an ID wins, as in the C library. A file that cannot be read
+// has no entries.
+func (t *table) fileEntries() []entry {
+	if t.read {
+		return t.entries
+	}
+	t.read = true
+	f, err := os.Open(t.file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' || line[0] == '+' || line[0] == '-' {
+			continue // The + and - lines of NIS name no one.
+		}
+		if fields := strings.Split(line, ":"); len(fields) > 2 {
+			t.entries = append(t.entries, entry{fields[0], fields[2]})
+		}
+	}
+	return t.entries
+}
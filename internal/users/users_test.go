@@ -0,0 +1,68 @@
+//
+// users_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package users
+
+import "errors"
+import "os"
+import "path/filepath"
+import "testing"
+
+// The file is used when os/user fails, and every answer is remembered.
+func TestFallback(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "passwd")
+	err := os.WriteFile(file, []byte("# users\nroot:x:0:0:root:/root:/bin/sh\n+nis::::::\n"+
+		"daemon:x:1:1::/:/bin/false\nalias:x:1:1::/:/bin/false\nshort:x\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	fail := func(string) (string, error) {
+		calls++
+		return "", errors.New("no name service")
+	}
+	tab := &table{file: file, lookupName: fail, lookupID: fail}
+	for _, test := range []struct {
+		lookup      func(string) (string, bool)
+		key, value  string
+		ok          bool
+		callsBefore int
+	}{
+		{tab.name, "0", "root", true, 0},
+		{tab.name, "1", "daemon", true, 1},
+		{tab.name, "2", "", false, 2},
+		{tab.id, "alias", "1", true, 3},
+		{tab.id, "+nis", "", false, 4},
+		{tab.id, "short", "", false, 5},
+		{tab.name, "0", "root", true, 6},
+		{tab.name, "2", "", false, 6},
+		{tab.id, "alias", "1", true, 6},
+	} {
+		if calls != test.callsBefore {
+			t.Errorf("%d lookups before %s, want %d", calls, test.key, test.callsBefore)
+		}
+		if value, ok := test.lookup(test.key); value != test.value || ok != test.ok {
+			t.Errorf("lookup of %s: got %q, %v; want %q, %v", test.key, value, ok, test.value, test.ok)
+		}
+	}
+
+	// The file is read once.
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := tab.name("1"); name != "daemon" || !ok {
+		t.Errorf("name of 1 after the file was removed: got %q, %v", name, ok)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	found := func(s string) (string, error) { return "found-" + s, nil }
+	tab := &table{file: "/nonexistent", lookupName: found, lookupID: found}
+	if name, ok := tab.name("7"); name != "found-7" || !ok {
+		t.Errorf("name of 7: got %q, %v", name, ok)
+	}
+	if id, ok := tab.id("x"); id != "found-x" || !ok {
+		t.Errorf("id of x: got %q, %v", id, ok)
+	}
+}
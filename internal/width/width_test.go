@@ -0,0 +1,33 @@
+//
+// width_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package width
+
+import "testing"
+
+// The expected widths are those of wcwidth in glibc's C.UTF-8 locale.
+func TestRune(t *testing.T) {
+	for _, test := range []struct {
+		r     rune
+		width int
+	}{
+		{'a', 1},
+		{' ', 1},
+		{'é', 1},
+		{'\t', 0},
+		{'\x01', 0},
+		{'\u0301', 0}, // combining acute accent
+		{'\u200b', 0}, // zero width space
+		{'日', 2},
+		{'\uff71', 1}, // halfwidth katakana a
+		{'\uff21', 2}, // fullwidth A
+		{'한', 2},
+		{'😀', 2},
+		{'\U00020000', 2},
+	} {
+		if got := Rune(test.r); got != test.width {
+			t.Errorf("Rune(%q) = %d, want %d", test.r, got, test.width)
+		}
+	}
+}
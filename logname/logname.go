@@ -4,13 +4,15 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package logname
 
-import "flag"
 import "fmt"
-import "os"
 import "os/user"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text = `
     Usage: logname
@@ -34,47 +36,48 @@ func GetCurrentUser(username *string) error {
 
 	current_user, err := user.Current()
 
-	*username = current_user.Username
-
 	if err != nil {
 		return err
 	}
 
+	*username = current_user.Username
+
 	return nil
 }
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("logname", Main)
+}
+
+// Main runs logname with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("logname")
+	opts := getopt.New("logname")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
-		fmt.Println(help)
-		os.Exit(0)
+		fmt.Println(help_text)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	if flag.NArg() > 0 {
-		fmt.Println(help)
-		os.Exit(0)
+	if opts.NArg() > 0 {
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(0)))
 	}
 
-	if flag.NArg() == 0 && flag.NFlag() == 0 {
-
-		var username string
-
-		err := GetCurrentUser(&username)
-
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-		}
-
-		fmt.Println(username)
-
+	var username string
+	if err := GetCurrentUser(&username); err != nil {
+		report.Errorf("no login name")
+		return report.Status()
 	}
-
+	fmt.Println(username)
+	return 0
 }
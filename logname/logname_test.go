@@ -0,0 +1,18 @@
+//
+// logname_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package logname
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "logname", []conformance.Case{
+		{Name: "extra-operand", Args: []string{"x"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
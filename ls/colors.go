@@ -0,0 +1,236 @@
+//
+// colors.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux || windows
+// +build linux windows
+
+package ls
+
+import "os"
+import "strings"
+
+import "github.com/aisola/go-coreutils/internal/lscolors"
+
+// colorWords are the arguments of --color.
+var colorWords = [][]string{
+	{"always", "yes", "force"},
+	{"never", "no", "none"},
+	{"auto", "tty", "if-tty"},
+}
+
+var (
+	colorWhen        = "never" // The key of --color.
+	useColor         = false   // Whether names are colored.
+	usedColor        = false   // Whether a color was printed already.
+	colorsAsReferent = false   // Whether links are colored as what they point to, for ln=target.
+	checkLinks       = false   // Whether the colors need to know what links point to.
+	lsColors         *lscolors.Colors
+)
+
+// Sets the colors up from LS_COLORS for --color. Without LS_COLORS, the
+// built-in colors are used if COLORTERM is set or TERM is a terminal type
+// that dircolors knows. A value of LS_COLORS that cannot be parsed turns
+// colors off.
+func setupColors() {
+	useColor = colorWhen == "always" || colorWhen == "auto" && isTerminal()
+	if !useColor {
+		return
+	}
+	value := os.Getenv("LS_COLORS")
+	if value == "" {
+		lsColors = lscolors.Default()
+		useColor = os.Getenv("COLORTERM") != "" || lscolors.KnownTerm(os.Getenv("TERM"))
+		return
+	}
+	var err error
+	if lsColors, err = lscolors.Parse(value); err != nil {
+		if err != lscolors.ErrUnparsable {
+			report.Warnf("%s", err)
+		}
+		report.Warnf("%s", lscolors.ErrUnparsable)
+		useColor = false
+		return
+	}
+	seq, _ := lsColors.Indicator("ln")
+	colorsAsReferent = seq == "target"
+	// As GNU ls, links are only followed when it changes their colors; otherwise the target of
+	// a link is shown as if it were missing, which has no color by default.
+	checkLinks = lsColors.IsColored("or") || lsColors.IsColored("ex") && colorsAsReferent ||
+		lsColors.IsColored("mi") && *longMode
+}
+
+// Reports whether links are followed, for their colors or, in long format,
+// for the indicators of their targets that -F and --file-type show.
+func followLinks() bool {
+	return checkLinks || *longMode && indicatorStyle >= indicatorFileType
+}
+
+// Returns a code for the output, preceded by the reset that comes before
+// the first code printed.
+func put(code string) string {
+	if !usedColor {
+		usedColor = true
+		return endColor() + code
+	}
+	return code
+}
+
+// Returns a color wrapped in the left and right codes.
+func indicator(seq string) string {
+	left, _ := lsColors.Indicator("lc")
+	right, _ := lsColors.Indicator("rc")
+	return put(left) + put(seq) + put(right)
+}
+
+// Returns what follows a colored name: the end code if there is one, else
+// the reset color.
+func endColor() string {
+	if end, ok := lsColors.Indicator("ec"); ok {
+		return put(end)
+	}
+	reset, _ := lsColors.Indicator("rs")
+	return indicator(reset)
+}
+
+// Returns the color of a file, or of the target of a link if target is
+// set, and false if it has none. linkOK reports whether the file a link
+// points to exists.
+func colorOf(name string, mode os.FileMode, nlink uint64, linkOK bool, target bool) (string, bool) {
+	code := "fi"
+	switch {
+	case target && !linkOK && lsColors.IsColored("mi"):
+		code = "mi"
+	case target && !linkOK:
+		code = "or"
+	case mode.IsRegular():
+		switch {
+		case mode&os.ModeSetuid != 0 && lsColors.IsColored("su"):
+			code = "su"
+		case mode&os.ModeSetgid != 0 && lsColors.IsColored("sg"):
+			code = "sg"
+		case mode&EXECUTABLE != 0 && lsColors.IsColored("ex"):
+			code = "ex"
+		case nlink > 1 && lsColors.IsColored("mh"):
+			code = "mh"
+		}
+	case mode.IsDir():
+		code = "di"
+		switch {
+		case mode&os.ModeSticky != 0 && mode&0002 != 0 && lsColors.IsColored("tw"):
+			code = "tw"
+		case mode&0002 != 0 && lsColors.IsColored("ow"):
+			code = "ow"
+		case mode&os.ModeSticky != 0 && lsColors.IsColored("st"):
+			code = "st"
+		}
+	case mode&SYMLINK != 0:
+		code = "ln"
+	case mode&os.ModeNamedPipe != 0:
+		code = "pi"
+	case mode&os.ModeSocket != 0:
+		code = "so"
+	case mode&os.ModeCharDevice != 0:
+		code = "cd"
+	case mode&os.ModeDevice != 0:
+		code = "bd"
+	default: // A file of another type.
+		code = "or"
+	}
+	if code == "fi" {
+		if seq, ok := lsColors.Extension(name); ok {
+			return seq, true
+		}
+	}
+	if code == "ln" && !linkOK && (colorsAsReferent || lsColors.IsColored("or")) {
+		code = "or"
+	}
+	return lsColors.Indicator(code)
+}
+
+// Returns the normal color, which each file listed starts with if it is set.
+func normalColor() string {
+	if !useColor || !lsColors.IsColored("no") {
+		return ""
+	}
+	seq, _ := lsColors.Indicator("no")
+	return indicator(seq)
+}
+
+// Returns a name in its color, from startCol on the line, followed by a
+// clear to the end of the line if it may wrap.
+func colorName(name, seq string, colored bool, startCol int) string {
+	if !colored && !lsColors.IsColored("no") {
+		return name
+	}
+	var s strings.Builder
+	if colored {
+		if lsColors.IsColored("no") {
+			s.WriteString(indicator(""))
+		}
+		s.WriteString(indicator(seq))
+	}
+	s.WriteString(name)
+	s.WriteString(endColor())
+	lineLength := terminalWidth
+	if lineLength == 0 {
+		lineLength = 80
+	}
+	if startCol/lineLength != (startCol+len(name)-1)/lineLength {
+		clear, _ := lsColors.Indicator("cl")
+		s.WriteString(clear)
+	}
+	return s.String()
+}
+
+// Returns the name of a file, shown as name, in its color if names are
+// colored, starting at startCol on the line.
+func colorizeAt(file os.FileInfo, name string, startCol int) string {
+	if !useColor {
+		return name
+	}
+	mode, linkOK := file.Mode(), false
+	if mode&SYMLINK != 0 && followLinks() {
+		if target := openSymlink(filePath(file)); target != nil {
+			linkOK = true
+			if colorsAsReferent {
+				mode = target.Mode()
+			}
+		}
+	}
+	seq, colored := colorOf(file.Name(), mode, getLinkCount(file), linkOK, false)
+	return colorName(name, seq, colored, startCol)
+}
+
+// Returns the path a link points to, shown as name, in the color of its
+// target, which is nil if it is missing, starting at startCol on the line.
+func colorizeTarget(path, name string, target os.FileInfo, startCol int) string {
+	if !useColor {
+		return name
+	}
+	var mode os.FileMode
+	var nlink uint64
+	if !followLinks() {
+		target = nil
+	}
+	if target != nil {
+		mode, nlink = target.Mode(), getLinkCount(target)
+	}
+	seq, colored := colorOf(path, mode, nlink, target != nil, true)
+	return colorName(name, seq, colored, startCol)
+}
+
+// Restores the default color at the end, unless the left and right codes
+// are the usual ones, which need no restoring.
+func restoreColor() string {
+	if !useColor || !usedColor {
+		return ""
+	}
+	left, _ := lsColors.Indicator("lc")
+	right, _ := lsColors.Indicator("rc")
+	if left == "\033[" && right == "m" {
+		return ""
+	}
+	return left + right
+}
@@ -0,0 +1,117 @@
+//
+// colors_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux
+// +build linux
+
+package ls
+
+import "os"
+import "path/filepath"
+import "strings"
+import "syscall"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+// TestColors lists the types of files the fixtures cannot hold, as GNU ls
+// colors them.
+func TestColors(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []struct {
+		name string
+		mode os.FileMode
+	}{{"sticky", os.ModeSticky | 0755}, {"other", 0757}, {"both", os.ModeSticky | 0757}} {
+		if err := os.Mkdir(filepath.Join(dir, d.name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(filepath.Join(dir, d.name), d.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []struct {
+		name string
+		mode os.FileMode
+	}{{"run", 0755}, {"setuid", os.ModeSetuid | 0644}, {"setgid", os.ModeSetgid | 0644}} {
+		if err := os.WriteFile(filepath.Join(dir, f.name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(filepath.Join(dir, f.name), f.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := syscall.Mkfifo(filepath.Join(dir, "fifo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sticky", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("missing", filepath.Join(dir, "broken")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		env    string
+		args   []string
+		stdout string
+	}{
+		{"TERM=xterm", []string{"-1"}, `\e[0m\e[30;42mboth\e[0m
+\e[01;36mbroken\e[0m
+\e[33mfifo\e[0m
+\e[01;36mlink\e[0m
+\e[34;42mother\e[0m
+\e[01;32mrun\e[0m
+\e[30;43msetgid\e[0m
+\e[37;41msetuid\e[0m
+\e[37;44msticky\e[0m
+`},
+		{"LS_COLORS=or=31:ln=target:ex=4", []string{"-1"}, `\e[0m\e[30;42mboth\e[0m
+\e[31mbroken\e[0m
+\e[33mfifo\e[0m
+\e[37;44mlink\e[0m
+\e[34;42mother\e[0m
+\e[4mrun\e[0m
+\e[30;43msetgid\e[0m
+\e[37;41msetuid\e[0m
+\e[37;44msticky\e[0m
+`},
+		{"LS_COLORS=su=0:sg=00:ow=:tw=1:st=2:pi=3", []string{"-1"}, `\e[0m\e[1mboth\e[0m
+\e[01;36mbroken\e[0m
+\e[3mfifo\e[0m
+\e[01;36mlink\e[0m
+\e[01;34mother\e[0m
+\e[01;32mrun\e[0m
+setgid
+setuid
+\e[2msticky\e[0m
+`},
+		// The targets of links are only colored when a missing one would be.
+		{"TERM=xterm", []string{"-l", "link", "broken"}, `\e[0m\e[01;36mbroken\e[0m -> missing
+\e[01;36mlink\e[0m -> sticky
+`},
+		{"LS_COLORS=mi=5", []string{"-l", "link", "broken"}, `\e[0m\e[01;36mbroken\e[0m -> \e[5mmissing\e[0m
+\e[01;36mlink\e[0m -> \e[37;44msticky\e[0m
+`},
+	} {
+		got := conformance.Exec(t, dir, conformance.Case{Args: append([]string{"--color=always"}, test.args...),
+			Env: []string{"COLORTERM=", test.env}})
+		var names []string
+		for _, line := range strings.SplitAfter(string(got.Stdout), "\n") {
+			if strings.HasPrefix(test.args[0], "-l") {
+				// Only the names of a long listing are compared.
+				if i := strings.Index(line, ":"); i >= 0 && !strings.HasPrefix(line, "total") {
+					line = line[i+4:]
+				} else {
+					continue
+				}
+			}
+			names = append(names, line)
+		}
+		stdout := strings.ReplaceAll(strings.Join(names, ""), "\x1b", `\e`)
+		if got.Status != 0 || stdout != test.stdout {
+			t.Errorf("%s ls %q: got %d,\n%s\nwant\n%s", test.env, test.args, got.Status, stdout, test.stdout)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+//
+// list.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux || windows
+// +build linux windows
+
+package ls
+
+import "fmt"
+import "os"
+import "path"
+import "strings"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+
+// namedFile is a file listed under another name than its base name, such
+// as an operand, which is shown as it was given, or the . and .. entries.
+type namedFile struct {
+	os.FileInfo
+	name string
+}
+
+func (f namedFile) Name() string { return f.name }
+
+var (
+	currentDir    = ""    // The directory being listed, ending with a slash, or "" for the operands.
+	printDirNames = false // Whether directories get a "name:" header.
+	listedOnce    = false // Whether a listing was printed already, so that the next is set apart.
+	activeDirs    []os.FileInfo
+)
+
+// Returns the path of a file of the current listing.
+func filePath(file os.FileInfo) string {
+	return currentDir + file.Name()
+}
+
+// Clears the lists of file information, for the next listing.
+func clearFiles() {
+	printOneLine = true
+	maxInodeLength, maxBlocksLength, maxLinkLength, maxUserLength, maxGroupLength = 0, 0, 0, 0, 0
+	maxSizeLength, maxMinorLength, prefixLength, totalCharLength, maxCharLength = 0, 0, 0, 0, 0
+	maxColumns, numOfRows, numOfFiles, lastRowCount = 0, 0, 0, 0
+	printOrder = printOrder[:0]
+	someQuoted = false
+	fileList = fileList[:0]
+	fileNameList = fileNameList[:0]
+	fileQuotedList = fileQuotedList[:0]
+	fileLengthList = fileLengthList[:0]
+	fileInodeList = fileInodeList[:0]
+	fileBlocksList = fileBlocksList[:0]
+	fileModeList = fileModeList[:0]
+	fileLinkList = fileLinkList[:0]
+	fileUserList = fileUserList[:0]
+	fileGroupList = fileGroupList[:0]
+	fileModDateList = fileModDateList[:0]
+	fileSizeList = fileSizeList[:0]
+	fileMinorList = fileMinorList[:0]
+}
+
+// Sorts the files, obtains their statistics and prints them. Files listed
+// before are set apart by a blank line.
+func printFiles(files []os.FileInfo, total bool) {
+	clearFiles()
+	fileList = append(fileList, files...)
+	sortFiles(fileList)
+	if listedOnce {
+		fmt.Println()
+	}
+	listedOnce = true
+	if (*longMode || *printBlocks) && total {
+		fmt.Println("total", getTotalBlocks(fileList))
+	}
+	if len(fileList) > 0 {
+		getFileStats()
+		printSwitch()
+	}
+}
+
+// Reads the entries of a directory in the order they are stored, which -U
+// keeps, with . and .. first for -a, leaving out those that are ignored.
+func readDirectory(path string) ([]os.FileInfo, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	entries, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+	var files []os.FileInfo
+	if *showHidden {
+		for _, name := range []string{".", ".."} {
+			if info, err := os.Lstat(path + name); err == nil && !ignored(name) {
+				files = append(files, namedFile{info, name})
+			}
+		}
+	}
+	for _, file := range entries {
+		if !ignored(file.Name()) {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// Reports whether an entry of a directory is not listed: one that is
+// hidden, by its leading '.' or by --hide, unless -a is given, or one that
+// matches a pattern of -I.
+func ignored(name string) bool {
+	if !*showHidden && (strings.HasPrefix(name, ".") || matchesAny(hidePatterns, name)) {
+		return true
+	}
+	return matchesAny(ignorePatterns, name)
+}
+
+// Reports whether name matches one of the shell patterns, in which, as in
+// GNU, a leading '.' is only matched by a '.'.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(pattern, ".") && !strings.HasPrefix(pattern, `\.`) {
+			continue
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats each operand and lists the files among them, then the contents of
+// the directories, in the order of the options. With -d, directories are
+// listed like files. A link to a directory is followed unless -l, -d or
+// -F is given.
+func listOperands(operands []string) {
+	var files, dirs []os.FileInfo
+	for _, operand := range operands {
+		info, err := os.Lstat(operand)
+		if err != nil {
+			report.Errorf("cannot access %s: %s", diag.Quote(operand), diag.Strerror(err))
+			continue
+		}
+		if info.Mode()&SYMLINK != 0 && !*longMode && !*dirOnly && indicatorStyle != indicatorClassify {
+			if target, err := os.Stat(operand); err == nil && target.IsDir() {
+				info = target
+			}
+		}
+		if info.IsDir() && !*dirOnly {
+			dirs = append(dirs, namedFile{info, operand})
+		} else {
+			files = append(files, namedFile{info, operand})
+		}
+	}
+	printDirNames = len(operands) > 1 || *recursive
+	if len(files) > 0 {
+		printFiles(files, false)
+	}
+	sortFiles(dirs)
+	for _, dir := range dirs {
+		listDirectory(dir.Name(), true)
+	}
+}
+
+// Lists the contents of a directory, then with -R those of its
+// subdirectories. A directory that cannot be read is a serious failure
+// when it was an operand and a minor one otherwise. So that a link cannot
+// make -R loop, a directory that contains itself is not listed again.
+func listDirectory(name string, operand bool) {
+	info, err := os.Stat(name)
+	if err == nil {
+		for _, active := range activeDirs {
+			if os.SameFile(info, active) {
+				report.Errorf("%s: not listing already-listed directory", diag.QuoteName(name))
+				report.Fail(2)
+				return
+			}
+		}
+	}
+	dir := name
+	if !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	files, err := readDirectory(dir)
+	if err != nil {
+		report.Warnf("cannot open directory %s: %s", diag.Quote(name), diag.Strerror(err))
+		if operand {
+			report.Fail(2)
+		} else {
+			report.Fail(1)
+		}
+		return
+	}
+	if printDirNames {
+		if listedOnce {
+			fmt.Println()
+			listedOnce = false
+		}
+		fmt.Printf("%s:\n", quoteDirName(name))
+	}
+	currentDir = dir
+	printFiles(files, true)
+	if !*recursive {
+		return
+	}
+	activeDirs = append(activeDirs, info)
+	for _, file := range append([]os.FileInfo(nil), fileList...) {
+		if file.IsDir() && file.Name() != "." && file.Name() != ".." {
+			listDirectory(dir+file.Name(), false)
+		}
+	}
+	activeDirs = activeDirs[:len(activeDirs)-1]
+}
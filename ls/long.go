@@ -0,0 +1,319 @@
+//
+// long.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux || windows
+// +build linux windows
+
+package ls
+
+import "errors"
+import "math/bits"
+import "os"
+import "strconv"
+import "strings"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/units"
+
+// How a size is written, as in GNU's human_readable.
+const (
+	humanAutoscale = 1 << iota // in the largest power of the base that keeps it at least 1
+	humanSI                    // with the suffix of the power, such as "K"
+	humanBase1024              // in powers of 1024 rather than 1000
+	humanB                     // with "B" after the suffix, or "iB" in powers of 1024
+)
+
+const (
+	statBlockSize = 512                // The size of the blocks stat counts.
+	powerLetters  = " KMGTPEZYRQ"      // The suffixes of the powers of the base.
+	blockSuffixes = "EGKMPTYZgkmt"     // The suffixes a block size may have.
+	blockLetters  = "eEgGkKmMpPtTyYzZ" // The suffixes a block size may start with.
+)
+
+var (
+	blockSize     uint64  = 1024 // The unit of the allocated sizes of -s and of the totals.
+	blockSizeOpts         = 0    // How the allocated sizes are written.
+	fileBlockSize uint64  = 1    // The unit of the sizes of -l.
+	fileBlockOpts         = 0    // How the sizes of -l are written.
+	blockSizeSpec *string        // The last argument of --block-size, or "human-readable" for -h.
+)
+
+// errBlockSuffix means a block size has a suffix that is not allowed.
+var errBlockSuffix = errors.New("invalid suffix")
+
+// Parses the argument of --block-size or the value of BLOCK_SIZE: "human-readable", "si", or
+// a size with an optional suffix, which is also written after the sizes if the number is left
+// out, as in "K" or "MiB". Like GNU, a quote before the size asks for grouped digits, which
+// the C locale does not group.
+func parseBlockSize(spec string) (uint64, int, error) {
+	spec = strings.TrimPrefix(spec, "'")
+	if spec != "" && strings.HasPrefix("human-readable", spec) {
+		return 1, humanAutoscale | humanSI | humanBase1024, nil
+	}
+	if spec != "" && strings.HasPrefix("si", spec) {
+		return 1, humanAutoscale | humanSI, nil
+	}
+	opts := 0
+	number := strings.TrimLeft(spec, "0123456789")
+	digits := spec[:len(spec)-len(number)]
+	if digits == "" {
+		if spec == "" || !strings.ContainsRune(blockLetters, rune(spec[0])) {
+			return 0, 0, units.ErrSyntax
+		}
+		digits, opts = "1", humanSI
+		if strings.HasSuffix(spec, "B") {
+			opts |= humanB
+		}
+		if !strings.HasSuffix(spec, "B") || strings.HasSuffix(spec, "iB") {
+			opts |= humanBase1024
+		}
+	}
+	if number != "" {
+		if !strings.ContainsRune(blockSuffixes, rune(number[0])) {
+			return 0, 0, errBlockSuffix
+		}
+		number = strings.ToUpper(number[:1]) + number[1:]
+	}
+	size, err := units.ParseSize(digits + number)
+	switch {
+	case err == units.ErrRange:
+		return 0, 0, err
+	case err != nil:
+		return 0, 0, errBlockSuffix
+	case size == 0:
+		return 0, 0, units.ErrSyntax
+	}
+	return size, opts, nil
+}
+
+// Sets the units of the sizes from LS_BLOCK_SIZE, BLOCK_SIZE or BLOCKSIZE, of which the first
+// two also apply to the sizes of -l, then from the last of -h and --block-size. A bad value in
+// the environment is ignored, and a bad argument is reported.
+func setupBlockSizes() bool {
+	blockSize, blockSizeOpts = 1024, 0
+	if _, ok := os.LookupEnv("POSIXLY_CORRECT"); ok {
+		blockSize = 512
+	}
+	spec, ok := os.LookupEnv("LS_BLOCK_SIZE")
+	if !ok {
+		spec, ok = os.LookupEnv("BLOCK_SIZE")
+	}
+	fileUnits := ok
+	if !ok {
+		spec, ok = os.LookupEnv("BLOCKSIZE")
+	}
+	if size, opts, err := parseBlockSize(spec); ok && err == nil {
+		blockSize, blockSizeOpts = size, opts
+	}
+	fileBlockSize, fileBlockOpts = 1, 0
+	if fileUnits {
+		fileBlockSize, fileBlockOpts = blockSize, blockSizeOpts
+	}
+	if blockSizeSpec == nil {
+		return true
+	}
+	size, opts, err := parseBlockSize(*blockSizeSpec)
+	switch err {
+	case nil:
+		blockSize, blockSizeOpts = size, opts
+		fileBlockSize, fileBlockOpts = size, opts
+		return true
+	case errBlockSuffix:
+		report.Errorf("invalid suffix in --block-size argument %s", diag.Quote(*blockSizeSpec))
+	case units.ErrRange:
+		report.Errorf("--block-size argument %s too large", diag.Quote(*blockSizeSpec))
+	default:
+		report.Errorf("invalid --block-size argument %s", diag.Quote(*blockSizeSpec))
+	}
+	return false
+}
+
+// Returns n units of from bytes in units of to bytes, rounded up, in the way opts asks for.
+// This is GNU's human_readable, which keeps a tenth of a unit below 10 when it scales.
+func humanReadable(n, from, to uint64, opts int) string {
+	base := uint64(1000)
+	if opts&humanBase1024 != 0 {
+		base = 1024
+	}
+	// The amount, its tenths, and how the rest compares with half a tenth: 0 for none, 1 for
+	// less, 2 for half and 3 for more.
+	var amt, tenths, rounding uint64
+	hi, lo := bits.Mul64(n, from)
+	switch {
+	case to <= from && from%to == 0 && hi == 0:
+		amt = lo / to
+	case to > from && to%from == 0:
+		divisor := to / from
+		r10 := n % divisor * 10
+		r2 := r10 % divisor * 2
+		amt, tenths = n/divisor, r10/divisor
+		switch {
+		case r2 == 0:
+		case r2 < divisor:
+			rounding = 1
+		case r2 == divisor:
+			rounding = 2
+		default:
+			rounding = 3
+		}
+	case hi >= to:
+		amt = 1<<64 - 1
+	default:
+		var rest uint64
+		amt, rest = bits.Div64(hi, lo, to)
+		if rest != 0 {
+			rounding = 1
+		}
+	}
+
+	exponent := -1
+	fraction := ""
+	if opts&humanAutoscale != 0 {
+		exponent = 0
+		if base <= amt {
+			for {
+				r10 := amt%base*10 + tenths
+				r2 := r10%base*2 + rounding>>1
+				amt /= base
+				tenths = r10 / base
+				switch {
+				case r2 < base && r2+rounding == 0:
+					rounding = 0
+				case r2 < base:
+					rounding = 1
+				case base < r2+rounding:
+					rounding = 3
+				default:
+					rounding = 2
+				}
+				exponent++
+				if amt < base || exponent == len(powerLetters)-1 {
+					break
+				}
+			}
+			if amt < 10 {
+				if rounding > 0 {
+					tenths, rounding = tenths+1, 0
+					if tenths == 10 {
+						amt, tenths = amt+1, 0
+					}
+				}
+				if amt < 10 {
+					fraction = "." + strconv.FormatUint(tenths, 10)
+					tenths = 0
+				}
+			}
+		}
+	}
+	if tenths+rounding > 0 {
+		amt++
+		if opts&humanAutoscale != 0 && amt == base && exponent < len(powerLetters)-1 {
+			amt, exponent, fraction = 1, exponent+1, ".0"
+		}
+	}
+
+	s := strconv.FormatUint(amt, 10) + fraction
+	if opts&humanSI != 0 {
+		if exponent < 0 {
+			exponent = 0
+			for power := uint64(1); power < to && exponent < len(powerLetters)-1; power *= base {
+				exponent++
+			}
+		}
+		switch {
+		case exponent == 1 && opts&humanBase1024 == 0:
+			s += "k"
+		case exponent > 0:
+			s += powerLetters[exponent : exponent+1]
+		}
+		if opts&humanB != 0 {
+			if exponent > 0 && opts&humanBase1024 != 0 {
+				s += "i"
+			}
+			s += "B"
+		}
+	}
+	return s
+}
+
+// Returns the allocated size of a file in blocks of --block-size.
+func getBlocksString(file os.FileInfo) string {
+	return humanReadable(getBlocks(file), statBlockSize, blockSize, blockSizeOpts)
+}
+
+// Returns the total allocated size of the files, for the "total" line.
+func getTotalBlocks(files []os.FileInfo) string {
+	var total uint64
+	for _, file := range files {
+		total += getBlocks(file)
+	}
+	return humanReadable(total, statBlockSize, blockSize, blockSizeOpts)
+}
+
+// Returns the mode of a file the way GNU writes it, such as "drwxr-sr-x": the type, then the
+// permissions, with the set-ID and sticky bits in place of the execute bits.
+func modeString(mode os.FileMode) string {
+	buf := []byte("?rwxrwxrwx")
+	switch {
+	case mode.IsRegular():
+		buf[0] = '-'
+	case mode&os.ModeDir != 0:
+		buf[0] = 'd'
+	case mode&os.ModeSymlink != 0:
+		buf[0] = 'l'
+	case mode&os.ModeNamedPipe != 0:
+		buf[0] = 'p'
+	case mode&os.ModeSocket != 0:
+		buf[0] = 's'
+	case mode&os.ModeCharDevice != 0:
+		buf[0] = 'c'
+	case mode&os.ModeDevice != 0:
+		buf[0] = 'b'
+	}
+	for bit := 0; bit < 9; bit++ {
+		if mode&(1<<uint(8-bit)) == 0 {
+			buf[bit+1] = '-'
+		}
+	}
+	special := func(set bool, index int, letter byte) {
+		if !set {
+			return
+		}
+		if buf[index] == '-' {
+			buf[index] = letter - 'a' + 'A'
+		} else {
+			buf[index] = letter
+		}
+	}
+	special(mode&os.ModeSetuid != 0, 3, 's')
+	special(mode&os.ModeSetgid != 0, 6, 's')
+	special(mode&os.ModeSticky != 0, 9, 't')
+	return string(buf)
+}
+
+// Pads s with spaces on the left to width.
+func padLeft(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-len(s)) + s
+}
+
+// Pads s with spaces on the right to width.
+func padRight(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
+
+// Returns an owner or group column: a name padded on the right, or an ID, which has no name or
+// was asked for with -n, padded on the left like the other numbers.
+func padOwner(name, id string, width int) string {
+	if name == id {
+		return padLeft(name, width) + " "
+	}
+	return padRight(name, width) + " "
+}
@@ -0,0 +1,115 @@
+//
+// long_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux
+// +build linux
+
+package ls
+
+import "fmt"
+import "os"
+import "path/filepath"
+import "strings"
+import "syscall"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+import "github.com/aisola/go-coreutils/internal/users"
+
+// The sizes GNU ls prints for files of these sizes.
+func TestHumanReadable(t *testing.T) {
+	for _, test := range []struct {
+		n, from, to uint64
+		opts        int
+		want        string
+	}{
+		{1023, 1, 1, humanAutoscale | humanSI | humanBase1024, "1023"},
+		{1024, 1, 1, humanAutoscale | humanSI | humanBase1024, "1.0K"},
+		{1030, 1, 1, humanAutoscale | humanSI | humanBase1024, "1.1K"},
+		{10239, 1, 1, humanAutoscale | humanSI | humanBase1024, "10K"},
+		{10241, 1, 1, humanAutoscale | humanSI | humanBase1024, "11K"},
+		{5000000, 1, 1, humanAutoscale | humanSI | humanBase1024, "4.8M"},
+		{1023, 1, 1, humanAutoscale | humanSI, "1.1k"},
+		{1023, 1, 1000, humanSI | humanB, "2kB"},
+		{8, 512, 1048576, humanSI | humanBase1024 | humanB, "1MiB"},
+		{8, 512, 300, 0, "14"},
+		{11928, 512, 1024, 0, "5964"},
+	} {
+		if got := humanReadable(test.n, test.from, test.to, test.opts); got != test.want {
+			t.Errorf("humanReadable(%d, %d, %d, %#x) = %q, want %q", test.n, test.from, test.to, test.opts, got, test.want)
+		}
+	}
+}
+
+// --si writes the sizes in powers of 1000, and whichever of it, -h and
+// --block-size comes last applies.
+func TestSI(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "f"), make([]byte, 1500), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		args []string
+		size string
+	}{
+		{[]string{"--si"}, "1.5k"},
+		{[]string{"--si", "-h"}, "1.5K"},
+		{[]string{"-h", "--si"}, "1.5k"},
+		{[]string{"--si", "--block-size=1"}, "1500"},
+		{[]string{"--block-size=si"}, "1.5k"},
+	} {
+		got := conformance.Exec(t, dir, conformance.Case{Args: append(append([]string{"-go"}, test.args...), "f")})
+		fields := strings.Fields(string(got.Stdout))
+		if got.Status != 0 || len(fields) < 3 || fields[2] != test.size {
+			t.Errorf("ls -go %q f: got %d, %q; want the size %s", test.args, got.Status, got.Stdout, test.size)
+		}
+	}
+}
+
+// TestLongColumns checks the columns that depend on the file system: the
+// inode numbers, allocated sizes, link counts and owners.
+func TestLongColumns(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big"), make([]byte, 5000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(filepath.Join(dir, "big"), filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "small"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := conformance.Exec(t, dir, conformance.Case{Args: []string{"-lis", "--author"}})
+	lines := strings.Split(strings.TrimSuffix(string(got.Stdout), "\n"), "\n")
+	var total uint64
+	var want []string
+	for _, name := range []string{"big", "link", "small"} {
+		info, err := os.Lstat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := info.Sys().(*syscall.Stat_t)
+		total += uint64(st.Blocks)
+		owner, ok := users.UserName(fmt.Sprint(st.Uid))
+		if !ok {
+			owner = fmt.Sprint(st.Uid)
+		}
+		group, ok := users.GroupName(fmt.Sprint(st.Gid))
+		if !ok {
+			group = fmt.Sprint(st.Gid)
+		}
+		want = append(want, strings.Join([]string{fmt.Sprint(st.Ino), fmt.Sprint((st.Blocks + 1) / 2),
+			modeString(info.Mode()), fmt.Sprint(st.Nlink), owner, group, owner, fmt.Sprint(info.Size()),
+			info.ModTime().Format("Jan 2 15:04"), name}, " "))
+	}
+	want = append([]string{fmt.Sprintf("total %d", (total+1)/2)}, want...)
+	for i := range lines {
+		lines[i] = strings.Join(strings.Fields(lines[i]), " ")
+	}
+	if got.Status != 0 || strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ls -lis --author: got %d,\n%s\nwant\n%s", got.Status, strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -5,64 +5,151 @@
 // Written By: Michael Murphy, Abram C. Isola
 //
 
-// +build linux
+//go:build linux || windows
+// +build linux windows
 
-
-/* TODO:
- * Add (t), sort by modification time, newest first.
- * Add (s, size), print the allocated size of each file, in blocks.
- * Add (S), sort by file size.
- * Add (q, quote-name), enclose entry names in double quotes.
- */
-package main
+package ls
 
 import "fmt"
-import "io/ioutil"
 import "os"
-import "os/exec"
 import "strings"
-import "flag"
-import "unsafe"
 import "runtime"
-import "syscall"
-import "time"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/quotearg"
+import "github.com/aisola/go-coreutils/internal/users"
 
 const ( // Constant variables used throughout the program.
-	TERMINAL_INFO    = 0x5413         // Used in the getTerminalWidth function
-	EXECUTABLE       = 0111           // File executable bit
-	SYMLINK          = os.ModeSymlink // Symlink bit
-	CYAN_SYMLINK     = "\x1b[36;1m"   // Cyan terminal color
-	BLUE_DIR         = "\x1b[34;1m"   // Blue terminal color
-	GREEN_EXECUTABLE = "\x1b[32;1m"   // Green terminal color
-	RESET            = "\x1b[0m"      // Reset terminal color
-	SPACING          = 1              // Spacing between columns
-	DATE_FORMAT      = "Jan _2 15:04" // Format date
-	DATE_YEAR_FORMAT = "Jan _2  2006" // If the file is from a previous year
+	EXECUTABLE = 0111           // File executable bit
+	SYMLINK    = os.ModeSymlink // Symlink bit
+	SPACING    = 1              // Spacing between columns
 
 	help_text string = `
-    Usage: ls [OPTION]...
+    Usage: ls [OPTION]... [FILE]...
     
-    list files and directories in working directory
+    List information about the FILEs (the current directory by default): the files first,
+    then the contents of each directory.
 
         --help        display this help and exit
         --version     output version information and exit
 
-        -a    include hidden files and directories
+        -a, --all
+              include hidden files and directories
+
+        --author
+              with -l, print the author of each file, who is its owner
+
+        -b, --escape
+              print C-style escapes for nongraphic characters, like --quoting-style=escape
+
+        --block-size=SIZE
+              with -l and -s, scale sizes by SIZE: a size such as 1K or 1MB, which is also written
+              after the sizes if its number is left out, as in K or MB; human-readable (-h); or si
+              (--si), which is like -h with powers of 1000; LS_BLOCK_SIZE or BLOCK_SIZE is the
+              default
         
-        -d, -directory
+        -c    with -lt: sort by, and show, ctime (time of last change of file status information);
+              with -l: show ctime and sort by name; otherwise: sort by ctime, newest first
+
+        --color[=WHEN]
+              color the names of files by their type: always (the default when WHEN is omitted),
+              never (the default when --color is not given) or auto; see LS_COLORS and dircolors
+
+        -d, --directory
               list only directories and not their contents
+
+        -F, --classify[=WHEN]
+              append an indicator (one of */=@|) to entries: always (the default when WHEN is
+              omitted), never or auto, which is only on a terminal
+
+        --file-type
+              likewise, except do not append '*'
+
+        --full-time
+              like -l --time-style=full-iso
+
+        -g    like -l, but do not list the owner
         
-        -h, -human-readable
-              with -l, print sizes in human readable format
+        --group-directories-first
+              group directories before files; can be augmented with a --sort option, but any
+              use of --sort=none (-U) disables grouping
+
+        -h, --human-readable
+              with -l and -s, print sizes in human readable format, such as 1K 234M 2G
+
+        --si  likewise, but use powers of 1000 not 1024
+
+        --hide=PATTERN
+              do not list entries of directories that match the shell PATTERN, unless -a is given
+
+        -i, --inode
+              print the index number of each file
+
+        -I, --ignore=PATTERN
+              do not list entries of directories that match the shell PATTERN, even with -a
 
         -l    use a long listing format
         
-        -n, -numeric-uid-gid
+        -n, --numeric-uid-gid (unavailable on Windows)
               list numeric uid/gid's instead of names
 
-        -r, -reverse
+        -o    like -l, but do not list the group
+
+        -p    append / indicator to directories
+
+        -q, --hide-control-chars
+              print ? instead of nongraphic characters (the default on a terminal)
+
+        --show-control-chars
+              show nongraphic characters as-is (the default, unless on a terminal)
+
+        -Q, --quote-name
+              enclose entry names in double quotes, like --quoting-style=c
+
+        --quoting-style=WORD
+              use quoting style WORD for entry names: literal, shell, shell-always, shell-escape,
+              shell-escape-always, c, c-maybe, escape, locale, clocale; QUOTING_STYLE is the
+              default, or else shell-escape on a terminal and literal otherwise
+
+        -r, --reverse
               reverse order while sorting
+
+        -R, --recursive
+              list subdirectories recursively
+
+        -s, --size
+              print the allocated size of each file, in blocks of --block-size, and the total for
+              each directory
               
+        -S    sort by file size, largest first
+
+        --sort=WORD
+              sort by WORD instead of name: none (-U), size (-S), time (-t), version (-v),
+              extension (-X), width
+
+        --time=WORD
+              change the default of using modification times; access time (-u): atime, access,
+              use; change time (-c): ctime, status; with -l, WORD determines which time to show;
+              with --sort=time, sort by WORD (newest first)
+
+        --time-style=STYLE
+              with -l, show times in STYLE: full-iso, long-iso, iso, locale (the default), or
+              +FORMAT, which date formats, with a second FORMAT for the times of the last six
+              months after a newline; TIME_STYLE is the default
+
+        -t    sort by time, newest first; see --time
+
+        -u    with -lt: sort by, and show, access time; with -l: show access time and sort by
+              name; otherwise: sort by access time, newest first
+
+        -U    do not sort; list entries in directory order
+
+        -v    natural sort of (version) numbers within text
+
+        -X    sort alphabetically by entry extension
+
         -1    list in a single column
 `
 	version_text = `
@@ -76,187 +163,78 @@ const ( // Constant variables used throughout the program.
 )
 
 var ( // Default flags and variables.
-	help            = flag.Bool("help", false, "display help information")
-	version         = flag.Bool("version", false, "display version information")
-	showHidden      = flag.Bool("a", false, "list hidden files and directories")
-	dirOnly         = flag.Bool("d", false, "list only directories and not their contents")
-	dirOnlyLong     = flag.Bool("directory", false, "list only directories and not their contents")
-	human           = flag.Bool("h", false, "print sizes in human-readable format")
-	humanLong       = flag.Bool("human-readable", false, "print sizes in human-readable format")
-	longMode        = flag.Bool("l", false, "use a long listing format")
-	numericIDs      = flag.Bool("n", false, "list numeric uid/gid's instead of names.")
-	numericIDsLong  = flag.Bool("numeric-uid-gid", false, "list numeric uid/gid's instead of names.")
-	reversed        = flag.Bool("r", false, "reverse order while sorting")
-	reversedLong    = flag.Bool("reverse", false, "reverse order while sorting")
-	singleColumn    = flag.Bool("1", false, "list files by one column")
-	printOneLine    = true                    // list in a single columnlist in a single columnets whether or not to print on one row.
-	terminalWidth   = int(getTerminalWidth()) // Grabs information on the current terminal width.
-	maxIDLength     = 0                       // Statistics for the longest id name length.
-	maxSizeLength   = 0                       // Statistics for the longest file size length.
-	totalCharLength = 0                       // Statistics for the total number of characters.
-	maxCharLength   = 0                       // Statistics for maximum file name length.
-	maxColumns      = 0                       // Statistics for the maximum number of columns
-	numOfRows       = 0                       // Statistics for the maximum number of rows.
-	numOfFiles      = 0                       // Statistics for the number of files.
-	lastRowCount    = 0                       // The number of files on the last row.
-	printOrder      = make([]int, 0)          // The printing order.
-	fileList        = make([]os.FileInfo, 0)  // A list of all files being processed
-	fileLengthList  = make([]int, 0)          // A list of file character lengths
-	fileModeList    = make([]string, 0)       // A list of file mode strings
-	fileUserList    = make([]string, 0)       // A list of user values
-	fileGroupList   = make([]string, 0)       // A list of group values
-	fileModDateList = make([]string, 0)       // A list of file modication times.
-	fileSizeList    = make([]string, 0)       // A list of file sizes.
+	opts            *getopt.Set
+	report          *diag.Reporter
+	showHidden      *bool
+	dirOnly         *bool
+	longMode        *bool
+	printInode      *bool
+	printBlocks     *bool
+	printAuthor     *bool
+	numericIDs      *bool
+	reversed        *bool
+	recursive       *bool
+	dirsFirst       *bool
+	singleColumn    *bool
+	sortType        = "name"                 // The key of --sort.
+	sortSpecified   = false                  // Whether a sort option was given.
+	badArgument     = false                  // Whether argmatch rejected the argument of an option.
+	hidePatterns    []string                 // The patterns of --hide, which -a overrides.
+	ignorePatterns  []string                 // The patterns of -I.
+	printOwner      = true                   // Whether long mode lists the owner, which -g turns off.
+	printGroup      = true                   // Whether long mode lists the group, which -o turns off.
+	timeType        = "mtime"                // The key of --time.
+	printOneLine    = true                   // list in a single columnlist in a single columnets whether or not to print on one row.
+	terminalWidth   = 0                      // The current terminal width.
+	maxInodeLength  = 0                      // Statistics for the longest inode number length.
+	maxBlocksLength = 0                      // Statistics for the longest allocated size length.
+	maxLinkLength   = 0                      // Statistics for the longest link count length.
+	maxUserLength   = 0                      // Statistics for the longest user name length.
+	maxGroupLength  = 0                      // Statistics for the longest group name length.
+	maxSizeLength   = 0                      // Statistics for the longest file size length.
+	maxMinorLength  = 0                      // Statistics for the longest minor device number length.
+	prefixLength    = 0                      // The length of the inode numbers and sizes before names.
+	totalCharLength = 0                      // Statistics for the total number of characters.
+	maxCharLength   = 0                      // Statistics for maximum file name length.
+	maxColumns      = 0                      // Statistics for the maximum number of columns
+	numOfRows       = 0                      // Statistics for the maximum number of rows.
+	numOfFiles      = 0                      // Statistics for the number of files.
+	lastRowCount    = 0                      // The number of files on the last row.
+	printOrder      = make([]int, 0)         // The printing order.
+	fileList        = make([]os.FileInfo, 0) // A list of all files being processed
+	fileLengthList  = make([]int, 0)         // A list of file character lengths
+	fileInodeList   = make([]string, 0)      // A list of inode numbers
+	fileBlocksList  = make([]string, 0)      // A list of allocated sizes
+	fileModeList    = make([]string, 0)      // A list of file mode strings
+	fileLinkList    = make([]string, 0)      // A list of hard link counts
+	fileUserList    = make([]string, 0)      // A list of user values
+	fileGroupList   = make([]string, 0)      // A list of group values
+	fileModDateList = make([]string, 0)      // A list of file modication times.
+	fileSizeList    = make([]string, 0)      // A list of file sizes, or major device numbers.
+	fileMinorList   = make([]string, 0)      // A list of minor device numbers, or "" for files.
 )
 
-// Check initial state of flags.
-func processFlags() {
-	flag.Parse()
-	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
-	}
-	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
-	}
-	if *humanLong {
-		*human = true
-	}
-	if *reversedLong {
-		*reversed = true
-	}
-	if *numericIDsLong {
-		*numericIDs = true
-	}
-}
-
-// Stores information regarding the terminal size.
-type termsize struct {
-	Row, Col, Xpixel, Ypixel uint16
-}
-
-// Obtains the current width of the terminal.
-func getTerminalWidth() uint {
-	ws := &termsize{}
-	retCode, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
-		uintptr(syscall.Stdin),
-		uintptr(TERMINAL_INFO),
-		uintptr(unsafe.Pointer(ws)))
-	if int(retCode) == -1 {
-		panic(errno)
-	}
-	return uint(ws.Col)
-}
-
-// Displays error messages
-func errorChecker(err *error, message string) {
-	if *err != nil {
-		fmt.Print(message)
-		os.Exit(0)
-	}
-}
-
-// If there is no argument, set the directory path to the current working directory
-func getPath() string {
-	if flag.NArg() < 1 {
-		path, err := os.Getwd()
-		errorChecker(&err, "ls: Could not obtain the current working directory.\n")
-		return path
-	} else {
-		if strings.HasPrefix(flag.Arg(0), ".") {
-			return flag.Arg(0)
-		} else {
-			return flag.Arg(0) + "/"
-		}
-	}
-}
-
-// Checks if the file can be shown
-func fileIsNotHidden(file string) bool {
-	if strings.HasPrefix(file, ".") {
-		return false
-	} else {
-		return true
-	}
-}
-
-// Loop through each file in the directory and check whether or not it is hidden.
-// If the file is hidden, it shall not be displayed.
-func checkForHiddenFiles(directory *[]os.FileInfo) {
-	for _, file := range *directory {
-		if fileIsNotHidden(file.Name()) {
-			fileList = append(fileList, file)
-		}
-	}
-}
-
-// Scans the directory and returns a list of the contents. If the directory
-// does not exist, an error is printed and the program exits.
-func scanDirectory() {
-	if *dirOnly {
-		directory, err := os.Stat(getPath())
-		errorChecker(&err, "ls: "+getPath()+" - No such file or directory.\n")
-		fileList = append(fileList, directory)
-	} else {
-		directory, err := ioutil.ReadDir(getPath())
-		errorChecker(&err, "ls: "+getPath()+" - No such file or directory.\n")
-		if *showHidden {
-			fileList = directory
-		} else {
-			checkForHiddenFiles(&directory)
-		}
-	}
-}
-
-// Get a list of users from getent
-func bufferUsers() []string {
-	input, _ := exec.Command("getent", "passwd").Output()
-	return strings.Split(string(input[:]), "\n")
+func init() {
+	applet.Register("ls", Main)
 }
 
-// Get a list of groups from getent
-func bufferGroups() []string {
-	input, _ := exec.Command("getent", "group").Output()
-	return strings.Split(string(input[:]), "\n")
-}
-
-// Returns a colon separated string array for use in parsing /etc/group and /etc/user
-func parseLine(line string) []string {
-	return strings.Split(line, ":")
-}
-
-// Returns user id
-func getUID(file os.FileInfo) string {
-	return fmt.Sprintf("%d", file.Sys().(*syscall.Stat_t).Uid)
-}
-
-// Returns group id
-func getGID(file os.FileInfo) string {
-	return fmt.Sprintf("%d", file.Sys().(*syscall.Stat_t).Gid)
-}
-
-// Returns the username associated to a user ID
-func lookupUserID(uid string, userStringArray []string) string {
-	for _, line := range userStringArray {
-		values := parseLine(line)
-		if len(values) > 2 {
-			if values[2] == uid {
-				return values[0]
-			}
+// Returns the name of the user with the given ID, or the ID itself with -n or if the user has no
+// name.
+func lookupUserID(uid string) string {
+	if !*numericIDs {
+		if name, ok := users.UserName(uid); ok {
+			return name
 		}
 	}
 	return uid
 }
 
-// Returns the groupname associated to a group ID
-func lookupGroupID(gid string, groupStringArray []string) string {
-	for _, line := range groupStringArray {
-		values := parseLine(line)
-		if len(values) > 2 {
-			if values[2] == gid {
-				return values[0]
-			}
+// Returns the name of the group with the given ID, or the ID itself with -n or if the group has no
+// name.
+func lookupGroupID(gid string) string {
+	if !*numericIDs {
+		if name, ok := users.GroupName(gid); ok {
+			return name
 		}
 	}
 	return gid
@@ -264,133 +242,137 @@ func lookupGroupID(gid string, groupStringArray []string) string {
 
 // Obtains a list of user names
 func getUserList(done chan bool) {
-	userBuffer := bufferUsers()
 	for _, file := range fileList {
-		if *numericIDs {
-			fileUserList = append(fileUserList, getUID(file))
-		} else {
-			fileUserList = append(fileUserList, lookupUserID(getUID(file), userBuffer))
-		}
+		fileUserList = append(fileUserList, lookupUserID(getUID(file)))
 	}
 	done <- true
 }
 
 // Obtains a list of group names
 func getGroupList(done chan bool) {
-	groupBuffer := bufferGroups()
 	for _, file := range fileList {
-		if *numericIDs {
-			fileGroupList = append(fileGroupList, getGID(file))
+		fileGroupList = append(fileGroupList, lookupGroupID(getGID(file)))
+	}
+	done <- true
+}
+
+// Obtains a list of file sizes, with the major and minor numbers of devices instead.
+func getFileSize(done chan bool) {
+	for _, file := range fileList {
+		if file.Mode()&os.ModeDevice != 0 {
+			major, minor := getDevice(file)
+			fileSizeList = append(fileSizeList, fmt.Sprintf("%d", major))
+			fileMinorList = append(fileMinorList, fmt.Sprintf("%d", minor))
 		} else {
-			fileGroupList = append(fileGroupList, lookupGroupID(getGID(file), groupBuffer))
+			fileSizeList = append(fileSizeList,
+				humanReadable(uint64(file.Size()), 1, fileBlockSize, fileBlockOpts))
+			fileMinorList = append(fileMinorList, "")
 		}
 	}
 	done <- true
 }
 
-// Returns the file size in either human or non-human-readable format
-func getSizeString(size int64) string {
-	if *human {
-		if size > 1073741824 {
-			return fmt.Sprintf("%.1f%s", float32(size)/1073741824, "G")
-		} else if size > 1048576 {
-			return fmt.Sprintf("%.1f%s", float32(size)/1048576, "M")
-		} else if size > 1024 {
-			return fmt.Sprintf("%.1f%s", float32(size)/1024, "K")
-		}
+// Obtains a list of inode numbers.
+func getInodeList(done chan bool) {
+	for _, file := range fileList {
+		fileInodeList = append(fileInodeList, fmt.Sprintf("%d", getInode(file)))
 	}
-	return fmt.Sprintf("%d", size)
+	done <- true
 }
 
-// Obtains a list of file sizes.
-func getFileSize(done chan bool) {
+// Obtains a list of allocated sizes.
+func getBlocksList(done chan bool) {
 	for _, file := range fileList {
-		fileSizeList = append(fileSizeList, getSizeString(file.Size()))
+		fileBlocksList = append(fileBlocksList, getBlocksString(file))
 	}
 	done <- true
 }
 
-// Obtains a list of file character lengths.
-func getFileLengthList(done chan bool) {
+// Obtains a list of hard link counts.
+func getLinkCountList(done chan bool) {
 	for _, file := range fileList {
-		fileLengthList = append(fileLengthList, len(file.Name()))
+		fileLinkList = append(fileLinkList, fmt.Sprintf("%d", getLinkCount(file)))
 	}
 	done <- true
 }
 
-// Checks if the date of the file is from a prior year, and if so print the year, else print
-// only the hour and minute.
-func dateFormatCheck(fileModTime time.Time) string {
-	if fileModTime.Year() != time.Now().Year() {
-		return fileModTime.Format(DATE_YEAR_FORMAT)
-	} else {
-		return fileModTime.Format(DATE_FORMAT)
+// Obtains a list of file character lengths.
+func getFileLengthList(done chan bool) {
+	for index := range fileList {
+		fileLengthList = append(fileLengthList, prefixLength+nameLength(index))
 	}
+	done <- true
 }
 
 // Obtains a list of formatted file modification dates.
 func getModDateList(done chan bool) {
 	for _, file := range fileList {
-		fileModDateList = append(fileModDateList, dateFormatCheck(file.ModTime()))
+		fileModDateList = append(fileModDateList, formatTime(fileTime(file)))
 	}
 	done <- true
 }
 
 // Obtains the mode type of the file in string format.
 func getModeType(file os.FileInfo) string {
-	return file.Mode().String()
+	return modeString(file.Mode())
 }
 
 // Obtains a list of mode types in string format.
 func getModeTypeList(done chan bool) {
 	for _, file := range fileList {
-		fileModeList = append(fileModeList, file.Mode().String())
+		fileModeList = append(fileModeList, getModeType(file))
 	}
 	done <- true
 }
 
 // Determines the character length of the longest file name.
 func getMaxCharacterLength(done chan bool) {
-	for _, file := range fileList {
-		if len(file.Name()) > maxCharLength {
-			maxCharLength = len(file.Name())
-		}
+	for index := range fileList {
+		maxCharLength = max(maxCharLength, prefixLength+nameLength(index))
 	}
 	done <- true
 }
 
-// Determines the max character length of file size and user/group names/ids.
-func countMaxSizeLength(done chan bool) {
-	for _, size := range fileSizeList {
-		length := len(size)
-		if length > maxSizeLength {
-			maxSizeLength = length
-		}
+// Returns the length of the longest string in list.
+func maxLength(list []string) int {
+	longest := 0
+	for _, s := range list {
+		longest = max(longest, len(s))
 	}
-	done <- true
+	return longest
 }
 
-// If the length of the ID is greater than the max ID length, length becomes the new max ID length.
-func checkIDLength(length int) {
-	if length > maxIDLength {
-		maxIDLength = length
+// Determines the max character length of file sizes. The major and minor numbers of devices
+// are separated by a comma, and aligned on it.
+func countMaxSizeLength(done chan bool) {
+	maxMajorLength := 0
+	for index, size := range fileSizeList {
+		if fileMinorList[index] == "" {
+			maxSizeLength = max(maxSizeLength, len(size))
+		} else {
+			maxMajorLength = max(maxMajorLength, len(size))
+		}
+	}
+	maxMinorLength = maxLength(fileMinorList)
+	if maxMinorLength > 0 {
+		maxSizeLength = max(maxSizeLength, maxMajorLength+2+maxMinorLength)
 	}
+	done <- true
 }
 
-// Determines the max ID length.
+// Determines the max length of link counts and user and group names or IDs.
 func countMaxIDLength(done chan bool) {
-	for index, _ := range fileList {
-		checkIDLength(len(fileUserList[index]))
-		checkIDLength(len(fileGroupList[index]))
-	}
+	maxLinkLength = maxLength(fileLinkList)
+	maxUserLength = maxLength(fileUserList)
+	maxGroupLength = maxLength(fileGroupList)
 	done <- true
 }
 
 // Counts char length up to maximum terminal width
 func countTotalCharLength() {
-	for _, file := range fileList {
+	for index := range fileList {
 		if totalCharLength <= terminalWidth {
-			totalCharLength += len(file.Name()) + 2 // The additional 2 is for spacing.
+			totalCharLength += prefixLength + nameLength(index) + 2 // The additional 2 is for spacing.
 		} else {
 			break
 		}
@@ -413,9 +395,13 @@ func printOneLineCheck(done chan bool) {
 	done <- true
 }
 
-// Returns the maximum number of columns to print
+// Returns the maximum number of columns to print, which is at least one
 func getMaxColumns(done chan int) {
-	done <- terminalWidth / (maxCharLength + SPACING)
+	if columns := terminalWidth / (maxCharLength + SPACING); columns > 1 {
+		done <- columns
+	} else {
+		done <- 1
+	}
 }
 
 // Returns the number of files to print
@@ -472,6 +458,25 @@ func getTopToBottomOrder() {
 
 // Obtain lists of file information
 func getFileStats() {
+	// The names are quoted first, as whether any is quoted changes the others.
+	getFileNameList()
+
+	// The inode numbers and allocated sizes are printed before the names, so they are needed
+	// for the lengths.
+	if *printInode || *printBlocks {
+		inodeDone := make(chan bool)
+		blocksDone := make(chan bool)
+
+		go getInodeList(inodeDone)
+		go getBlocksList(blocksDone)
+
+		<-inodeDone
+		<-blocksDone
+		maxInodeLength = maxLength(fileInodeList)
+		maxBlocksLength = maxLength(fileBlocksList)
+		prefixLength = len(filePrefix(0))
+	}
+
 	// Channels for the goroutines to check when they finish.
 	lengthDone := make(chan bool)
 	oneLineCheck := make(chan bool)
@@ -485,6 +490,7 @@ func getFileStats() {
 	// If longMode is enabled
 	if *longMode {
 		modeDone := make(chan bool)
+		linkDone := make(chan bool)
 		modDateDone := make(chan bool)
 		sizeDone := make(chan bool)
 		userDone := make(chan bool)
@@ -493,6 +499,7 @@ func getFileStats() {
 		countDone := make(chan bool)
 
 		go getModeTypeList(modeDone)
+		go getLinkCountList(linkDone)
 		go getModDateList(modDateDone)
 		go getFileSize(sizeDone)
 		go getUserList(userDone)
@@ -500,6 +507,7 @@ func getFileStats() {
 
 		<-userDone
 		<-groupDone
+		<-linkDone
 		go countMaxIDLength(idDone)
 		<-sizeDone
 		go countMaxSizeLength(countDone)
@@ -507,8 +515,6 @@ func getFileStats() {
 		<-modDateDone
 		<-countDone
 		<-idDone
-	} else {
-
 	}
 
 	// Synchronize goroutines with main
@@ -539,20 +545,18 @@ func getFileStats() {
 	}
 }
 
-// Open a symlink
-func openSymlink(file string) os.FileInfo {
-	var fi os.FileInfo
-	if !strings.HasPrefix(file, "/") {
-		fi, _ = os.Stat(getPath() + file)
-	} else {
-		fi, _ = os.Stat(file)
+// Open a symlink, returning what it points to or nil if it is broken
+func openSymlink(path string) os.FileInfo {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil
 	}
 	return fi
 }
 
 // Resolve the symbolic links
-func readLink(file string) string {
-	sympath, err := os.Readlink(getPath() + file)
+func readLink(path string) string {
+	sympath, err := os.Readlink(path)
 	if err == nil {
 		return sympath
 	} else {
@@ -560,92 +564,94 @@ func readLink(file string) string {
 	}
 }
 
-/* If the file is a symbolic link, print it in cyan; a directory, blue; an executable file,
- * green; else print the file in white. */
-func colorizer(file os.FileInfo) string {
-	switch {
-	case file.Mode()&SYMLINK != 0:
-		return CYAN_SYMLINK + file.Name()
-	case file.IsDir():
-		return BLUE_DIR + file.Name()
-	case file.Mode()&EXECUTABLE != 0:
-		return GREEN_EXECUTABLE + file.Name()
-	default:
-		return RESET + file.Name()
+// Returns the inode number and allocated size of a file that -i and -s print before its name.
+func filePrefix(index int) string {
+	prefix := ""
+	if *printInode {
+		prefix += padLeft(fileInodeList[index], maxInodeLength) + " "
+	}
+	if *printBlocks {
+		prefix += padLeft(fileBlocksList[index], maxBlocksLength) + " "
 	}
+	return prefix
 }
 
-// Returns the printing layout for long mode.
-func getLongModeLayout() string {
-	ownershipLayout := fmt.Sprintf("%d", maxIDLength)
-	sizeLayout := fmt.Sprintf("%d", maxSizeLength)
-	return "%11s %-" + ownershipLayout + "s %-" + ownershipLayout + "s %" + sizeLayout + "s %12s %s\n"
+// Returns the name of a file with what -i and -s print before it, in its color if names are
+// colored, and its indicator after it.
+func colorizer(index int) string {
+	prefix := filePrefix(index) + namePad(index)
+	return normalColor() + prefix + colorizeAt(fileList[index], fileNameList[index], len(prefix)) +
+		typeIndicator(fileList[index])
 }
 
-// If the file is a symbolic link, resolve it and print the mode type of that location.
-func checkIfSymlink(file os.FileInfo) string {
-	var fileName string
-
-	if file.Mode()&SYMLINK != 0 {
-		symPath := readLink(file.Name())
-		fileName = colorizer(file) + RESET + " -> " + colorizer(openSymlink(symPath))
+// Returns the columns of long mode before the name of a file.
+func getLongModePrefix(index int) string {
+	file := fileList[index]
+	line := filePrefix(index) + fileModeList[index] + " " + padLeft(fileLinkList[index], maxLinkLength) + " "
+	if printOwner {
+		line += padOwner(fileUserList[index], getUID(file), maxUserLength)
+	}
+	if printGroup {
+		line += padOwner(fileGroupList[index], getGID(file), maxGroupLength)
+	}
+	if *printAuthor {
+		line += padOwner(fileUserList[index], getUID(file), maxUserLength)
+	}
+	if minor := fileMinorList[index]; minor != "" {
+		line += padLeft(fileSizeList[index], maxSizeLength-2-maxMinorLength) + ", " + padLeft(minor, maxMinorLength)
 	} else {
-		fileName = colorizer(file)
+		line += padLeft(fileSizeList[index], maxSizeLength)
 	}
+	return line + " " + fileModDateList[index] + " "
+}
 
+// Returns the name of a file starting at startCol on the line, followed by
+// what it points to if it is a symbolic link, with the indicator of that
+// if the link was followed.
+func checkIfSymlink(index int, startCol int) string {
+	file := fileList[index]
+	pad := namePad(index)
+	fileName := pad + colorizeAt(file, fileNameList[index], startCol+len(pad))
+	if file.Mode()&SYMLINK == 0 {
+		return fileName + typeIndicator(file)
+	}
+	symPath := readLink(filePath(file))
+	target := openSymlink(filePath(file))
+	targetName, _ := quoteName(symPath)
+	fileName += " -> " + colorizeTarget(symPath, targetName, target, startCol+len(pad)+len(fileNameList[index])+4)
+	if target != nil && indicatorStyle != indicatorNone && followLinks() {
+		fileName += modeIndicator(target.Mode())
+	}
 	return fileName
 }
 
 // Prints a single file in long mode format.
 func printLongModeFile(file os.FileInfo, index *int) {
-	printingLayout := getLongModeLayout()
-	fileName := checkIfSymlink(file)
-	fmt.Printf(printingLayout, fileModeList[*index], fileUserList[*index],
-		fileGroupList[*index], fileSizeList[*index], fileModDateList[*index], fileName+RESET)
+	fmt.Print(normalColor())
+	prefix := getLongModePrefix(*index)
+	fmt.Println(prefix + checkIfSymlink(*index, len(prefix)))
 }
 
 // Prints files in long mode
 func longModePrinter() {
-	// Print number of files in the directory
-	fmt.Println("total:", numOfFiles)
-	if *reversed {
-		for index := numOfFiles - 1; index >= 0; index-- {
-			file := fileList[index]
-			printLongModeFile(file, &index)
-		}
-	} else {
-		for index, file := range fileList {
-			printLongModeFile(file, &index)
-		}
+	for index, file := range fileList {
+		printLongModeFile(file, &index)
 	}
 }
 
 // Prints all files in one line
 func oneLinePrinter() {
-	if *reversed {
-		for index := numOfFiles - 1; index >= 0; index-- {
-			fmt.Print(colorizer(fileList[index]), "  ")
-		}
-	} else {
-		for _, file := range fileList {
-			fmt.Print(colorizer(file), "  ")
-		}
+	for index := range fileList {
+		fmt.Print(colorizer(index), "  ")
 	}
-	fmt.Println(RESET)
+	fmt.Println()
 }
 
 // Prints all files in one column
 func singleColumnPrinter() {
-	if *reversed {
-		for index := numOfFiles - 1; index >= 0; index-- {
-			fmt.Println(colorizer(fileList[index]))
-		}
-	} else {
-		for _, file := range fileList {
-			fmt.Println(colorizer(file))
-		}
+	for index := range fileList {
+		fmt.Println(colorizer(index))
 	}
-	fmt.Print(RESET)
 }
 
 // Prints a file on the screen and determines when it is time to print a newline.
@@ -659,35 +665,19 @@ func printTopToBottomFile(currentColumn *int, file string) {
 	*currentColumn++
 }
 
-// Reset the terminal at the end and add an extra newline if needed.
+// Add an extra newline at the end if needed.
 func resetTerminal(lastRowCount *int) {
-	if *lastRowCount == 0 {
-		fmt.Print(RESET)
-	} else {
-		fmt.Println(RESET)
+	if *lastRowCount != 0 {
+		fmt.Println()
 	}
 }
 
-// Obtains statistics on the files to be printed, checks if printing order should be reversed,
-// and finally prints files based on the printing order.
+// Prints files from top to bottom based on the printing order. The files are sorted already,
+// in reverse with -r.
 func printTopToBottom(colorizedList []string) {
 	var currentColumn int = 1
-	if *reversed {
-		// Print all but the last row in descending order
-		for index := ((numOfRows - 1) * maxColumns) - 1; index >= 0; index-- {
-			printTopToBottomFile(&currentColumn, colorizedList[printOrder[index]])
-		}
-		// Print the final row
-		index := len(printOrder) - 1
-		for count := 0; count < lastRowCount; count++ {
-			fmt.Print(colorizedList[printOrder[index]])
-			index--
-		}
-	} else {
-		// Print files from top to bottom in ascending order
-		for _, index := range printOrder {
-			printTopToBottomFile(&currentColumn, colorizedList[index])
-		}
+	for _, index := range printOrder {
+		printTopToBottomFile(&currentColumn, colorizedList[index])
 	}
 	resetTerminal(&lastRowCount)
 }
@@ -698,11 +688,12 @@ func spacer(name string, charLength int) string {
 	return string(name + strings.Repeat(" ", maxCharLength-charLength+SPACING))
 }
 
-// Obtains a list of colorized and spaced names for printTopToBottom.
+// Obtains a list of colorized and spaced names for printTopToBottom. The names are colorized in
+// the printing order, as the first color printed is preceded by a reset.
 func getColorizedList() []string {
-	colorizedList := make([]string, 0)
-	for index, file := range fileList { // Preprocesses the file list for printing by adding spaces.
-		colorizedList = append(colorizedList, spacer(colorizer(file), fileLengthList[index]))
+	colorizedList := make([]string, len(fileList))
+	for _, index := range printOrder { // Preprocesses the file list for printing by adding spaces.
+		colorizedList[index] = spacer(colorizer(index), fileLengthList[index])
 	}
 	return colorizedList
 }
@@ -721,10 +712,155 @@ func printSwitch() {
 	}
 }
 
-func main() {
+// Main runs ls with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report = diag.New("ls")
+	report.FailStatus, report.UsageStatus = 2, 2
+	opts = getopt.New("ls")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	showHidden = opts.Bool('a', "all")
+	dirOnly = opts.Bool('d', "directory")
+	longMode = opts.Bool('l', "")
+	printInode = opts.Bool('i', "inode")
+	printBlocks = opts.Bool('s', "size")
+	printAuthor = opts.Bool(0, "author")
+	opts.Func('g', "", getopt.NoArgument, func(string) error {
+		*longMode, printOwner = true, false
+		return nil
+	})
+	opts.Func('o', "", getopt.NoArgument, func(string) error {
+		*longMode, printGroup = true, false
+		return nil
+	})
+	opts.Func('h', "human-readable", getopt.NoArgument, func(string) error {
+		spec := "human-readable"
+		blockSizeSpec = &spec
+		return nil
+	})
+	opts.Func(0, "si", getopt.NoArgument, func(string) error {
+		spec := "si"
+		blockSizeSpec = &spec
+		return nil
+	})
+	opts.Func(0, "block-size", getopt.RequiredArgument, func(value string) error {
+		blockSizeSpec = &value
+		return nil
+	})
+	opts.Func(0, "full-time", getopt.NoArgument, func(string) error {
+		*longMode, timeStyle, timeStyleGiven = true, "full-iso", true
+		return nil
+	})
+	opts.Func(0, "time-style", getopt.RequiredArgument, func(value string) error {
+		timeStyle, timeStyleGiven = value, true
+		return nil
+	})
+	opts.Func(0, "quoting-style", getopt.RequiredArgument, func(value string) error {
+		key, err := argmatch("--quoting-style", value, quotingWords)
+		if err == nil {
+			style := quotingStyleOf(key)
+			quotingStyle = &style
+		}
+		return err
+	})
+	opts.Func('b', "escape", getopt.NoArgument, setQuoting(quotearg.Escape))
+	opts.Func('Q', "quote-name", getopt.NoArgument, setQuoting(quotearg.C))
+	opts.Func('q', "hide-control-chars", getopt.NoArgument, setHideControl(true))
+	opts.Func(0, "show-control-chars", getopt.NoArgument, setHideControl(false))
+	opts.Func(0, "hide", getopt.RequiredArgument, func(value string) error {
+		hidePatterns = append(hidePatterns, value)
+		return nil
+	})
+	opts.Func('I', "ignore", getopt.RequiredArgument, func(value string) error {
+		ignorePatterns = append(ignorePatterns, value)
+		return nil
+	})
+	opts.Func('F', "", getopt.NoArgument, setIndicator(indicatorClassify))
+	opts.Func(0, "classify", getopt.OptionalArgument, func(value string) error {
+		if value == "" {
+			indicatorStyle = indicatorClassify
+			return nil
+		}
+		// As in GNU, never and auto off a terminal leave the indicators as they were.
+		key, err := argmatch("--classify", value, colorWords)
+		if key == "always" || key == "auto" && isTerminal() {
+			indicatorStyle = indicatorClassify
+		}
+		return err
+	})
+	opts.Func('p', "", getopt.NoArgument, setIndicator(indicatorSlash))
+	opts.Func(0, "file-type", getopt.NoArgument, setIndicator(indicatorFileType))
+	numericIDs = opts.Bool('n', "numeric-uid-gid")
+	reversed = opts.Bool('r', "reverse")
+	recursive = opts.Bool('R', "recursive")
+	singleColumn = opts.Bool('1', "")
+	dirsFirst = opts.Bool(0, "group-directories-first")
+	opts.Func('t', "", getopt.NoArgument, setSort("time"))
+	opts.Func('S', "", getopt.NoArgument, setSort("size"))
+	opts.Func('X', "", getopt.NoArgument, setSort("extension"))
+	opts.Func('v', "", getopt.NoArgument, setSort("version"))
+	opts.Func('U', "", getopt.NoArgument, setSort("none"))
+	opts.Func(0, "sort", getopt.RequiredArgument, func(value string) error {
+		key, err := argmatch("--sort", value, sortWords)
+		if err == nil {
+			sortType, sortSpecified = key, true
+		}
+		return err
+	})
+	opts.Func(0, "color", getopt.OptionalArgument, func(value string) error {
+		if value == "" {
+			colorWhen = "always"
+			return nil
+		}
+		key, err := argmatch("--color", value, colorWords)
+		if err == nil {
+			colorWhen = key
+		}
+		return err
+	})
+	opts.Func('c', "", getopt.NoArgument, setTime("ctime"))
+	opts.Func('u', "", getopt.NoArgument, setTime("atime"))
+	opts.Func(0, "time", getopt.RequiredArgument, func(value string) error {
+		key, err := argmatch("--time", value, timeWords)
+		if err == nil {
+			timeType = key
+		}
+		return err
+	})
+	if err := opts.Parse(args); err != nil {
+		if badArgument { // GNU ls exits with 1, not 2, for a bad argument of an option.
+			report.UsageStatus = 1
+		}
+		return report.Usage(err)
+	}
+	if *help {
+		fmt.Print(help_text)
+		return 0
+	}
+	if *version {
+		fmt.Print(version_text)
+		return 0
+	}
+	setupQuoting()
+	if !setupBlockSizes() || *longMode && !setupTimeStyle() {
+		return report.Status()
+	}
+	// Without -l, -c and -u also sort by their time unless another sort was asked for.
+	if timeType != "mtime" && !sortSpecified && !*longMode {
+		sortType = "time"
+	}
+
 	runtime.GOMAXPROCS(runtime.NumCPU() + 1)
-	processFlags()  // Process flags and arguments
-	scanDirectory() // Load the directory list
-	getFileStats()  // Obtain lists of file information
-	printSwitch()   // Now that statistics have been gathered, it's time to process and print them.
+	terminalWidth = int(getTerminalWidth()) // Grabs information on the current terminal width.
+	if terminalWidth == 0 {                 // Not a terminal, so print one file per line.
+		*singleColumn = true
+	}
+	setupColors()
+	operands := opts.Args()
+	if len(operands) == 0 {
+		operands = []string{"."}
+	}
+	listOperands(operands) // Statistics are gathered, then printed, for each listing in turn.
+	fmt.Print(restoreColor())
+	return report.Status()
 }
@@ -0,0 +1,92 @@
+//
+// ls_linux.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+// Written By: Michael Murphy, Abram C. Isola
+//
+package ls
+
+import "fmt"
+import "os"
+import "syscall"
+import "time"
+import "unsafe"
+
+const (
+	TERMINAL_INFO  = 0x5413 // Used in the getTerminalWidth function
+	TERMINAL_ATTRS = 0x5401 // Used in the isTerminal function
+)
+
+// Stores information regarding the terminal size.
+type termsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+// Obtains the current width of the terminal, or 0 if standard output is not
+// a terminal.
+func getTerminalWidth() uint {
+	ws := &termsize{}
+	retCode, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		uintptr(syscall.Stdout),
+		uintptr(TERMINAL_INFO),
+		uintptr(unsafe.Pointer(ws)))
+	if int(retCode) == -1 || errno != 0 {
+		return 0
+	}
+	return uint(ws.Col)
+}
+
+// Reports whether standard output is a terminal, for --color=auto.
+func isTerminal() bool {
+	var attrs syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
+		uintptr(syscall.Stdout),
+		uintptr(TERMINAL_ATTRS),
+		uintptr(unsafe.Pointer(&attrs)))
+	return errno == 0
+}
+
+// Returns user id
+func getUID(file os.FileInfo) string {
+	return fmt.Sprintf("%d", file.Sys().(*syscall.Stat_t).Uid)
+}
+
+// Returns group id
+func getGID(file os.FileInfo) string {
+	return fmt.Sprintf("%d", file.Sys().(*syscall.Stat_t).Gid)
+}
+
+// Returns the last access time
+func getAccessTime(file os.FileInfo) time.Time {
+	st := file.Sys().(*syscall.Stat_t)
+	return time.Unix(int64(st.Atim.Sec), int64(st.Atim.Nsec))
+}
+
+// Returns the last status change time
+func getChangeTime(file os.FileInfo) time.Time {
+	st := file.Sys().(*syscall.Stat_t)
+	return time.Unix(int64(st.Ctim.Sec), int64(st.Ctim.Nsec))
+}
+
+// Returns the number of hard links
+func getLinkCount(file os.FileInfo) uint64 {
+	return uint64(file.Sys().(*syscall.Stat_t).Nlink)
+}
+
+// Returns the inode number
+func getInode(file os.FileInfo) uint64 {
+	return uint64(file.Sys().(*syscall.Stat_t).Ino)
+}
+
+// Returns the number of 512-byte blocks allocated to the file
+func getBlocks(file os.FileInfo) uint64 {
+	return uint64(file.Sys().(*syscall.Stat_t).Blocks)
+}
+
+// Returns the major and minor numbers of a device, as glibc splits them
+func getDevice(file os.FileInfo) (major, minor uint64) {
+	dev := uint64(file.Sys().(*syscall.Stat_t).Rdev)
+	major = dev>>8&0xfff | dev>>32&^0xfff
+	minor = dev&0xff | dev>>12&^0xff
+	return major, minor
+}
@@ -0,0 +1,227 @@
+//
+// ls_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux || windows
+// +build linux windows
+
+package ls
+
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "ls", []conformance.Case{
+		{Name: "default"},
+		{Name: "single-column", Args: []string{"-1"}},
+		{Name: "all", Args: []string{"-a"}},
+		{Name: "directory", Args: []string{"dir"}},
+		{Name: "missing", Args: []string{"missing"}},
+		{Name: "invalid-option", Args: []string{"-Y"}},
+		{Name: "unrecognized-option", Args: []string{"--bogus"}},
+		{Name: "sort-by-size", Args: []string{"-S", "a.txt", "b.txt"}},
+		{Name: "operands", Args: []string{"dir", "b.txt", "dir/sub", "a.txt"}},
+		{Name: "missing-operand", Args: []string{"a.txt", "missing", "dir/sub"}},
+		{Name: "recursive", Args: []string{"-R"}},
+		{Name: "recursive-all", Args: []string{"-Ra", "dir/"}},
+		{Name: "sort-invalid", Args: []string{"--sort=foo"}},
+		{Name: "sort-ambiguous", Args: []string{"--sort=", "."}},
+		{Name: "time-invalid", Args: []string{"--time=x"},
+			Skip: "ls has no birth time"},
+		{Name: "extension", Args: []string{"-X"}},
+		{Name: "group-directories-first", Args: []string{"--group-directories-first", "-r"}},
+		{Name: "color-never", Args: []string{"--color=never", "-1"}},
+		{Name: "color-always", Args: []string{"--color=always", "-1"},
+			Env: []string{"TERM=xterm", "COLORTERM="}},
+		{Name: "color-without-argument", Args: []string{"--color", "-1", "dir"},
+			Env: []string{"TERM=xterm", "COLORTERM="}},
+		{Name: "color-unknown-terminal", Args: []string{"--color=always", "-1"},
+			Env: []string{"TERM=dumb", "COLORTERM="}},
+		{Name: "color-colorterm", Args: []string{"--color=yes", "-1"},
+			Env: []string{"TERM=dumb", "COLORTERM=truecolor"}},
+		{Name: "color-auto", Args: []string{"--color=auto", "-1"},
+			Env: []string{"TERM=xterm", "COLORTERM="}},
+		{Name: "color-ls-colors", Args: []string{"--color=always", "-1", "-R"},
+			Env: []string{"LS_COLORS=di=35:*.TXT=4;32:*d.txt=\\e[7:ec=\\e[m"}},
+		{Name: "color-normal", Args: []string{"--color=always", "-1"},
+			Env: []string{"LS_COLORS=no=36:lc=<:rc=>"}},
+		{Name: "color-invalid-ls-colors", Args: []string{"--color=always", "-1"},
+			Env: []string{"LS_COLORS=di=34:xx=1"}},
+		{Name: "color-unparsable-ls-colors", Args: []string{"--color=always", "-1"},
+			Env: []string{"LS_COLORS=*.txt"}},
+		{Name: "color-invalid", Args: []string{"--color=rainbow"}},
+		{Name: "color-operands", Args: []string{"--color=always", "dir", "b.txt", "dir/sub", "a.txt"},
+			Env: []string{"TERM=xterm", "COLORTERM="}},
+		{Name: "color-missing-operand", Args: []string{"--color=always", "-r", "a.txt", "missing", "dir/sub"},
+			Env: []string{"TERM=xterm", "COLORTERM="}},
+		{Name: "color-recursive-all", Args: []string{"--color=always", "-Ra", "dir/"},
+			Env: []string{"TERM=xterm", "COLORTERM="}},
+		{Name: "color-directory", Args: []string{"--color=always", "-d", "dir", "a.txt"},
+			Env: []string{"LS_COLORS=di=1;34:*.txt=32"}},
+		{Name: "long-no-owner-group", Args: []string{"-go", "a.txt", "b.txt"}},
+		{Name: "full-time", Args: []string{"--full-time", "-go", "a.txt"}},
+		{Name: "time-style-long-iso", Args: []string{"-go", "--time-style=long-iso", "a.txt"}},
+		{Name: "time-style-iso", Args: []string{"-go", "--time-style=posix-iso", "a.txt"}},
+		{Name: "time-style-format", Args: []string{"-go", "--time-style=+%Y/%j %-d%%%_3e %a %#b", "a.txt"}},
+		{Name: "time-style-two-formats", Args: []string{"-go", "--time-style=+old %F\nrecent %R", "a.txt"}},
+		{Name: "time-style-environment", Args: []string{"-go", "a.txt"}, Env: []string{"TIME_STYLE=long-iso"}},
+		{Name: "time-style-without-long", Args: []string{"--time-style=bogus", "a.txt"}},
+		{Name: "time-style-invalid", Args: []string{"-l", "--time-style=bogus", "a.txt"}},
+		{Name: "time-style-ambiguous", Args: []string{"-l", "--time-style=l", "a.txt"}},
+		{Name: "time-style-too-many-formats", Args: []string{"-l", "--time-style=+1\n2\n3", "a.txt"}},
+		{Name: "size", Args: []string{"-sgo", ".hidden"}},
+		{Name: "block-size", Args: []string{"-sgo", "--block-size=K", ".hidden"}},
+		{Name: "block-size-environment", Args: []string{"-go", "a.txt"}, Env: []string{"BLOCK_SIZE=kB"}},
+		{Name: "block-size-human-readable", Args: []string{"-goh", "a.txt"}},
+		{Name: "block-size-invalid", Args: []string{"-l", "--block-size=x"}},
+		{Name: "block-size-invalid-suffix", Args: []string{"-l", "--block-size=1X"}},
+		{Name: "block-size-too-large", Args: []string{"-l", "--block-size=99999999999999999999999"}},
+		{Name: "si", Args: []string{"-go", "--si", "a.txt"}},
+		{Name: "size-prefix-ambiguous", Args: []string{"--s"}},
+		{Name: "hide", Args: []string{"--hide=*.txt"}},
+		{Name: "hide-separate-argument", Args: []string{"--hide", "a*"}},
+		{Name: "hide-several", Args: []string{"--hide=a*", "--hide", "[bd]*"}},
+		{Name: "hide-all", Args: []string{"-a", "--hide=*.txt"}},
+		{Name: "hide-operand", Args: []string{"--hide=*.txt", "a.txt", "dir"}},
+		{Name: "hide-missing-argument", Args: []string{"--hide"}},
+		{Name: "hide-prefix-ambiguous", Args: []string{"--hid", "a*"}},
+		{Name: "hide-control-chars-prefix", Args: []string{"--hide-c", "a.txt"}},
+		{Name: "ignore", Args: []string{"-a", "-I", "*.txt"}},
+		{Name: "ignore-dot", Args: []string{"-a", "--ignore=.*"}},
+		{Name: "ignore-leading-period", Args: []string{"-a", "--ignore=*"}},
+		{Name: "ignore-recursive", Args: []string{"-R", "-I?.txt", "--ignore", "sub"}},
+		{Name: "ignore-missing-argument", Args: []string{"-I"}},
+		{Name: "classify", Args: []string{"-F"}},
+		{Name: "classify-always", Args: []string{"--classify=always", "dir"}},
+		{Name: "classify-never", Args: []string{"-p", "--classify=never"}},
+		{Name: "classify-invalid", Args: []string{"--classify=x"}},
+		{Name: "slash", Args: []string{"-pa"}},
+		{Name: "file-type", Args: []string{"--file-type", "-R"}},
+		{Name: "quote-name", Args: []string{"-Q", "-R"}},
+		{Name: "escape", Args: []string{"-b", "a.txt"}},
+		{Name: "quoting-style-shell-always", Args: []string{"--quoting-style=shell-always", "-R", "dir"}},
+		{Name: "quoting-style-c-maybe", Args: []string{"--quoting-style=c-maybe", "a.txt", "dir"}},
+		{Name: "quoting-style-locale", Args: []string{"--quoting-style=locale", "-F", "dir"}},
+		{Name: "quoting-style-last", Args: []string{"-Q", "--quoting-style=lit", "a.txt"}},
+		{Name: "quoting-style-invalid", Args: []string{"--quoting-style=x"}},
+		{Name: "quoting-style-ambiguous", Args: []string{"--quoting-style=shell-e"}},
+		{Name: "quoting-style-environment", Args: []string{"a.txt"}, Env: []string{"QUOTING_STYLE=c"}},
+		{Name: "quoting-style-environment-invalid", Args: []string{"a.txt"}, Env: []string{"QUOTING_STYLE=bogus"}},
+	})
+}
+
+// TestSort lists files made with different sizes and times, which the
+// fixtures cannot have, in every order.
+func TestSort(t *testing.T) {
+	dir := conformance.Setup(t)
+	at := func(seconds int) time.Time { return conformance.FixtureTime.Add(time.Duration(seconds) * time.Second) }
+	for _, f := range []struct {
+		name         string
+		size         int
+		atime, mtime time.Time
+	}{
+		{"sort/a.c", 3, at(1), at(1)},
+		{"sort/b.tar.gz", 10, at(3), at(3)},
+		{"sort/file10", 1, at(2), at(2)},
+		{"sort/file9", 1, at(20), at(2)},
+		{"sort/Makefile", 5, at(30), at(0)},
+		{"mixed/a", 0, at(0), at(0)},
+		{"mixed/c", 0, at(0), at(0)},
+		{"mixed/b.d/", 0, at(0), at(0)},
+		{"mixed/sub/", 0, at(0), at(0)},
+	} {
+		name := filepath.Join(dir, f.name)
+		var err error
+		if strings.HasSuffix(f.name, "/") {
+			err = os.MkdirAll(name, 0755)
+		} else if err = os.MkdirAll(filepath.Dir(name), 0755); err == nil {
+			err = os.WriteFile(name, []byte(strings.Repeat("x", f.size)), 0644)
+		}
+		if err == nil {
+			err = os.Chtimes(name, f.atime, f.mtime)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, test := range []struct {
+		args   []string
+		stdout string
+	}{
+		{[]string{"sort"}, "Makefile a.c b.tar.gz file10 file9"},
+		{[]string{"-r", "sort"}, "file9 file10 b.tar.gz a.c Makefile"},
+		{[]string{"-t", "sort"}, "b.tar.gz file10 file9 a.c Makefile"},
+		{[]string{"-tr", "sort"}, "Makefile a.c file9 file10 b.tar.gz"},
+		{[]string{"-S", "sort"}, "b.tar.gz Makefile a.c file10 file9"},
+		{[]string{"-Sr", "sort"}, "file9 file10 a.c Makefile b.tar.gz"},
+		{[]string{"-X", "sort"}, "Makefile file10 file9 a.c b.tar.gz"},
+		{[]string{"-v", "sort"}, "Makefile a.c b.tar.gz file9 file10"},
+		{[]string{"--sort=width", "sort"}, "a.c file9 file10 Makefile b.tar.gz"},
+		{[]string{"--sort=v", "-S", "sort"}, "b.tar.gz Makefile a.c file10 file9"},
+		{[]string{"-u", "sort"}, "Makefile file9 b.tar.gz file10 a.c"},
+		{[]string{"--time=access", "--sort=time", "sort"}, "Makefile file9 b.tar.gz file10 a.c"},
+		{[]string{"-u", "--sort=size", "sort"}, "b.tar.gz Makefile a.c file10 file9"},
+		{[]string{"--group-directories-first", "mixed"}, "b.d sub a c"},
+		{[]string{"--group-directories-first", "-r", "mixed"}, "sub b.d c a"},
+	} {
+		got := conformance.Exec(t, dir, conformance.Case{Args: append([]string{"-1"}, test.args...)})
+		names := strings.Fields(string(got.Stdout))
+		if strings.Join(names, " ") != test.stdout || got.Status != 0 {
+			t.Errorf("ls -1 %q: got %d, %q; want 0, %q", test.args, got.Status, names, test.stdout)
+		}
+	}
+}
+
+func TestOperands(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		status int
+		stdout string
+		stderr string
+	}{
+		{[]string{"dir", "b.txt", "dir/sub", "a.txt"}, 0, "a.txt\nb.txt\n\ndir:\nc.txt\nsub\n\ndir/sub:\nd.txt\n", ""},
+		{[]string{"-r", "a.txt", "missing", "dir/sub"}, 2, "a.txt\n\ndir/sub:\nd.txt\n",
+			"ls: cannot access 'missing': No such file or directory\n"},
+		{[]string{"-R"}, 0, ".:\na.txt\nb.txt\ndir\n\n./dir:\nc.txt\nsub\n\n./dir/sub:\nd.txt\n", ""},
+		{[]string{"-Ra", "dir/"}, 0, "dir/:\n.\n..\nc.txt\nsub\n\ndir/sub:\n.\n..\nd.txt\n", ""},
+		{[]string{"-d", "dir", "a.txt"}, 0, "a.txt\ndir\n", ""},
+		{[]string{"dir/sub"}, 0, "d.txt\n", ""},
+	} {
+		got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: test.args})
+		if got.Status != test.status || string(got.Stdout) != test.stdout || string(got.Stderr) != test.stderr {
+			t.Errorf("ls %q: got %d, %q, %q; want %d, %q, %q", test.args,
+				got.Status, got.Stdout, got.Stderr, test.status, test.stdout, test.stderr)
+		}
+	}
+}
+
+// A subdirectory that cannot be read is a minor failure, and the listing
+// goes on.
+func TestUnreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("every directory is readable by root")
+	}
+	dir := conformance.Setup(t)
+	if err := os.Chmod(filepath.Join(dir, "dir", "sub"), 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(filepath.Join(dir, "dir", "sub"), 0755)
+	got := conformance.Exec(t, dir, conformance.Case{Args: []string{"-R", "dir"}})
+	want := "dir:\nc.txt\nsub\n"
+	if got.Status != 1 || string(got.Stdout) != want ||
+		string(got.Stderr) != "ls: cannot open directory 'dir/sub': Permission denied\n" {
+		t.Errorf("ls -R dir: got %d, %q, %q", got.Status, got.Stdout, got.Stderr)
+	}
+	got = conformance.Exec(t, dir, conformance.Case{Args: []string{"dir/sub"}})
+	if got.Status != 2 {
+		t.Errorf("ls dir/sub: got status %d, want 2", got.Status)
+	}
+}
@@ -0,0 +1,129 @@
+//
+// ls_windows.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+// Written By: Abram C. Isola
+//
+package ls
+
+import "os"
+import "syscall"
+import "time"
+import "unsafe"
+
+// Obtains the current width of the terminal, or 0 if there is no console.
+func getTerminalWidth() uint {
+	x, _, err := getConWinSize()
+	if err != nil {
+		return 0
+	}
+	return uint(x)
+}
+
+func getConWinSize() (x, y int, err error) {
+	hCon, err := syscall.Open("CONOUT$", syscall.O_RDONLY, 0)
+	if err != nil {
+		return
+	}
+	defer syscall.Close(hCon)
+
+	sb, err := getConsoleScreenBufferInfo(hCon)
+	if err != nil {
+		return
+	}
+	x = int(sb.size.x)
+	y = int(sb.size.y)
+	return
+}
+
+var (
+	modkernel32          = syscall.NewLazyDLL("kernel32.dll")
+	procGetConScrBufInfo = modkernel32.NewProc("GetConsoleScreenBufferInfo")
+	procGetConsoleMode   = modkernel32.NewProc("GetConsoleMode")
+)
+
+// Reports whether standard output is a console, for --color=auto.
+func isTerminal() bool {
+	var mode uint32
+	rc, _, _ := syscall.Syscall(procGetConsoleMode.Addr(), 2,
+		uintptr(syscall.Stdout), uintptr(unsafe.Pointer(&mode)), 0)
+	return rc != 0
+}
+
+type coord struct {
+	x int16
+	y int16
+}
+
+type smallRect struct {
+	left   int16
+	top    int16
+	right  int16
+	bottom int16
+}
+
+type consoleScreenBuffer struct {
+	size       coord
+	cursorPos  coord
+	attrs      int32
+	window     smallRect
+	maxWinSize coord
+}
+
+func getConsoleScreenBufferInfo(hCon syscall.Handle) (sb consoleScreenBuffer, err error) {
+	rc, _, ec := syscall.Syscall(procGetConScrBufInfo.Addr(), 2,
+		uintptr(hCon), uintptr(unsafe.Pointer(&sb)), 0)
+	if rc == 0 {
+		err = syscall.Errno(ec)
+	}
+	return
+}
+
+// Returns user id
+func getUID(file os.FileInfo) string {
+	// TODO: Figure all of this out...
+	// return fmt.Sprintf("%d", file.Sys().(*syscall.Stat_t).Uid)
+	return "0"
+}
+
+// Returns group id
+func getGID(file os.FileInfo) string {
+	// TODO: Figure all of this out...
+	// return fmt.Sprintf("%d", file.Sys().(*syscall.Stat_t).Gid)
+	return "0"
+}
+
+// Returns the last access time
+func getAccessTime(file os.FileInfo) time.Time {
+	if data, ok := file.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.LastAccessTime.Nanoseconds())
+	}
+	return file.ModTime()
+}
+
+// Returns the last status change time. Windows keeps none, so this is the
+// modification time.
+func getChangeTime(file os.FileInfo) time.Time {
+	return file.ModTime()
+}
+
+// Returns the number of hard links, which are not counted on Windows
+func getLinkCount(file os.FileInfo) uint64 {
+	return 1
+}
+
+// Returns the inode number, which Windows does not show through os.FileInfo
+func getInode(file os.FileInfo) uint64 {
+	return 0
+}
+
+// Returns the number of 512-byte blocks the file takes, which Windows does
+// not report, so its size rounded up
+func getBlocks(file os.FileInfo) uint64 {
+	return (uint64(file.Size()) + 511) / 512
+}
+
+// Returns the major and minor numbers of a device, which Windows does not have
+func getDevice(file os.FileInfo) (major, minor uint64) {
+	return 0, 0
+}
@@ -0,0 +1,232 @@
+//
+// quote.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux || windows
+// +build linux windows
+
+package ls
+
+import "os"
+import "strings"
+import "unicode/utf8"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/quotearg"
+import "github.com/aisola/go-coreutils/internal/width"
+
+// What is written after names, in GNU's order: each style adds to the one before.
+const (
+	indicatorNone     = iota
+	indicatorSlash    // -p: a slash after directories
+	indicatorFileType // --file-type: also @ after links, | after FIFOs and = after sockets
+	indicatorClassify // -F: also * after executable files
+)
+
+var (
+	quotingStyle   *quotearg.Style     // The style of --quoting-style, -b or -Q, if one was given.
+	hideControl    *bool               // Whether -q or --show-control-chars was given last, if one was.
+	indicatorStyle = indicatorNone     // What -F, -p and --file-type write after names.
+	someQuoted     = false             // Whether a name of the listing is quoted, so the others are padded.
+	fileNameList   = make([]string, 0) // A list of quoted file names.
+	fileQuotedList = make([]bool, 0)   // A list of whether the names are quoted.
+)
+
+// quotingWords are the arguments of --quoting-style.
+var quotingWords = func() [][]string {
+	words := make([][]string, len(quotearg.Names))
+	for i, name := range quotearg.Names {
+		words[i] = []string{name}
+	}
+	return words
+}()
+
+// Returns the quoting style that an argument of --quoting-style or the value of QUOTING_STYLE
+// names, which matchWord matched.
+func quotingStyleOf(key string) quotearg.Style {
+	for i, name := range quotearg.Names {
+		if name == key {
+			return quotearg.Style(i)
+		}
+	}
+	return quotearg.Literal
+}
+
+// Sets the quoting style up when no option chose it: from QUOTING_STYLE, else shell-escape for
+// a terminal and literal otherwise. Unprintable characters are shown as ? on a terminal unless
+// an option says otherwise.
+func setupQuoting() {
+	if quotingStyle == nil {
+		style := quotearg.Literal
+		if isTerminal() {
+			style = quotearg.ShellEscape
+		}
+		if value, ok := os.LookupEnv("QUOTING_STYLE"); ok {
+			if key, _ := matchWord(value, quotingWords); key != "" {
+				style = quotingStyleOf(key)
+			} else {
+				report.Warnf("ignoring invalid value of environment variable QUOTING_STYLE: %s", diag.Quote(value))
+			}
+		}
+		quotingStyle = &style
+	}
+	if hideControl == nil {
+		hide := isTerminal()
+		hideControl = &hide
+	}
+}
+
+// Returns a name in the quoting style, and whether quoting changed it. The characters of the
+// indicators are quoted too when they are written, and so are spaces in the escape style, which
+// has no quotes. With -q, the styles that leave unprintable characters as they are show them
+// as ? instead, which does not count as quoting.
+func quoteName(name string) (string, bool) {
+	extra := ""
+	if *quotingStyle == quotearg.Escape {
+		extra = " "
+	}
+	switch indicatorStyle {
+	case indicatorFileType:
+		extra += "*=>@|"
+	case indicatorClassify:
+		extra += "=>@|"
+	}
+	quoted := quotearg.Quote(name, *quotingStyle, extra)
+	return hideUnprintable(quoted), quoted != name
+}
+
+// Returns the name of a directory for the "name:" header, in the quoting style with colons
+// quoted too.
+func quoteDirName(name string) string {
+	return hideUnprintable(quotearg.Quote(name, *quotingStyle, ":"))
+}
+
+// Replaces each unprintable character, and each byte that is not UTF-8, with ? for -q in the
+// literal and shell styles.
+func hideUnprintable(s string) string {
+	switch *quotingStyle {
+	case quotearg.Literal, quotearg.Shell, quotearg.ShellAlways:
+	default:
+		return s
+	}
+	if !*hideControl {
+		return s
+	}
+	var out strings.Builder
+	for len(s) > 0 {
+		r, n := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && n == 1 || !quotearg.IsPrint(r) {
+			out.WriteByte('?')
+		} else {
+			out.WriteString(s[:n])
+		}
+		s = s[n:]
+	}
+	return out.String()
+}
+
+// Obtains the quoted names of the listing. In long format and in columns, the names without
+// quotes are padded with a space to line up with the quoted ones of the shell and c-maybe
+// styles, which only quote the names that need it.
+func getFileNameList() {
+	for _, file := range fileList {
+		name, quoted := quoteName(file.Name())
+		someQuoted = someQuoted || quoted
+		fileNameList = append(fileNameList, name)
+		fileQuotedList = append(fileQuotedList, quoted)
+	}
+	switch *quotingStyle {
+	case quotearg.Shell, quotearg.ShellEscape, quotearg.CMaybe:
+	default:
+		someQuoted = false
+	}
+	if !*longMode && *singleColumn {
+		someQuoted = false
+	}
+}
+
+// Returns the space that pads a name without quotes, if it needs one.
+func namePad(index int) string {
+	if someQuoted && !fileQuotedList[index] {
+		return " "
+	}
+	return ""
+}
+
+// Returns the width on the screen of a file name with its pad and indicator.
+func nameLength(index int) int {
+	return len(namePad(index)) + displayWidth(fileNameList[index]) + len(typeIndicator(fileList[index]))
+}
+
+// Returns the indicator of a file of the given mode, or "" if it has none.
+func modeIndicator(mode os.FileMode) string {
+	switch {
+	case mode.IsRegular():
+		if indicatorStyle == indicatorClassify && mode&EXECUTABLE != 0 {
+			return "*"
+		}
+	case mode.IsDir():
+		if indicatorStyle != indicatorNone {
+			return "/"
+		}
+	case indicatorStyle < indicatorFileType:
+	case mode&SYMLINK != 0:
+		return "@"
+	case mode&os.ModeNamedPipe != 0:
+		return "|"
+	case mode&os.ModeSocket != 0:
+		return "="
+	}
+	return ""
+}
+
+// Returns the indicator written after the name of a file. In long format, a link has none, as
+// the indicator of what it points to follows that.
+func typeIndicator(file os.FileInfo) string {
+	if *longMode && file.Mode()&SYMLINK != 0 {
+		return ""
+	}
+	return modeIndicator(file.Mode())
+}
+
+// Returns the number of columns s takes up on a terminal, where a byte that is not UTF-8 takes
+// up one.
+func displayWidth(s string) int {
+	columns := 0
+	for len(s) > 0 {
+		r, n := utf8.DecodeRuneInString(s)
+		s = s[n:]
+		if r == utf8.RuneError && n == 1 {
+			columns++
+		} else {
+			columns += width.Rune(r)
+		}
+	}
+	return columns
+}
+
+// setQuoting returns the option function that quotes names in style.
+func setQuoting(style quotearg.Style) func(string) error {
+	return func(string) error {
+		quotingStyle = &style
+		return nil
+	}
+}
+
+// setHideControl returns the option function that shows unprintable characters as ? if hide
+// is set, and as they are otherwise.
+func setHideControl(hide bool) func(string) error {
+	return func(string) error {
+		hideControl = &hide
+		return nil
+	}
+}
+
+// setIndicator returns the option function that writes the indicators of style after names.
+func setIndicator(style int) func(string) error {
+	return func(string) error {
+		indicatorStyle = style
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+//
+// quote_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux
+// +build linux
+
+package ls
+
+import "os"
+import "path/filepath"
+import "strings"
+import "syscall"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+// TestQuoting lists names that the fixtures cannot have, with the quoting and indicator
+// options. The expected outputs are GNU's in a UTF-8 locale, as ls treats names as UTF-8.
+func TestQuoting(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a b", "it's", "nl\nx", "\x01ctl", "é", "\xff", "日本", "st*r"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "run"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(dir, "run"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Mkfifo(filepath.Join(dir, "fifo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{"lnk": "run", "broken": "missing"} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		args   []string
+		stdout string
+	}{
+		{nil, "\x01ctl\na b\nbroken\nfifo\nit's\nlnk\nnl\nx\nrun\nst*r\né\n日本\n\xff\n"},
+		{[]string{"-q"}, "?ctl\na b\nbroken\nfifo\nit's\nlnk\nnl?x\nrun\nst*r\né\n日本\n?\n"},
+		{[]string{"-b"}, "\\001ctl\na\\ b\nbroken\nfifo\nit's\nlnk\nnl\\nx\nrun\nst*r\né\n日本\n\\377\n"},
+		{[]string{"-Q"}, `"\001ctl"` + "\n" + `"a b"` + "\n" + `"broken"` + "\n" + `"fifo"` + "\n" + `"it's"` + "\n" +
+			`"lnk"` + "\n" + `"nl\nx"` + "\n" + `"run"` + "\n" + `"st*r"` + "\n" + `"é"` + "\n" + `"日本"` + "\n" +
+			`"\377"` + "\n"},
+		{[]string{"--quoting-style=shell-escape"}, `''$'\001''ctl'` + "\n'a b'\nbroken\nfifo\n\"it's\"\nlnk\n" +
+			`'nl'$'\n''x'` + "\nrun\n'st*r'\né\n日本\n" + `''$'\377'` + "\n"},
+		{[]string{"-q", "--quoting-style=shell"}, "?ctl\n'a b'\nbroken\nfifo\n\"it's\"\nlnk\n'nl?x'\nrun\n'st*r'\né\n日本\n?\n"},
+		{[]string{"-F"}, "\x01ctl\na b\nbroken@\nfifo|\nit's\nlnk@\nnl\nx\nrun*\nst*r\né\n日本\n\xff\n"},
+		{[]string{"--file-type", "-b"}, "\\001ctl\na\\ b\nbroken@\nfifo|\nit's\nlnk@\nnl\\nx\nrun\nst\\*r\né\n日本\n\\377\n"},
+		{[]string{"-p", "--quoting-style=c-maybe"}, `"\001ctl"` + "\na b\nbroken\nfifo\nit's\nlnk\n" + `"nl\nx"` +
+			"\nrun\nst*r\né\n日本\n" + `"\377"` + "\n"},
+
+		// In long format, the names without quotes line up with the quoted ones, and a link
+		// shows the indicator of its target.
+		{[]string{"-goF", "--time-style=+T", "--quoting-style=shell-escape", "a b", "broken", "lnk", "run"},
+			"-rw-r--r-- 1 0 T 'a b'\nlrwxrwxrwx 1 7 T  broken -> missing\nlrwxrwxrwx 1 3 T  lnk -> run*\n" +
+				"-rwxr-xr-x 1 0 T  run*\n"},
+	} {
+		got := conformance.Exec(t, dir, conformance.Case{Args: append([]string{"-1"}, test.args...)})
+		if got.Status != 0 || string(got.Stdout) != test.stdout {
+			t.Errorf("ls -1 %q: got %d,\n%q\nwant\n%q", test.args, got.Status, got.Stdout, test.stdout)
+		}
+	}
+}
+
+// The operand of -F that is a link to a directory is listed as a link.
+func TestClassifyLinkOperand(t *testing.T) {
+	dir := conformance.Setup(t)
+	if err := os.Symlink("dir", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		args   []string
+		stdout string
+	}{
+		{[]string{"link"}, "c.txt\nsub\n"},
+		{[]string{"-F", "link"}, "link@\n"},
+		{[]string{"-p", "link"}, "c.txt\nsub/\n"},
+	} {
+		got := conformance.Exec(t, dir, conformance.Case{Args: test.args})
+		if got.Status != 0 || string(got.Stdout) != test.stdout {
+			t.Errorf("ls %q: got %d, %q; want 0, %q", test.args, got.Status, got.Stdout, test.stdout)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	for _, test := range []struct {
+		s     string
+		width int
+	}{
+		{"abc", 3},
+		{"'a b'", 5},
+		{"é", 1},
+		{"e\u0301", 1},
+		{"日本", 4},
+		{"\x01ctl", 3},
+		{"\xff", 1},
+		{strings.Repeat("\u200b", 2), 0},
+	} {
+		if got := displayWidth(test.s); got != test.width {
+			t.Errorf("displayWidth(%q) = %d, want %d", test.s, got, test.width)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+//
+// sort.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux || windows
+// +build linux windows
+
+package ls
+
+import "fmt"
+import "os"
+import "sort"
+import "strings"
+import "time"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+
+// The keys of --sort, each with its spellings, and of --time.
+var (
+	sortWords = [][]string{{"none"}, {"time"}, {"size"}, {"extension"}, {"version"}, {"width"}}
+	timeWords = [][]string{{"atime", "access", "use"}, {"ctime", "status"}}
+)
+
+// matchWord returns the first spelling of the group of words that value
+// names. Like GNU, it accepts any prefix of a word that names one group
+// only, and reports whether value is such a prefix of several.
+func matchWord(value string, words [][]string) (key string, ambiguous bool) {
+	for _, group := range words {
+		for _, word := range group {
+			switch {
+			case word == value:
+				return group[0], false
+			case strings.HasPrefix(word, value) && key == "":
+				key = group[0]
+			case strings.HasPrefix(word, value) && key != group[0]:
+				ambiguous = true
+			}
+		}
+	}
+	if ambiguous {
+		return "", true
+	}
+	return key, false
+}
+
+// argmatch returns the first spelling of the group of words that value
+// names, for the argument of option, as matchWord does, and sets
+// badArgument if there is no match.
+func argmatch(option, value string, words [][]string) (string, error) {
+	match, ambiguous := matchWord(value, words)
+	if match != "" {
+		return match, nil
+	}
+	badArgument = true
+	problem := "invalid"
+	if ambiguous {
+		problem = "ambiguous"
+	}
+	msg := fmt.Sprintf("%s argument %s for '%s'\nValid arguments are:", problem, diag.Quote(value), option)
+	for _, group := range words {
+		msg += "\n  - '" + strings.Join(group, "', '") + "'"
+	}
+	return "", fmt.Errorf("%s", msg)
+}
+
+// setSort returns the option function that makes the files sorted by key.
+func setSort(key string) func(string) error {
+	return func(string) error {
+		sortType, sortSpecified = key, true
+		return nil
+	}
+}
+
+// setTime returns the option function that makes the files shown, and
+// maybe sorted, by the time key.
+func setTime(key string) func(string) error {
+	return func(string) error {
+		timeType = key
+		return nil
+	}
+}
+
+// fileTime returns the time of the file that -c, -u and --time select.
+func fileTime(file os.FileInfo) time.Time {
+	switch timeType {
+	case "atime":
+		return getAccessTime(file)
+	case "ctime":
+		return getChangeTime(file)
+	}
+	return file.ModTime()
+}
+
+// extension returns the extension of a name for -X: what follows its last
+// dot, the dot included.
+func extension(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i:]
+	}
+	return ""
+}
+
+// isDirectory reports whether the file is a directory, or a link to one,
+// for --group-directories-first.
+func isDirectory(file os.FileInfo) bool {
+	if file.Mode()&SYMLINK != 0 {
+		file = openSymlink(filePath(file))
+	}
+	return file != nil && file.IsDir()
+}
+
+// compareFiles compares two files by the key of --sort, falling back on
+// their names, and returns a negative number if a comes first.
+func compareFiles(a, b os.FileInfo) int {
+	switch sortType {
+	case "time":
+		ta, tb := fileTime(a), fileTime(b)
+		if !ta.Equal(tb) {
+			if ta.After(tb) {
+				return -1
+			}
+			return 1
+		}
+	case "size":
+		if a.Size() != b.Size() {
+			if a.Size() > b.Size() {
+				return -1
+			}
+			return 1
+		}
+	case "extension":
+		if c := strings.Compare(extension(a.Name()), extension(b.Name())); c != 0 {
+			return c
+		}
+	case "version":
+		if c := versionCompare(a.Name(), b.Name()); c != 0 {
+			return c
+		}
+	case "width":
+		if c := len(a.Name()) - len(b.Name()); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a.Name(), b.Name())
+}
+
+// sortFiles puts files in the order of the options, before the lists of
+// file information are built from them. -r reverses the order, but
+// directories still come first with --group-directories-first. -U keeps
+// the order of the directory and both of these options are ignored.
+func sortFiles(files []os.FileInfo) {
+	if sortType == "none" {
+		return
+	}
+	dirs := make([]bool, len(files))
+	if *dirsFirst {
+		for i, file := range files {
+			dirs[i] = isDirectory(file)
+		}
+	}
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if dirs[a] != dirs[b] {
+			return dirs[a]
+		}
+		if *reversed {
+			a, b = b, a
+		}
+		return compareFiles(files[a], files[b]) < 0
+	})
+	sorted := make([]os.FileInfo, len(files))
+	for i, index := range order {
+		sorted[i] = files[index]
+	}
+	copy(files, sorted)
+}
+
+// versionOrder returns the weight of the byte of s at i for versionCompare:
+// letters sort before the other bytes, ~ before everything, even the end
+// of the string.
+func versionOrder(s string, i int) int {
+	if i == len(s) {
+		return -1
+	}
+	switch c := s[i]; {
+	case isDigit(c):
+		return 0
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return int(c)
+	case c == '~':
+		return -2
+	default:
+		return int(c) + 256
+	}
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// versionRevCompare compares a and b as Debian versions: runs of digits
+// compare as numbers, and the text between them byte by byte by
+// versionOrder.
+func versionRevCompare(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && !isDigit(a[i]) || j < len(b) && !isDigit(b[j]) {
+			ca, cb := versionOrder(a, i), versionOrder(b, j)
+			if ca != cb {
+				return ca - cb
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for i < len(a) && j < len(b) && isDigit(a[i]) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return firstDiff
+		}
+	}
+	return 0
+}
+
+// suffixStart returns where the suffix of a file name starts that version
+// sort sets aside, the longest match of (\.[A-Za-z~][A-Za-z0-9~]*)*$.
+func suffixStart(s string) int {
+	prefix := 0
+	for i := 0; i < len(s); {
+		i++
+		prefix = i
+		for i+1 < len(s) && s[i] == '.' && (isAlnum(s[i+1]) && !isDigit(s[i+1]) || s[i+1] == '~') {
+			for i += 2; i < len(s) && (isAlnum(s[i]) || s[i] == '~'); i++ {
+			}
+		}
+	}
+	return prefix
+}
+
+// versionCompare compares file names for -v the way GNU filevercmp does:
+// "." first, then "..", then the other names starting with a dot, then
+// the rest, by versionRevCompare of the names without their suffixes and,
+// if that is a tie, of the whole names.
+func versionCompare(a, b string) int {
+	switch {
+	case a == "" || b == "":
+		return strings.Compare(a, b)
+	case a[0] == '.' && b[0] != '.':
+		return -1
+	case a[0] != '.' && b[0] == '.':
+		return 1
+	case a[0] == '.':
+		for _, special := range []string{".", ".."} {
+			switch {
+			case a == special && b == special:
+				return 0
+			case a == special:
+				return -1
+			case b == special:
+				return 1
+			}
+		}
+	}
+	pa, pb := suffixStart(a), suffixStart(b)
+	if c := versionRevCompare(a[:pa], b[:pb]); c != 0 || pa == len(a) && pb == len(b) {
+		return c
+	}
+	return versionRevCompare(a, b)
+}
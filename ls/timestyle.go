@@ -0,0 +1,323 @@
+//
+// timestyle.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build linux || windows
+// +build linux windows
+
+package ls
+
+import "fmt"
+import "os"
+import "strconv"
+import "strings"
+import "time"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+
+// The time styles of --time-style, each with its formats for times older than six months
+// and for recent ones.
+var timeStyles = []struct {
+	name        string
+	old, recent string
+}{
+	{"full-iso", "%Y-%m-%d %H:%M:%S.%N %z", "%Y-%m-%d %H:%M:%S.%N %z"},
+	{"long-iso", "%Y-%m-%d %H:%M", "%Y-%m-%d %H:%M"},
+	{"iso", "%Y-%m-%d ", "%m-%d %H:%M"},
+	{"locale", "%b %e  %Y", "%b %e %H:%M"},
+}
+
+var (
+	timeStyle        = ""            // The argument of --time-style.
+	timeStyleGiven   = false         // Whether --time-style or --full-time was given.
+	oldTimeFormat    = "%b %e  %Y"   // The format of the times older than six months.
+	recentTimeFormat = "%b %e %H:%M" // The format of the other times.
+	currentTime      time.Time       // The time ls started, which recent times are before.
+)
+
+// Sets the formats of the times from --time-style, or else TIME_STYLE, which only long listings
+// look at. The posix- styles are the locale style in the C locale, and a +FORMAT may hold a
+// second format for recent times after a newline.
+func setupTimeStyle() bool {
+	currentTime = time.Now()
+	style := timeStyle
+	if !timeStyleGiven {
+		var ok bool
+		if style, ok = os.LookupEnv("TIME_STYLE"); !ok {
+			return true
+		}
+	}
+	if strings.HasPrefix(style, "posix-") {
+		return true
+	}
+	if strings.HasPrefix(style, "+") {
+		formats := strings.Split(style[1:], "\n")
+		switch len(formats) {
+		case 1:
+			oldTimeFormat, recentTimeFormat = formats[0], formats[0]
+		case 2:
+			oldTimeFormat, recentTimeFormat = formats[0], formats[1]
+		default:
+			report.Errorf("invalid time style format %s", quoteFormat(style[1:]))
+			return false
+		}
+		return true
+	}
+
+	match := -1
+	ambiguous := false
+	for i, s := range timeStyles {
+		if s.name == style {
+			match, ambiguous = i, false
+			break
+		}
+		if strings.HasPrefix(s.name, style) {
+			ambiguous = match >= 0
+			match = i
+		}
+	}
+	if match < 0 || ambiguous {
+		problem := "invalid"
+		if ambiguous {
+			problem = "ambiguous"
+		}
+		msg := fmt.Sprintf("%s argument %s for 'time style'\nValid arguments are:", problem, diag.Quote(style))
+		for _, s := range timeStyles {
+			msg += "\n  - [posix-]" + s.name
+		}
+		report.Usagef("%s\n  - +FORMAT (e.g., +%%H:%%M) for a 'date'-style format", msg)
+		return false
+	}
+	oldTimeFormat, recentTimeFormat = timeStyles[match].old, timeStyles[match].recent
+	return true
+}
+
+// Quotes a format the way GNU's quote does, with C escapes, as it may hold newlines.
+func quoteFormat(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`).Replace(s) + "'"
+}
+
+// Returns a time in the format for recent times if it is within the six months before ls
+// started, and in the format for old times otherwise. A time in the future may be a file
+// changed since then, so the clock is read again.
+func formatTime(t time.Time) string {
+	if t.After(currentTime) {
+		currentTime = time.Now()
+	}
+	sixMonthsAgo := currentTime.Add(-31556952 / 2 * time.Second) // Half a Gregorian year.
+	if t.After(sixMonthsAgo) && t.Before(currentTime) {
+		return strftime(recentTimeFormat, t)
+	}
+	return strftime(oldTimeFormat, t)
+}
+
+// strftime formats t like the C function of that name in the C locale, with the GNU additions
+// that date documents: the flags '-' (no padding), '_' (spaces), '0' (zeros), '^' (upper case)
+// and '#' (opposite case) and a field width after the '%', %N for the nanoseconds, %q for the
+// quarter, and %:z, %::z and %:::z for the time zone with colons. A conversion it does not know
+// is copied as it is.
+func strftime(format string, t time.Time) string {
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+		start := i
+		pad, upper, swap := byte(0), false, false
+		for i+1 < len(format) && strings.IndexByte("-_0^#", format[i+1]) >= 0 {
+			i++
+			switch format[i] {
+			case '^':
+				upper = true
+			case '#':
+				swap = true
+			default:
+				pad = format[i]
+			}
+		}
+		width := 0
+		for i+1 < len(format) && '0' <= format[i+1] && format[i+1] <= '9' {
+			i++
+			width = width*10 + int(format[i]-'0')
+		}
+		colons := 0
+		for i+1 < len(format) && format[i+1] == ':' {
+			i++
+			colons++
+		}
+		if i+1 < len(format) && (format[i+1] == 'E' || format[i+1] == 'O') && colons == 0 {
+			i++ // The alternative forms of the C locale are the usual ones.
+		}
+		if i+1 >= len(format) {
+			out.WriteString(format[start:])
+			break
+		}
+		i++
+		c := format[i]
+		if colons > 0 && (c != 'z' || colons > 3) {
+			out.WriteString(format[start : i+1])
+			continue
+		}
+		if c == 'N' {
+			nanos := fmt.Sprintf("%09d", t.Nanosecond())
+			if width > 0 && width < 9 {
+				nanos = nanos[:width]
+			} else if width > 9 {
+				nanos += strings.Repeat("0", width-9)
+			}
+			out.WriteString(nanos)
+			continue
+		}
+
+		text, number, digits, numberPad := conversion(c, t, colons)
+		switch {
+		case digits > 0:
+			if pad == 0 {
+				pad = numberPad
+			}
+			if width == 0 {
+				width = digits
+			}
+			text = strconv.Itoa(number)
+		case text == "":
+			if c != '%' {
+				out.WriteString(format[start : i+1])
+				continue
+			}
+			text = "%"
+		default:
+			if pad == 0 {
+				pad = ' '
+			}
+			switch {
+			case swap && (c == 'p' || c == 'Z'):
+				text = strings.ToLower(text)
+			case upper || swap:
+				text = strings.ToUpper(text)
+			}
+		}
+		if pad != '-' && len(text) < width {
+			if pad == '_' {
+				pad = ' '
+			}
+			text = strings.Repeat(string(pad), width-len(text)) + text
+		}
+		out.WriteString(text)
+	}
+	return out.String()
+}
+
+// Returns a conversion of strftime: text, or a number with its usual number of digits and
+// padding. Neither is returned for an unknown conversion.
+func conversion(c byte, t time.Time, colons int) (text string, number, digits int, pad byte) {
+	year, week := t.ISOWeek()
+	hour12 := (t.Hour()+11)%12 + 1
+	switch c {
+	case 'a':
+		return t.Weekday().String()[:3], 0, 0, 0
+	case 'A':
+		return t.Weekday().String(), 0, 0, 0
+	case 'b', 'h':
+		return t.Month().String()[:3], 0, 0, 0
+	case 'B':
+		return t.Month().String(), 0, 0, 0
+	case 'c':
+		return strftime("%a %b %e %H:%M:%S %Y", t), 0, 0, 0
+	case 'C':
+		return "", t.Year() / 100, 2, '0'
+	case 'd':
+		return "", t.Day(), 2, '0'
+	case 'D', 'x':
+		return strftime("%m/%d/%y", t), 0, 0, 0
+	case 'e':
+		return "", t.Day(), 2, ' '
+	case 'F':
+		return strftime("%Y-%m-%d", t), 0, 0, 0
+	case 'g':
+		return "", year % 100, 2, '0'
+	case 'G':
+		return "", year, 1, '0'
+	case 'H':
+		return "", t.Hour(), 2, '0'
+	case 'I':
+		return "", hour12, 2, '0'
+	case 'j':
+		return "", t.YearDay(), 3, '0'
+	case 'k':
+		return "", t.Hour(), 2, ' '
+	case 'l':
+		return "", hour12, 2, ' '
+	case 'm':
+		return "", int(t.Month()), 2, '0'
+	case 'M':
+		return "", t.Minute(), 2, '0'
+	case 'n':
+		return "\n", 0, 0, 0
+	case 'p':
+		if t.Hour() < 12 {
+			return "AM", 0, 0, 0
+		}
+		return "PM", 0, 0, 0
+	case 'P':
+		if t.Hour() < 12 {
+			return "am", 0, 0, 0
+		}
+		return "pm", 0, 0, 0
+	case 'q':
+		return "", (int(t.Month())-1)/3 + 1, 1, '0'
+	case 'r':
+		return strftime("%I:%M:%S %p", t), 0, 0, 0
+	case 'R':
+		return strftime("%H:%M", t), 0, 0, 0
+	case 's':
+		return "", int(t.Unix()), 1, '0'
+	case 'S':
+		return "", t.Second(), 2, '0'
+	case 't':
+		return "\t", 0, 0, 0
+	case 'T', 'X':
+		return strftime("%H:%M:%S", t), 0, 0, 0
+	case 'u':
+		return "", (int(t.Weekday())+6)%7 + 1, 1, '0'
+	case 'U':
+		return "", (t.YearDay() + 6 - int(t.Weekday())) / 7, 2, '0'
+	case 'V':
+		return "", week, 2, '0'
+	case 'w':
+		return "", int(t.Weekday()), 1, '0'
+	case 'W':
+		return "", (t.YearDay() + 6 - (int(t.Weekday())+6)%7) / 7, 2, '0'
+	case 'y':
+		return "", t.Year() % 100, 2, '0'
+	case 'Y':
+		return "", t.Year(), 1, '0'
+	case 'z':
+		return zone(t, colons), 0, 0, 0
+	case 'Z':
+		name, _ := t.Zone()
+		return name, 0, 0, 0
+	}
+	return "", 0, 0, 0
+}
+
+// Returns the offset of the time zone of t as +hhmm, or with colons +hh:mm, +hh:mm:ss, or only
+// as precise as needed for three.
+func zone(t time.Time, colons int) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	h, m, s := offset/3600, offset/60%60, offset%60
+	switch {
+	case colons == 0:
+		return fmt.Sprintf("%c%02d%02d", sign, h, m)
+	case colons == 1 || colons == 3 && s == 0 && m != 0:
+		return fmt.Sprintf("%c%02d:%02d", sign, h, m)
+	case colons == 3 && s == 0:
+		return fmt.Sprintf("%c%02d", sign, h)
+	}
+	return fmt.Sprintf("%c%02d:%02d:%02d", sign, h, m, s)
+}
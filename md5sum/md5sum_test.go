@@ -0,0 +1,36 @@
+//
+// md5sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package md5sum
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "md5sum", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-"}, Stdin: "hello\n"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "check", Args: []string{"-c", "good.md5"}},
+		{Name: "check-stdin", Args: []string{"--check"}, Stdin: "b1946ac92492d2347c6235b4d2611184  a.txt\n"},
+		{Name: "check-mismatch", Args: []string{"-c", "bad.md5"}},
+		{Name: "check-missing-file", Args: []string{"-c", "missing.md5"}},
+		{Name: "check-missing-list", Args: []string{"-c", "missing"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+		{Name: "binary", Args: []string{"-b", "a.txt"}},
+		{Name: "tag", Args: []string{"--tag", "a.txt", "-"}, Stdin: "hello\n"},
+		{Name: "tag-text", Args: []string{"--tag", "-t", "a.txt"}},
+		{Name: "text-tag", Args: []string{"-t", "--tag", "a.txt"}},
+		{Name: "zero", Args: []string{"-z", "a.txt", "b.txt"}},
+		{Name: "check-tag", Args: []string{"-c", "--tag", "good.md5"}},
+		{Name: "check-binary", Args: []string{"-c", "-b", "good.md5"}},
+		{Name: "check-zero", Args: []string{"-c", "-z", "good.md5"}},
+		{Name: "check-tagged", Args: []string{"-c", "tagged.md5"}},
+	})
+}
@@ -4,12 +4,15 @@
 //
 // Written By: Corey Prak
 //
-package main
+package mkdir
 
 import (
-	"flag"
 	"fmt"
 	"os"
+
+	"github.com/aisola/go-coreutils/internal/applet"
+	"github.com/aisola/go-coreutils/internal/diag"
+	"github.com/aisola/go-coreutils/internal/getopt"
 )
 
 const (
@@ -18,9 +21,9 @@ const (
 
         --help        display this help and exit
         --version     output version information and exit
-        --parents     create parent directory/directories as 
+    -p, --parents     create parent directory/directories as 
                       needed, do nothing if already existing
-        --verbose     print a message for each created directory
+    -v, --verbose     print a message for each created directory
   `
 
 	version_text = `
@@ -31,59 +34,58 @@ const (
     LICENSE. This is free software, and you are welcome to redistribute 
     it under certain conditions in LICENSE.
   `
-
-	parents_text = `
-    Create parent directory/directories as needed for a path. If any
-    already exist, do nothing. 
-  `
-
-	usage_text = `usage: mkdir [-parents, -verbose] directory ...`
-
-	verbose_text = `Print a message for each created directory.`
 )
 
-var (
-	help    = flag.Bool("help", false, help_text)
-	version = flag.Bool("version", false, version_text)
-	parents = flag.Bool("parents", false, parents_text)
-	verbose = flag.Bool("verbose", false, verbose_text)
-)
-
-func main() {
-	flag.Parse()
+func init() {
+	applet.Register("mkdir", Main)
+}
 
-	if flag.NArg() == 0 {
-		fmt.Println(usage_text)
-		os.Exit(0)
+// Main runs mkdir with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("mkdir")
+	opts := getopt.New("mkdir")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	parents := opts.Bool('p', "parents")
+	verbose := opts.Bool('v', "verbose")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
 	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
 		fmt.Println(version_text)
-		os.Exit(0)
+		return 0
+	}
+
+	if opts.NArg() == 0 {
+		return report.Usagef("missing operand")
 	}
 
-	for i := 0; i < flag.NArg(); i++ {
+	for i := 0; i < opts.NArg(); i++ {
 		if *parents {
-			mkdirAllError := os.MkdirAll(flag.Arg(i), os.ModePerm)
+			mkdirAllError := os.MkdirAll(opts.Arg(i), os.ModePerm)
 
 			if mkdirAllError != nil {
-				fmt.Println(mkdirAllError)
+				report.Errorf("cannot create directory %s: %s",
+					diag.Quote(opts.Arg(i)), diag.Strerror(mkdirAllError))
 			} else if *verbose {
-				fmt.Printf("%s\n", flag.Arg(i))
+				fmt.Printf("%s\n", opts.Arg(i))
 			}
 		} else {
-			mkdirError := os.Mkdir(flag.Arg(i), os.ModePerm)
+			mkdirError := os.Mkdir(opts.Arg(i), os.ModePerm)
 
 			if mkdirError != nil {
-				fmt.Println(mkdirError)
+				report.Errorf("cannot create directory %s: %s",
+					diag.Quote(opts.Arg(i)), diag.Strerror(mkdirError))
 			} else if *verbose {
-				fmt.Printf("mkdir: created directory '%s'\n", flag.Arg(i))
+				fmt.Printf("mkdir: created directory '%s'\n", opts.Arg(i))
 			}
 		}
 	}
+	return report.Status()
 }
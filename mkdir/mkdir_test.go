@@ -0,0 +1,26 @@
+//
+// mkdir_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package mkdir
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "mkdir", []conformance.Case{
+		{Name: "new", Args: []string{"new"}},
+		{Name: "verbose", Args: []string{"-v", "new"}},
+		{Name: "parents", Args: []string{"-p", "x/y/z"}},
+		{Name: "parents-verbose", Args: []string{"-pv", "x/y"},
+			Skip: "mkdir -pv prints the path rather than each directory it creates"},
+		{Name: "parents-existing", Args: []string{"-p", "dir"}},
+		{Name: "existing", Args: []string{"dir", "new"}},
+		{Name: "missing-parent", Args: []string{"x/y"}},
+		{Name: "missing-operand"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -4,14 +4,19 @@
 //
 // Written By: Abram C. Isola, Michael Murphy
 //
-package main
+package mv
 
 import "bufio"
-import "flag"
+import "errors"
 import "fmt"
 import "io"
 import "os"
 import "path/filepath"
+import "syscall"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
 
 const (
 	help_text string = `
@@ -37,10 +42,14 @@ const (
 )
 
 var (
-	forceEnabled     = flag.Bool("f", false, "remove existing destination files and never prompt the user")
-	forceEnabledLong = flag.Bool("force", false, "remove existing destination files and never prompt the user")
+	forceEnabled *bool
+	report       *diag.Reporter
 )
 
+func init() {
+	applet.Register("mv", Main)
+}
+
 // The input function prints a statement to the user and accepts an input, then returns the input.
 
 func input(prompt, location string) string {
@@ -65,30 +74,28 @@ func fileExists(filep string) os.FileInfo {
 /* The argumentCheck function will check the number of arguments given to the program and process them
  * accordingly. */
 
-func argumentCheck(files []string) {
+func argumentCheck(files []string) int {
 	switch len(files) {
 	case 0: // If there is no argument
-		fmt.Println("mv: missing file operand\nTry 'mv -help' for more information")
-		os.Exit(0)
+		return report.Usagef("missing file operand")
 	case 1: // If there is one argument
-		fmt.Printf("mv: missing destination file operand after '%s'\nTry 'mv -help' for more information.\n", files[0])
-		os.Exit(0)
+		return report.Usagef("missing destination file operand after %s", diag.Quote(files[0]))
 	case 2: // If there are two arguments
 		mover(files[0], files[1])
 	default: // If there are more than two arguments
 		to_file, files := files[len(files)-1], files[:len(files)-1]
 
-		if fp := fileExists(to_file); fp == nil || !fp.IsDir() {
-			fmt.Println("mv: when moving multiple files, last argument must be a directory")
-			os.Exit(0)
+		if fp := fileExists(to_file); fp == nil {
+			report.Errorf("target %s: %s", diag.Quote(to_file), diag.Strerror(syscall.ENOENT))
+		} else if !fp.IsDir() {
+			report.Errorf("target %s: %s", diag.Quote(to_file), diag.Strerror(syscall.ENOTDIR))
 		} else {
-			fmt.Println(files)
 			for i := 0; i < len(files); i++ {
 				mover(files[i], to_file)
 			}
-			os.Exit(0)
 		}
 	}
+	return report.Status()
 }
 
 /* The mover function will take two strings as an argument and move the original file/dir to
@@ -99,8 +106,7 @@ func mover(originalLocation, newLocation string) {
 
 	switch {
 	case fileExists(originalLocation) == nil: // If the original file does not exist
-		fmt.Printf("mv: cannot stat '%s': No such file or directory\n", originalLocation)
-		os.Exit(0)
+		report.Errorf("cannot stat %s: %s", diag.Quote(originalLocation), diag.Strerror(syscall.ENOENT))
 	case fp != nil && !*forceEnabled: // If the destination file does not exist and forceEnabled is disabled
 		if fp.IsDir() {
 			base := filepath.Base(originalLocation)
@@ -108,8 +114,6 @@ func mover(originalLocation, newLocation string) {
 				answer := input("File '%s' exists. Overwrite? (y/N): ", newLocation+"/"+base)
 				if answer == "y\n" {
 					try_move(originalLocation, newLocation+"/"+base)
-				} else {
-					os.Exit(0)
 				}
 			} else if fp2 != nil && *forceEnabled {
 				try_move(originalLocation, newLocation+"/"+base)
@@ -120,8 +124,6 @@ func mover(originalLocation, newLocation string) {
 			answer := input("File '%s' exists. Overwrite? (y/N): ", newLocation)
 			if answer == "y\n" {
 				try_move(originalLocation, newLocation)
-			} else {
-				os.Exit(0)
 			}
 		}
 	default: // If the destination file exists and forceEnabled is enabled,
@@ -129,25 +131,19 @@ func mover(originalLocation, newLocation string) {
 	}
 }
 
+/* The try_move function renames the file, copying it instead when the new location is
+ * on another device. Failures are reported and returned. */
+
 func try_move(originalLocation, newLocation string) error {
 	err := os.Rename(originalLocation, newLocation)
-	switch t := err.(type) {
-	case *os.LinkError:
-		fmt.Printf("Cross-device move. Copying instead\n")
-		return move_across_devices(originalLocation, newLocation)
-	case *os.PathError:
-		fmt.Printf("Path error: %q\n", t)
-		return err
-	case *os.SyscallError:
-		fmt.Printf("Syscall error: %q\n", t)
-		return err
-	case nil:
-		return nil
-	default:
-		fmt.Printf("Unkown error Type: %T Error: %q", t, t)
-		return err
+	if errors.Is(err, syscall.EXDEV) {
+		err = move_across_devices(originalLocation, newLocation)
 	}
-	return nil
+	if err != nil {
+		report.Errorf("cannot move %s to %s: %s", diag.Quote(originalLocation),
+			diag.Quote(newLocation), diag.Strerror(err))
+	}
+	return err
 }
 
 func move_across_devices(originalLocation, newLocation string) error {
@@ -180,31 +176,31 @@ func move_across_devices(originalLocation, newLocation string) error {
 	return nil
 }
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
-
-	// We only need one instance of forceEnabled
-
-	if *forceEnabledLong {
-		*forceEnabled = true
+// Main runs mv with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report = diag.New("mv")
+	opts := getopt.New("mv")
+	forceEnabled = opts.Bool('f', "force")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
 	}
 
 	// Display help information
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	// Display version information
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	files := flag.Args() // Obtain a list of files.
-	argumentCheck(files) // Check the number of arguments and process them.
+	files := opts.Args()        // Obtain a list of files.
+	return argumentCheck(files) // Check the number of arguments and process them.
 }
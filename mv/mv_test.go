@@ -0,0 +1,25 @@
+//
+// mv_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package mv
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "mv", []conformance.Case{
+		{Name: "rename", Args: []string{"a.txt", "c.txt"}},
+		{Name: "into-directory", Args: []string{"a.txt", "b.txt", "dir"}},
+		{Name: "force", Args: []string{"-f", "a.txt", "b.txt"}},
+		{Name: "missing-source", Args: []string{"missing", "c.txt"}},
+		{Name: "not-a-directory", Args: []string{"a.txt", "dir", "b.txt"}},
+		{Name: "missing-target", Args: []string{"a.txt", "b.txt", "c.txt"}},
+		{Name: "missing-destination", Args: []string{"a.txt"}},
+		{Name: "missing-operand"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
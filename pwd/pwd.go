@@ -4,12 +4,14 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package pwd
 
 import "fmt"
-import "log"
 import "os"
-import "flag"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
 
 const (
 	help_text string = `
@@ -30,26 +32,39 @@ const (
 `
 )
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("pwd", Main)
+}
+
+// Main runs pwd with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("pwd")
+	opts := getopt.New("pwd")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	if opts.NArg() > 0 {
+		report.Warnf("ignoring non-option arguments")
 	}
 
 	pwd, err := os.Getwd()
 	if err != nil {
-		log.Fatalln(err)
+		report.Errorf("%s", diag.Strerror(err))
 	} else {
 		fmt.Println(pwd)
 	}
-
+	return report.Status()
 }
@@ -0,0 +1,20 @@
+//
+// pwd_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package pwd
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "pwd", []conformance.Case{
+		{Name: "pwd"},
+		{Name: "physical", Args: []string{"-P"},
+			Skip: "pwd has no -L and -P options"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
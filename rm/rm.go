@@ -4,14 +4,17 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package rm
 
-import "flag"
 import "fmt"
 import "io"
 import "os"
 import "syscall"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text string = `
     Usage: rm [OPTION]...
@@ -20,7 +23,8 @@ const (
 
         --help     display this help and exit
         --version  output version information and exit
-        -f         ignore if files do not exist, never prompt
+        -f, --force
+                   ignore if files do not exist, never prompt
         -i         prompt before each removal
         -r, -R, --recursive
             remove directories and their contents recursively
@@ -36,17 +40,28 @@ const (
 )
 
 var (
-	force        = flag.Bool("f", false, "ignore if files do not exist, never prompt")
-	recursiveR   = flag.Bool("R", false, "remove directories and their contents recursively")
-	recursiver   = flag.Bool("r", false, "remove directories and their contents recursively")
-	recursive    = flag.Bool("recursive", false, "remove directories and their contents recursively")
-	interactivei = flag.Bool("i", false, "prompt before each removal")
-	// interactiveI = flag.Bool("I", false, "")
+	force        *bool
+	recursive    *bool
+	interactivei *bool
+	// interactiveI *bool
+
+	report *diag.Reporter
 )
 
+func init() {
+	applet.Register("rm", Main)
+}
+
+// removeError reports that path could not be removed and returns err.
+func removeError(path string, err error) error {
+	report.Errorf("cannot remove %s: %s", diag.Quote(path), diag.Strerror(err))
+	return err
+}
+
 // MODIFIED FROM THE os.RemoveAll() implimentation
 // RemoveAll removes all files/directories below
-// and prompts if the option is set.
+// and prompts if the option is set. Every failure is
+// reported; the first one is returned.
 func RemoveAll(path string) error {
 	var answer string
 	var err error
@@ -54,10 +69,10 @@ func RemoveAll(path string) error {
 	// Is this a directory we need to recurse into?
 	dir, serr := os.Lstat(path)
 	if serr != nil {
-		if serr, ok := serr.(*os.PathError); ok && (os.IsNotExist(serr.Err) || serr.Err == syscall.ENOTDIR) {
+		if serr, ok := serr.(*os.PathError); ok && *force && (os.IsNotExist(serr.Err) || serr.Err == syscall.ENOTDIR) {
 			return nil
 		}
-		return serr
+		return removeError(path, serr)
 	}
 
 	if !dir.IsDir() {
@@ -70,19 +85,21 @@ func RemoveAll(path string) error {
 			}
 
 			if answer == "y" || answer == "yes" {
-				os.Remove(path)
+				err = os.Remove(path)
 			}
 		} else {
-			os.Remove(path)
+			err = os.Remove(path)
 		}
 
+		if err != nil {
+			return removeError(path, err)
+		}
 		return nil
 	}
 
 	// Turns out, it's a directory...
-	if !*recursiveR && !*recursiver && !*recursive {
-		fmt.Printf("rm: '%s' is a directory\n", path)
-		return nil
+	if !*recursive {
+		return removeError(path, syscall.EISDIR)
 	}
 
 	if *interactivei {
@@ -96,7 +113,7 @@ func RemoveAll(path string) error {
 
 			fd, err := os.Open(path)
 			if err != nil {
-				return err
+				return removeError(path, err)
 			}
 
 			// Remove contents & return first error.
@@ -113,8 +130,8 @@ func RemoveAll(path string) error {
 					break
 				}
 				// If Readdirnames returned an error, use it.
-				if err == nil {
-					err = err1
+				if err == nil && err1 != nil {
+					err = removeError(path, err1)
 				}
 				if len(names) == 0 {
 					break
@@ -131,7 +148,7 @@ func RemoveAll(path string) error {
 	} else {
 		fd, err := os.Open(path)
 		if err != nil {
-			return err
+			return removeError(path, err)
 		}
 
 		// Remove contents & return first error.
@@ -148,8 +165,8 @@ func RemoveAll(path string) error {
 				break
 			}
 			// If Readdirnames returned an error, use it.
-			if err == nil {
-				err = err1
+			if err == nil && err1 != nil {
+				err = removeError(path, err1)
 			}
 			if len(names) == 0 {
 				break
@@ -170,40 +187,54 @@ func RemoveAll(path string) error {
 
 		if answer == "y" || answer == "yes" {
 			// Remove directory.
-			err1 := os.Remove(path)
-			if err == nil {
-				err = err1
+			if err1 := os.Remove(path); err1 != nil {
+				err = removeError(path, err1)
 			}
 		}
 
 	} else {
-		// Remove directory.
-		err1 := os.Remove(path)
+		// Remove directory, unless removing its contents failed.
 		if err == nil {
-			err = err1
+			if err1 := os.Remove(path); err1 != nil {
+				err = removeError(path, err1)
+			}
 		}
 	}
 
 	return err
 }
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+// Main runs rm with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report = diag.New("rm")
+	opts := getopt.New("rm")
+	force = opts.Bool('f', "force")
+	recursive = opts.Bool('r', "recursive")
+	opts.BoolVar(recursive, 'R', "")
+	interactivei = opts.Bool('i', "")
+	// interactiveI = opts.Bool("I", false, "")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	files := flag.Args()
+	files := opts.Args()
+	if len(files) == 0 && !*force {
+		return report.Usagef("missing operand")
+	}
 	for i := 0; i < len(files); i++ {
 		RemoveAll(files[i])
 	}
+	return report.Status()
 }
@@ -0,0 +1,24 @@
+//
+// rm_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package rm
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "rm", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "missing", Args: []string{"missing", "a.txt"}},
+		{Name: "force-missing", Args: []string{"-f", "missing"}},
+		{Name: "directory", Args: []string{"dir"}},
+		{Name: "recursive", Args: []string{"-r", "dir"}},
+		{Name: "force", Args: []string{"-f"}},
+		{Name: "missing-operand"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
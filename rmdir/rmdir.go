@@ -4,11 +4,15 @@
 //
 // Written By: Michael Murphy
 //
-package main
+package rmdir
 
-import "flag"
 import "fmt"
 import "os"
+import "syscall"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
 
 const (
 	help_text = `
@@ -16,12 +20,12 @@ const (
 
     Removes directories if they are empty.
 
-        -v, -verbose
+        -v, --verbose
               output a diagnostic for every directory processed
       
-        -help display this help and exit
+        --help display this help and exit
         
-        -version output version information and exit
+        --version output version information and exit
 `
 	version_text = `
     rmdir (go-coreutils) 0.1
@@ -33,64 +37,61 @@ const (
 `
 )
 
-var (
-	verbose     = flag.Bool("v", false, "output a diagnostic for every directory processed.")
-	verboseLong = flag.Bool("verbose", false, "see verbose")
-	help        = flag.Bool("help", false, "display this help and exit")
-	version     = flag.Bool("version", false, "output version information and exit")
-)
-
-// printAndExit prints a message and exits the program.
-func printAndExit(message string) {
-	fmt.Println(message)
-	os.Exit(0)
+func init() {
+	applet.Register("rmdir", Main)
 }
 
 // argumentIsDir returns true if the argument is a directory.
-func argumentIsDir(dir *string) bool {
+func argumentIsDir(report *diag.Reporter, dir *string) bool {
 	file, err := os.Stat(*dir)
+	if err == nil && !file.IsDir() {
+		err = syscall.ENOTDIR
+	}
 	if err != nil {
-		prefix := "rmdir: Failed to remove"
-		fmt.Printf("%s '%s': no such file or directory\n", prefix, *dir)
+		report.Errorf("failed to remove %s: %s", diag.Quote(*dir), diag.Strerror(err))
 		return false
-	} else if !file.IsDir() {
-		prefix := "rmdir: Failed to remove"
-		fmt.Printf("%s '%s': not a directory\n", prefix, *dir)
-		return false
-	} else {
-		return true
 	}
+	return true
 }
 
 // removeDirectory attempts to remove the 'dir' directory.
-func removeDirectory(dir *string) {
+func removeDirectory(report *diag.Reporter, dir *string) {
 	err := os.Remove(*dir)
 	if err != nil {
-		fmt.Printf("rmdir: failed to remove '%s': %s\n", *dir, err)
+		report.Errorf("failed to remove %s: %s", diag.Quote(*dir), diag.Strerror(err))
 	}
 }
 
-func main() {
-	for index := 0; index < flag.NArg(); index++ {
-		arg := flag.Arg(index)
-		if *verbose || *verboseLong {
-			fmt.Printf("rmdir: removing directory, '%s'\n", arg)
-		}
-		if argumentIsDir(&arg) {
-			removeDirectory(&arg)
-		}
+// Main runs rmdir with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("rmdir")
+	opts := getopt.New("rmdir")
+	verbose := opts.Bool('v', "verbose")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
 	}
-}
-
-func init() {
-	flag.Parse()
 	if *help {
-		printAndExit(help_text)
+		fmt.Print(help_text)
+		return 0
 	}
 	if *version {
-		printAndExit(version_text)
+		fmt.Print(version_text)
+		return 0
+	}
+	if opts.NArg() == 0 {
+		return report.Usagef("missing operand")
 	}
-	if flag.NArg() == 0 {
-		printAndExit("Try 'rmdir --help' for more information.")
+
+	for index := 0; index < opts.NArg(); index++ {
+		arg := opts.Arg(index)
+		if *verbose {
+			fmt.Printf("rmdir: removing directory, '%s'\n", arg)
+		}
+		if argumentIsDir(report, &arg) {
+			removeDirectory(report, &arg)
+		}
 	}
+	return report.Status()
 }
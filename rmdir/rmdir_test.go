@@ -0,0 +1,23 @@
+//
+// rmdir_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package rmdir
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "rmdir", []conformance.Case{
+		{Name: "empty", Args: []string{"empty"}},
+		{Name: "verbose", Args: []string{"-v", "empty"}},
+		{Name: "not-empty", Args: []string{"full"}},
+		{Name: "not-a-directory", Args: []string{"a.txt"}},
+		{Name: "missing", Args: []string{"missing", "empty"}},
+		{Name: "missing-operand"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
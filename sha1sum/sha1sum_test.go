@@ -0,0 +1,27 @@
+//
+// sha1sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sha1sum
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sha1sum", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-"}, Stdin: "hello\n"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "check", Args: []string{"-c", "good.sha1"}},
+		{Name: "check-stdin", Args: []string{"--check"}, Stdin: "f572d396fae9206628714fb2ce00f72e94f2258f  a.txt\n"},
+		{Name: "check-mismatch", Args: []string{"-c", "bad.sha1"}},
+		{Name: "check-missing-file", Args: []string{"-c", "missing.sha1"}},
+		{Name: "check-missing-list", Args: []string{"-c", "missing"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -0,0 +1,27 @@
+//
+// sha224sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sha224sum
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sha224sum", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-"}, Stdin: "hello\n"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "check", Args: []string{"-c", "good.sha224"}},
+		{Name: "check-stdin", Args: []string{"--check"}, Stdin: "2d6d67d91d0badcdd06cbbba1fe11538a68a37ec9c2e26457ceff12b  a.txt\n"},
+		{Name: "check-mismatch", Args: []string{"-c", "bad.sha224"}},
+		{Name: "check-missing-file", Args: []string{"-c", "missing.sha224"}},
+		{Name: "check-missing-list", Args: []string{"-c", "missing"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -4,16 +4,11 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package sha256sum
 
-import "bufio"
-import "crypto/sha256"
-import "flag"
-import "fmt"
-import "io"
-import "io/ioutil"
-import "os"
-import "strings"
+import "github.com/aisola/go-coreutils/checksum"
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/sumtool"
 
 const (
 	help_text string = `
@@ -26,7 +21,36 @@ const (
         --help        display this help and exit
         --version     output version information and exit
 
+        -b, --binary  read in binary mode
         -c, --check   check sha256 sums against given list
+        -t, --text    read in text mode (default)
+            --tag     create a BSD-style checksum
+        -z, --zero    end each output line with NUL, not newline, and do not
+                      escape the file names
+        -j, --jobs=N  compute up to N sums at once, one per processor by default
+
+    The following options hash or check whole directory trees:
+        -r, --recursive        read the files under each directory, in sorted
+                               order; with --check, also report the files
+                               removed, and those added under each --root
+            --exclude=PATTERN  with -r, leave out the files and directories
+                               whose name or relative path matches PATTERN
+            --root=DIR         with -c and -r, a directory the lists were
+                               made from; may be given more than once
+
+    Each file under a directory is named by the directory as given followed
+    by its path under it: a list made from a relative directory, such as .,
+    can be checked elsewhere, and one made from an absolute directory only
+    where that directory is. The --exclude patterns are not written in the
+    list; give them again with --check -r, or the files they left out are
+    reported as added.
+
+    The following options are useful only when verifying checksums:
+            --ignore-missing  don't fail or report status for missing files
+            --quiet           don't print OK for each successfully verified file
+            --status          don't output anything, status code shows success
+            --strict          exit non-zero for improperly formatted checksum lines
+        -w, --warn            warn about improperly formatted checksum lines
 
     The sums are computed as described in RFC 1321. When checking, the input
     should be a former output of this program. The default mode is to print
@@ -43,99 +67,12 @@ const (
 `
 )
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	check := flag.Bool("check", false, "check sha256 sums against given list")
-	check1 := flag.Bool("c", false, "check sha256 sums against given list")
-	flag.Parse()
-
-	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
-	}
-
-	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
-	}
-
-	// If you are NOT checking...
-	if !*check && !*check1 {
-		if flag.NArg() > 0 {
-			for _, file := range flag.Args() {
-				buff, err := ioutil.ReadFile(file)
-				if err != nil {
-					fmt.Printf("sha256sum: cannot read '%s': %s\n", file, err)
-				}
-				fmt.Printf("%x %s\n", sha256.Sum256(buff), file)
-			}
-		} else {
-			buff, err := ioutil.ReadAll(os.Stdin)
-			if err != nil {
-				fmt.Printf("sha256sum: cannot read 'STDIN': %s\n", err)
-			}
-			fmt.Printf("%x -\n", sha256.Sum256(buff))
-		}
-
-		// Check the files...
-	} else {
-		if flag.NArg() > 0 {
-			NUMFAILED := 0
-			for _, file := range flag.Args() {
-				fp, err := os.Open(file)
-				if err != nil {
-					fmt.Printf("sha256sum: cannot read '%s': %s\n", file, err)
-				}
-				// Set up new reader
-				bf := bufio.NewReader(fp)
-
-				// loop through lines
-				for {
-					// get info
-					line, isPrefix, err := bf.ReadLine()
-
-					// is if EOF?
-					if err == io.EOF {
-						break
-					}
-					// another ERR?
-					if err != nil {
-						fmt.Printf("sha256sum: cannot read '%s': %s\n", file, err)
-					}
-					// is the line WAY too long?
-					if isPrefix {
-						fmt.Printf("sha256sum: unexpected long line: %s\n", file)
-					}
-
-					// success. check the hash
-					hashfile := strings.Split(string(line), " ")
-					HASH := string(hashfile[0])
-					HFIL := string(hashfile[1])
-					buff, err := ioutil.ReadFile(HFIL)
-					if err != nil {
-						fmt.Printf("sha256sum: cannot read '%s': %s\n", HFIL, err)
-					}
-					if HASH == fmt.Sprintf("%x", sha256.Sum256(buff)) {
-						fmt.Printf("%s: OK\n", HFIL)
-					} else {
-						fmt.Printf("%s: FAILED\n", HFIL)
-						NUMFAILED += 1
-					}
-				}
-			}
-			// Print how many TOTAL failed...
-			if NUMFAILED > 0 {
-				fmt.Printf("sha256sum: WARNING: %d computed checksum did NOT match\n", NUMFAILED)
-			}
+func init() {
+	applet.Register("sha256sum", Main)
+}
 
-		} /* TODO: Implement this section...
-		 else {
-			buff, err := ioutil.ReadAll(os.Stdin)
-			if err != nil {
-				fmt.Printf("sha256sum: cannot read 'STDIN': %s", err)
-			}
-			fmt.Printf("%x -\n", sha256.Sum(buff))
-		} */
-	}
+// Main runs sha256sum with the given arguments and returns its exit status.
+func Main(args []string) int {
+	return sumtool.Main(sumtool.Tool{Name: "sha256sum", Algorithm: checksum.SHA256, Help: help_text,
+		Version: version_text}, args)
 }
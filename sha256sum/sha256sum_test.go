@@ -0,0 +1,214 @@
+//
+// sha256sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sha256sum
+
+import "bytes"
+import "os"
+import "path/filepath"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sha256sum", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-"}, Stdin: "hello\n"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "check", Args: []string{"-c", "good.sha256"}},
+		{Name: "check-stdin", Args: []string{"--check"}, Stdin: "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  a.txt\n"},
+		{Name: "check-mismatch", Args: []string{"-c", "bad.sha256"}},
+		{Name: "check-missing-file", Args: []string{"-c", "missing.sha256"}},
+		{Name: "check-missing-list", Args: []string{"-c", "missing"}},
+		{Name: "check-formats", Args: []string{"-c", "formats.sha256"}},
+		{Name: "check-warn", Args: []string{"-c", "--warn", "formats.sha256"}},
+		{Name: "check-strict", Args: []string{"-c", "--strict", "formats.sha256"}},
+		{Name: "check-quiet", Args: []string{"-c", "--quiet", "bad.sha256"}},
+		{Name: "check-status", Args: []string{"-c", "--status", "bad.sha256", "junk.sha256"}},
+		{Name: "check-status-quiet", Args: []string{"-c", "--status", "--quiet", "formats.sha256", "bad.sha256"}},
+		{Name: "check-quiet-status", Args: []string{"-c", "--quiet", "--status", "formats.sha256", "bad.sha256"}},
+		{Name: "check-status-warn", Args: []string{"-c", "--status", "--warn", "formats.sha256", "bad.sha256"}},
+		{Name: "check-warn-status", Args: []string{"-c", "-w", "--status", "formats.sha256", "bad.sha256"}},
+		{Name: "check-quiet-warn", Args: []string{"-c", "--quiet", "--warn", "formats.sha256", "bad.sha256"}},
+		{Name: "check-warn-quiet", Args: []string{"-c", "--warn", "--quiet", "formats.sha256", "bad.sha256"}},
+		{Name: "check-ignore-missing", Args: []string{"-c", "--ignore-missing", "ignore.sha256", "gone.sha256"}},
+		{Name: "check-no-lines", Args: []string{"-c", "junk.sha256", "-"}, Stdin: "garbage\n"},
+		{Name: "check-stdin-dash", Args: []string{"-c"}, Stdin: "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  -\n"},
+		{Name: "check-read-error", Args: []string{"-c", "."}},
+		{Name: "quiet-without-check", Args: []string{"--quiet", "a.txt"}},
+		{Name: "status-warn-without-check", Args: []string{"--status", "--warn", "a.txt"}},
+		{Name: "strict-warn-without-check", Args: []string{"--strict", "--warn", "a.txt"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
+
+// -j is not in GNU sha256sum; hashing in parallel must not change the
+// output.
+func TestJobs(t *testing.T) {
+	args := []string{"a.txt", "missing", "-", "b.txt", "-", "empty.txt"}
+	want := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: args, Stdin: "hello\n"})
+	for _, jobs := range []string{"1", "3", "16"} {
+		c := conformance.Case{Args: append([]string{"-j", jobs}, args...), Stdin: "hello\n"}
+		got := conformance.Exec(t, conformance.Setup(t), c)
+		if got.Status != want.Status || !bytes.Equal(got.Stdout, want.Stdout) || !bytes.Equal(got.Stderr, want.Stderr) {
+			t.Errorf("sha256sum -j %s: got %d, %q, %q; want %d, %q, %q", jobs, got.Status, got.Stdout, got.Stderr,
+				want.Status, want.Stdout, want.Stderr)
+		}
+	}
+}
+
+const (
+	treeA = "b6a98d9ce9a2d9149288fa3df42d377c3e42737afdcdaf714e33c0a100b51060"
+	treeB = "f2c82decdd7181cf98945929a62598db7e6b477e11f6e0eb0ae97020eff151ad"
+	treeO = "eab32d918fc1c07d87eddb59a45086666f9117538d6d9c40ee0efeda635bd330"
+)
+
+// -r and --exclude are not in GNU sha256sum.
+func TestRecursive(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		stdout string
+		stderr string
+	}{
+		{[]string{"-r", "tree"},
+			treeA + "  tree/a.txt\n" + treeO + "  tree/sub/b.o\n" + treeB + "  tree/sub/b.txt\n", ""},
+		{[]string{"-r", "--exclude=*.o", "tree/", "a.txt"},
+			treeA + "  tree/a.txt\n" + treeB + "  tree/sub/b.txt\n" +
+				"5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  a.txt\n", ""},
+		{[]string{"-r", "--exclude=sub", "--tag", "tree"}, "SHA256 (tree/a.txt) = " + treeA + "\n", ""},
+		{[]string{"-r", "--exclude=sub/b.txt", "tree"}, treeA + "  tree/a.txt\n" + treeO + "  tree/sub/b.o\n", ""},
+		{[]string{"tree"}, "", "sha256sum: tree: Is a directory\n"},
+		{[]string{"--exclude=*.o", "tree"}, "",
+			"sha256sum: the --exclude option is meaningful only with --recursive\nTry 'sha256sum --help' for more information.\n"},
+		{[]string{"-r", "--exclude=[", "tree"}, "", "sha256sum: invalid pattern: '['\n"},
+	} {
+		got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: test.args})
+		if string(got.Stdout) != test.stdout || string(got.Stderr) != test.stderr {
+			t.Errorf("sha256sum %q: got %q, %q; want %q, %q", test.args, got.Stdout, got.Stderr, test.stdout, test.stderr)
+		}
+	}
+}
+
+// Checking a manifest with -r also reports the files added under --root
+// and those removed from it.
+func TestRecursiveCheck(t *testing.T) {
+	dir := conformance.Setup(t)
+	manifest := conformance.Exec(t, dir, conformance.Case{Args: []string{"-r", "--exclude=*.o", "tree"}})
+	if err := os.WriteFile(filepath.Join(dir, "tree", "MANIFEST"), manifest.Stdout, 0644); err != nil {
+		t.Fatal(err)
+	}
+	check := conformance.Case{Args: []string{"-c", "-r", "--exclude=*.o", "--root=tree", "tree/MANIFEST"}}
+	if got := conformance.Exec(t, dir, check); got.Status != 0 || string(got.Stdout) != "tree/a.txt: OK\ntree/sub/b.txt: OK\n" {
+		t.Errorf("unchanged tree: got %d, %q, %q", got.Status, got.Stdout, got.Stderr)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "tree", "a.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "tree", "sub", "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tree", "sub", "c.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := conformance.Exec(t, dir, check)
+	stdout := "tree/a.txt: FAILED\ntree/sub/b.txt: REMOVED\ntree/sub/c.txt: ADDED\n"
+	stderr := "sha256sum: WARNING: 1 computed checksum did NOT match\n" +
+		"sha256sum: WARNING: 1 listed file was removed\n" +
+		"sha256sum: WARNING: 1 file was added\n"
+	if got.Status != 1 || string(got.Stdout) != stdout || string(got.Stderr) != stderr {
+		t.Errorf("changed tree: got %d, %q, %q; want 1, %q, %q", got.Status, got.Stdout, got.Stderr, stdout, stderr)
+	}
+
+	// The --exclude patterns are not in the manifest: without them, the
+	// files they left out are added.
+	noExclude := conformance.Case{Args: []string{"-c", "-r", "--root=tree", "tree/MANIFEST"}}
+	got = conformance.Exec(t, dir, noExclude)
+	stdout = "tree/a.txt: FAILED\ntree/sub/b.txt: REMOVED\ntree/sub/b.o: ADDED\ntree/sub/c.txt: ADDED\n"
+	if got.Status != 1 || string(got.Stdout) != stdout {
+		t.Errorf("without --exclude: got %d, %q; want 1, %q", got.Status, got.Stdout, stdout)
+	}
+
+	// Without -r, only the listed files are checked.
+	check.Args = []string{"-c", "--ignore-missing", "tree/MANIFEST"}
+	if got := conformance.Exec(t, dir, check); got.Status != 1 || string(got.Stdout) != "tree/a.txt: FAILED\n" {
+		t.Errorf("without -r: got %d, %q, %q", got.Status, got.Stdout, got.Stderr)
+	}
+}
+
+// The files added are looked for under the directories given with --root,
+// not under the one that holds the listed files.
+func TestRecursiveCheckRoot(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "u", "only"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "u", "only", "f"), []byte("f\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := conformance.Exec(t, dir, conformance.Case{Args: []string{"-r", "u"}})
+	if err := os.WriteFile(filepath.Join(dir, "m2"), manifest.Stdout, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "u", "added-top"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	usage := "Try 'sha256sum --help' for more information.\n"
+	for _, test := range []struct {
+		args           []string
+		status         int
+		stdout, stderr string
+	}{
+		{[]string{"-c", "-r", "--root=u", "m2"}, 1, "u/only/f: OK\nu/added-top: ADDED\n",
+			"sha256sum: WARNING: 1 file was added\n"},
+		{[]string{"-c", "-r", "--root=./u/", "--root=u/only", "m2"}, 1, "u/only/f: OK\nu/added-top: ADDED\n",
+			"sha256sum: WARNING: 1 file was added\n"},
+		{[]string{"-c", "-r", "--root=u/only", "m2"}, 0, "u/only/f: OK\n", ""},
+		{[]string{"-c", "-r", "m2"}, 1, "", "sha256sum: --check --recursive requires --root=DIR\n" + usage},
+		{[]string{"-c", "--root=u", "m2"}, 1, "",
+			"sha256sum: the --root option is meaningful only with --recursive\n" + usage},
+		{[]string{"-r", "--root=u", "u"}, 1, "",
+			"sha256sum: the --root option is meaningful only when verifying checksums\n" + usage},
+	} {
+		got := conformance.Exec(t, dir, conformance.Case{Args: test.args})
+		if got.Status != test.status || string(got.Stdout) != test.stdout || string(got.Stderr) != test.stderr {
+			t.Errorf("sha256sum %q: got %d, %q, %q; want %d, %q, %q", test.args, got.Status, got.Stdout, got.Stderr,
+				test.status, test.stdout, test.stderr)
+		}
+	}
+}
+
+// Files are named by the directory as given, so a manifest of . can be
+// checked in a copy of the tree, and one of an absolute directory names it.
+func TestRecursiveNames(t *testing.T) {
+	dir := conformance.Setup(t)
+	tree := filepath.Join(dir, "tree")
+	got := conformance.Exec(t, tree, conformance.Case{Args: []string{"-r", "--exclude=*.o", "."}})
+	want := treeA + "  a.txt\n" + treeB + "  sub/b.txt\n"
+	if got.Status != 0 || string(got.Stdout) != want {
+		t.Fatalf("sha256sum -r .: got %d, %q; want 0, %q", got.Status, got.Stdout, want)
+	}
+
+	copied := conformance.Setup(t)
+	if err := os.WriteFile(filepath.Join(copied, "MANIFEST"), got.Stdout, 0644); err != nil {
+		t.Fatal(err)
+	}
+	check := conformance.Case{Args: []string{"-c", "-r", "--exclude=*.o", "--exclude=MANIFEST", "--root=.",
+		filepath.Join(copied, "MANIFEST")}}
+	if got := conformance.Exec(t, filepath.Join(copied, "tree"), check); got.Status != 0 ||
+		string(got.Stdout) != "a.txt: OK\nsub/b.txt: OK\n" {
+		t.Errorf("checking a copy: got %d, %q, %q", got.Status, got.Stdout, got.Stderr)
+	}
+
+	got = conformance.Exec(t, dir, conformance.Case{Args: []string{"-r", "--exclude=*.o", tree}})
+	want = treeA + "  $DIR/tree/a.txt\n" + treeB + "  $DIR/tree/sub/b.txt\n" // Exec writes dir as $DIR
+	if got.Status != 0 || string(got.Stdout) != want {
+		t.Errorf("sha256sum -r %s: got %d, %q; want 0, %q", tree, got.Status, got.Stdout, want)
+	}
+}
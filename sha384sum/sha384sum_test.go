@@ -0,0 +1,27 @@
+//
+// sha384sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sha384sum
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sha384sum", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-"}, Stdin: "hello\n"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "check", Args: []string{"-c", "good.sha384"}},
+		{Name: "check-stdin", Args: []string{"--check"}, Stdin: "1d0f284efe3edea4b9ca3bd514fa134b17eae361ccc7a1eefeff801b9bd6604e01f21f6bf249ef030599f0c218f2ba8c  a.txt\n"},
+		{Name: "check-mismatch", Args: []string{"-c", "bad.sha384"}},
+		{Name: "check-missing-file", Args: []string{"-c", "missing.sha384"}},
+		{Name: "check-missing-list", Args: []string{"-c", "missing"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -0,0 +1,27 @@
+//
+// sha512sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sha512sum
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sha512sum", []conformance.Case{
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-"}, Stdin: "hello\n"},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "check", Args: []string{"-c", "good.sha512"}},
+		{Name: "check-stdin", Args: []string{"--check"}, Stdin: "e7c22b994c59d9cf2b48e549b1e24666636045930d3da7c1acb299d1c3b7f931f94aae41edda2c2b207a36e10f8bcb8d45223e54878f5b316e7ce3b6bc019629  a.txt\n"},
+		{Name: "check-mismatch", Args: []string{"-c", "bad.sha512"}},
+		{Name: "check-missing-file", Args: []string{"-c", "missing.sha512"}},
+		{Name: "check-missing-list", Args: []string{"-c", "missing"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
@@ -4,13 +4,15 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package sleep
 
 import (
-	"flag"
 	"fmt"
-	"os"
 	"time"
+
+	"github.com/aisola/go-coreutils/internal/applet"
+	"github.com/aisola/go-coreutils/internal/diag"
+	"github.com/aisola/go-coreutils/internal/getopt"
 )
 
 const (
@@ -37,39 +39,43 @@ const (
 `
 )
 
-func usage() {
-	fmt.Printf("sleep: missing operand\nTry 'sleep --help' for more information.\n")
-}
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("sleep", Main)
+}
 
-	if flag.NArg() == 0 && flag.NFlag() == 0 {
-		usage()
-		os.Exit(1)
+// Main runs sleep with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("sleep")
+	opts := getopt.New("sleep")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
 	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	if opts.NArg() == 0 {
+		return report.Usagef("missing operand")
 	}
 
 	var total time.Duration
 
 	// coreutil's sleep says: "Given two or more arguments, pause for the amount
 	// of time specified by the sum of their value"
-	for i := 0; i < flag.NArg(); i++ {
-		d, err := time.ParseDuration(flag.Arg(i))
+	for i := 0; i < opts.NArg(); i++ {
+		d, err := time.ParseDuration(opts.Arg(i))
 		if err != nil {
-			fmt.Printf("sleep: invalid time interval '%s'\n", flag.Arg(i))
-			os.Exit(1)
+			return report.Usagef("invalid time interval %s", diag.Quote(opts.Arg(i)))
 		}
 
 		total = total + d
@@ -77,4 +83,5 @@ func main() {
 
 	// sleep for a total time of passed times
 	time.Sleep(total)
+	return 0
 }
@@ -0,0 +1,25 @@
+//
+// sleep_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sleep
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sleep", []conformance.Case{
+		{Name: "zero", Args: []string{"0"}},
+		{Name: "fraction", Args: []string{"0.01"},
+			Skip: "sleep does not accept fractional intervals"},
+		{Name: "suffix", Args: []string{"0.01s"}},
+		{Name: "sum", Args: []string{"0.01", "0.01"},
+			Skip: "sleep does not accept fractional intervals"},
+		{Name: "invalid", Args: []string{"x"}},
+		{Name: "missing-operand"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
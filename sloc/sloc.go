@@ -0,0 +1,343 @@
+//
+// sloc.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// Package sloc counts the lines of source code, comments and blank lines in
+// a program, as printed by wc --sloc:
+//
+//	counts, err := sloc.ForFile("main.go").Count(r)
+//	fmt.Println(counts.Code, counts.Comment, counts.Blank)
+//
+// A line with any code on it is a line of code, even if it also has a
+// comment. A line with only a comment, or inside a block comment, is a
+// comment line, and a line with nothing but white space is blank. Comment
+// markers inside strings are not comments.
+package sloc
+
+import "bufio"
+import "bytes"
+import "io"
+import "path/filepath"
+import "strings"
+
+// Language describes the comments and strings of a programming language.
+type Language struct {
+	Name       string
+	Extensions []string // such as ".go", in lower case
+
+	LineComments  []string    // start a comment that ends with the line
+	BlockComments [][2]string // the start and end of comments that may span lines
+	Nested        bool        // block comments may be nested, as in Rust
+	Splices       bool        // a backslash at the end of a line joins the next one to it, as in C
+
+	Quotes          []string    // start and end strings on one line, which may contain escapes
+	MultilineQuotes []string    // start and end strings that may span lines and contain escapes
+	RawQuotes       [][2]string // the start and end of strings that may span lines, without escapes
+}
+
+var (
+	Go = &Language{
+		Name:          "Go",
+		Extensions:    []string{".go"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        []string{`"`, `'`},
+		RawQuotes:     [][2]string{{"`", "`"}},
+	}
+	C = &Language{
+		Name:          "C",
+		Extensions:    []string{".c", ".h"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Splices:       true,
+		Quotes:        []string{`"`, `'`},
+	}
+	CPlusPlus = &Language{
+		Name:          "C++",
+		Extensions:    []string{".cc", ".cpp", ".cxx", ".c++", ".hh", ".hpp", ".hxx", ".h++"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Splices:       true,
+		Quotes:        []string{`"`, `'`},
+	}
+	Java = &Language{
+		Name:          "Java",
+		Extensions:    []string{".java"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        []string{`"`, `'`},
+		RawQuotes:     [][2]string{{`"""`, `"""`}},
+	}
+	JavaScript = &Language{
+		Name:          "JavaScript",
+		Extensions:    []string{".js", ".mjs", ".cjs", ".jsx"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        []string{`"`, `'`},
+		RawQuotes:     [][2]string{{"`", "`"}},
+	}
+	TypeScript = &Language{
+		Name:          "TypeScript",
+		Extensions:    []string{".ts", ".mts", ".cts", ".tsx"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        []string{`"`, `'`},
+		RawQuotes:     [][2]string{{"`", "`"}},
+	}
+	Rust = &Language{
+		Name:            "Rust",
+		Extensions:      []string{".rs"},
+		LineComments:    []string{"//"},
+		BlockComments:   [][2]string{{"/*", "*/"}},
+		Nested:          true,
+		MultilineQuotes: []string{`"`}, // not ', which also starts a lifetime
+	}
+	Python = &Language{
+		Name:         "Python",
+		Extensions:   []string{".py", ".pyw"},
+		LineComments: []string{"#"},
+		Quotes:       []string{`"`, `'`},
+		RawQuotes:    [][2]string{{`"""`, `"""`}, {`'''`, `'''`}},
+	}
+	Shell = &Language{
+		Name:            "Shell",
+		Extensions:      []string{".sh", ".bash", ".ksh", ".zsh"},
+		LineComments:    []string{"#"},
+		MultilineQuotes: []string{`"`},
+		RawQuotes:       [][2]string{{`'`, `'`}},
+	}
+	YAML = &Language{
+		Name:         "YAML",
+		Extensions:   []string{".yaml", ".yml"},
+		LineComments: []string{"#"},
+		Quotes:       []string{`"`, `'`},
+	}
+
+	// Text has no comments, so each line is either code or blank. It is
+	// used for the files in no other language.
+	Text = &Language{Name: "Text"}
+)
+
+// Languages lists the languages ForFile knows.
+var Languages = []*Language{Go, C, CPlusPlus, Java, JavaScript, TypeScript, Rust, Python, Shell, YAML}
+
+// ForFile returns the language of the named file, by its extension, or nil
+// if it is in none of Languages.
+func ForFile(name string) *Language {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, lang := range Languages {
+		for _, e := range lang.Extensions {
+			if e == ext {
+				return lang
+			}
+		}
+	}
+	return nil
+}
+
+// ForName returns the language called name, ignoring case, or nil.
+func ForName(name string) *Language {
+	for _, lang := range append(Languages, Text) {
+		if strings.EqualFold(lang.Name, name) {
+			return lang
+		}
+	}
+	return nil
+}
+
+// Counts are the numbers of lines of each kind.
+type Counts struct {
+	Code    int
+	Comment int
+	Blank   int
+}
+
+// Add adds the counts of another file to c.
+func (c *Counts) Add(other Counts) {
+	c.Code += other.Code
+	c.Comment += other.Comment
+	c.Blank += other.Blank
+}
+
+// Count reads r to the end and counts its lines.
+func (l *Language) Count(r io.Reader) (Counts, error) {
+	s := l.NewScanner()
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			s.Line(line)
+		}
+		if err == io.EOF {
+			return s.Counts, nil
+		}
+		if err != nil {
+			return s.Counts, err
+		}
+	}
+}
+
+// Scanner counts the lines of a file as they are given to it one at a time.
+// It remembers whether a line ends inside a comment or a string.
+type Scanner struct {
+	Counts Counts
+
+	lang  *Language
+	depth int    // how many block comments are open
+	open  string // what starts a block comment nested in the open one
+	end   string // what ends the open block comment or raw string
+	raw   bool   // a raw string is open
+
+	multiline string // the quote that ends the open multiline string
+
+	continued bool // the line comment of the line before goes on, after a backslash
+}
+
+// NewScanner returns a Scanner for a file in the language l.
+func (l *Language) NewScanner() *Scanner {
+	return &Scanner{lang: l}
+}
+
+// Line counts one line, with or without its newline.
+func (s *Scanner) Line(line []byte) {
+	if s.continued {
+		s.continued = spliced(line)
+		s.Counts.Comment++
+		return
+	}
+	code, comment := s.raw || s.multiline != "", s.depth > 0
+	quote := "" // the quote that ends the string open on this line
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		switch {
+		case s.depth > 0:
+			if s.lang.Nested && hasPrefix(rest, s.open) {
+				s.depth++
+				i += len(s.open)
+			} else if hasPrefix(rest, s.end) {
+				s.depth--
+				i += len(s.end)
+			} else {
+				i++
+			}
+		case s.raw:
+			if hasPrefix(rest, s.end) {
+				s.raw = false
+				i += len(s.end)
+			} else {
+				i++
+			}
+		case quote != "":
+			switch {
+			case line[i] == '\\':
+				i += 2
+			case hasPrefix(rest, quote):
+				quote = ""
+				i += 1
+			default:
+				i++
+			}
+		case s.multiline != "":
+			switch {
+			case line[i] == '\\':
+				i += 2
+			case hasPrefix(rest, s.multiline):
+				i += len(s.multiline)
+				s.multiline = ""
+			default:
+				i++
+			}
+		case isSpace(line[i]):
+			i++
+		default:
+			if s.startsLineComment(rest) {
+				comment = true
+				s.continued = s.lang.Splices && spliced(line)
+				i = len(line)
+				break
+			}
+			if open, end, ok := s.startsBlockComment(rest); ok {
+				comment = true
+				s.depth, s.open, s.end = 1, open, end
+				i += len(open)
+				break
+			}
+			code = true
+			if open, end, ok := s.startsRawString(rest); ok {
+				s.raw, s.end = true, end
+				i += len(open)
+			} else if q, ok := s.startsString(rest, s.lang.MultilineQuotes); ok {
+				s.multiline = q
+				i += len(q)
+			} else if q, ok := s.startsString(rest, s.lang.Quotes); ok {
+				quote = q
+				i += len(q)
+			} else {
+				i++
+			}
+		}
+	}
+	switch {
+	case code:
+		s.Counts.Code++
+	case comment:
+		s.Counts.Comment++
+	default:
+		s.Counts.Blank++
+	}
+}
+
+func (s *Scanner) startsLineComment(b []byte) bool {
+	for _, c := range s.lang.LineComments {
+		if hasPrefix(b, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) startsBlockComment(b []byte) (open, end string, ok bool) {
+	for _, c := range s.lang.BlockComments {
+		if hasPrefix(b, c[0]) {
+			return c[0], c[1], true
+		}
+	}
+	return "", "", false
+}
+
+// startsRawString is checked before startsString, as """ starts a raw
+// string where " starts another.
+func (s *Scanner) startsRawString(b []byte) (open, end string, ok bool) {
+	for _, q := range s.lang.RawQuotes {
+		if hasPrefix(b, q[0]) {
+			return q[0], q[1], true
+		}
+	}
+	return "", "", false
+}
+
+func (s *Scanner) startsString(b []byte, quotes []string) (string, bool) {
+	for _, q := range quotes {
+		if hasPrefix(b, q) {
+			return q, true
+		}
+	}
+	return "", false
+}
+
+// spliced reports whether line ends with a backslash before its newline,
+// which joins the next line to it.
+func spliced(line []byte) bool {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	return bytes.HasSuffix(line, []byte("\\"))
+}
+
+func hasPrefix(b []byte, prefix string) bool {
+	return prefix != "" && bytes.HasPrefix(b, []byte(prefix))
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
@@ -0,0 +1,58 @@
+//
+// sloc_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sloc
+
+import "strings"
+import "testing"
+
+func TestCount(t *testing.T) {
+	for _, test := range []struct {
+		lang *Language
+		src  string
+		want Counts
+	}{
+		{Go, "package main\n\n// Main.\nfunc main() {} // done\n", Counts{2, 1, 1}},
+		{Go, "/* a\n\n   b */\nx := 1 /* c\n*/ y := 2\n", Counts{2, 3, 0}},
+		{Go, "s := \"// not a comment\"\nt := '\"' /* c */\n", Counts{2, 0, 0}},
+		{Go, "s := `raw\n// in the string\n\n`\n", Counts{4, 0, 0}},
+		{Go, "/* one */ /* two */\n   \t\n", Counts{0, 1, 1}},
+		{C, "char *s = \"a\\\"/*\"; int i;\n// c\n", Counts{1, 1, 0}},
+		{C, "// a \\\n   b \\\n\nint i; // c \\\nd\nint j;\n", Counts{2, 4, 0}},
+		{CPlusPlus, "// a \\\r\nb\r\n// c \\ d\ne\n", Counts{1, 3, 0}},
+		{Go, "// a \\\nb\n", Counts{1, 1, 0}},
+		{Rust, "/* a /* nested */ still\n*/ fn f<'a>() {}\n", Counts{1, 1, 0}},
+		{Rust, "let s = \"/*\";\n", Counts{1, 0, 0}},
+		{Rust, "let s = \"x\n// y\n\";\n// z\n", Counts{3, 1, 0}},
+		{Rust, "let s = \"a \\\"\n// b \\\\\";\n// c\n", Counts{2, 1, 0}},
+		{Shell, "echo \"usage:\n# opt\n\"\n# c\n", Counts{3, 1, 0}},
+		{Shell, "echo \"\\\"\n# in \\\"quotes\\\"\n\" # c\n", Counts{3, 0, 0}},
+		{Python, "def f():\n    \"\"\"Doc\n    # still doc\n    \"\"\"\n    return '#'  # c\n", Counts{5, 0, 0}},
+		{Python, "# comment\n\nx = 1\n", Counts{1, 1, 1}},
+		{Shell, "#!/bin/sh\necho '# quoted\n  still quoted'\n", Counts{2, 1, 0}},
+		{YAML, "# c\nkey: \"#value\"\n", Counts{1, 1, 0}},
+		{JavaScript, "const s = `a\n${b}`; // c\n", Counts{2, 0, 0}},
+		{Text, "a\n\n// b\n", Counts{2, 0, 1}},
+		{Go, "no newline", Counts{1, 0, 0}},
+	} {
+		got, err := test.lang.Count(strings.NewReader(test.src))
+		if err != nil || got != test.want {
+			t.Errorf("%s %q: got %+v, %v; want %+v", test.lang.Name, test.src, got, err, test.want)
+		}
+	}
+}
+
+func TestForFile(t *testing.T) {
+	for name, want := range map[string]*Language{
+		"main.go": Go, "a/b.H": C, "x.cpp": CPlusPlus, "lib.rs": Rust, "setup.py": Python,
+		"run.sh": Shell, "ci.yml": YAML, "app.tsx": TypeScript, "README": nil, "notes.txt": nil,
+	} {
+		if got := ForFile(name); got != want {
+			t.Errorf("ForFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if ForName("c++") != CPlusPlus || ForName("text") != Text || ForName("cobol") != nil {
+		t.Errorf("ForName does not find the languages by name")
+	}
+}
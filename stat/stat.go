@@ -7,18 +7,18 @@
 
 // +build linux
 
+package stat
 
-package main
-
-import "bytes"
-import "flag"
 import "fmt"
-import "io"
 import "os"
-import "strings"
 import "syscall"
 import "time"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/users"
+
 const (
 	isExecutable = 0111              // isExcutable
 	isSymlink    = os.ModeSymlink    // isSymlink
@@ -33,7 +33,7 @@ const (
     
     THIS IS PROGRAM IS IN PROGRESS
 
-        -L, -dereference
+        -L, --dereference
               follow links
           
         --help     display this help and exit
@@ -51,25 +51,20 @@ const (
 )
 
 var (
-	dereference     = flag.Bool("L", false, "")
-	dereferenceLong = flag.Bool("dereference", false, "")
+	opts        *getopt.Set
+	dereference *bool
 )
 
-// Process the initial flags.
-func processFlags() {
-	if *dereferenceLong {
-		*dereference = true
-	}
+func init() {
+	applet.Register("stat", Main)
 }
 
 // Obtain file statistics
-func getFileStat(index int) os.FileInfo {
-	fi, err := os.Lstat(flag.Arg(0))
-	if err != nil {
-		fmt.Printf("stat: fatal: could not open '%s': %s\n", flag.Arg(0), err)
-		os.Exit(0)
+func getFileStat(index int) (os.FileInfo, error) {
+	if *dereference {
+		return os.Stat(opts.Arg(index))
 	}
-	return fi
+	return os.Lstat(opts.Arg(index))
 }
 
 // Obtains all file statistics information
@@ -77,69 +72,18 @@ func getAdditionalFileStat(fi os.FileInfo) *syscall.Stat_t {
 	return fi.Sys().(*syscall.Stat_t)
 }
 
-// Opens the passwd file and returns a buffer of it's contents.
-func bufferUsers() *bytes.Buffer {
-	buffer := bytes.NewBuffer(nil)
-
-	cached, err := os.Open("/etc/passwd")
-	if err != nil {
-		fmt.Println("Error: passwd file does not exist.")
-		os.Exit(0)
-	}
-	io.Copy(buffer, cached)
-	return buffer
-}
-
-// Opens the group file and returns a buffer of it's contents.
-func bufferGroups() *bytes.Buffer {
-	buffer := bytes.NewBuffer(nil)
-
-	cached, err := os.Open("/etc/group")
-	if err != nil {
-		fmt.Println("Error: group file does not exist.")
-		os.Exit(0)
-	}
-
-	io.Copy(buffer, cached)
-	return buffer
-}
-
-// Converts a bytes buffer into a newline-separated string array.
-func bufferToStringArray(buffer *bytes.Buffer) []string {
-	return strings.Split(buffer.String(), "\n")
-}
-
-// Returns a colon separated string array for use in parsing /etc/group and /etc/user
-func parseLine(line string) []string {
-	return strings.Split(line, ":")
-}
-
 // Returns the username associated to a user ID
 func lookupUserID(uid string) string {
-	groupStringArray := bufferToStringArray(bufferUsers())
-	for _, line := range groupStringArray {
-		values := parseLine(line)
-		if len(values) > 2 {
-			if values[2] == uid {
-				return values[0]
-			}
-		}
-
+	if name, ok := users.UserName(uid); ok {
+		return name
 	}
 	return uid
 }
 
 // Returns the groupname associated to a group ID
 func lookupGroupID(gid string) string {
-	groupStringArray := bufferToStringArray(bufferGroups())
-	for _, line := range groupStringArray {
-		values := parseLine(line)
-		if len(values) > 2 {
-			if values[2] == gid {
-				return values[0]
-			}
-		}
-
+	if name, ok := users.GroupName(gid); ok {
+		return name
 	}
 	return gid
 }
@@ -168,7 +112,7 @@ func timespecToTime(ts syscall.Timespec) time.Time {
 
 // Resolve the symbolic link
 func readLink(index int) string {
-	sympath, err := os.Readlink(flag.Arg(index))
+	sympath, err := os.Readlink(opts.Arg(index))
 	if err == nil {
 		return sympath
 	} else {
@@ -212,9 +156,13 @@ func defaultMode(fi os.FileInfo, sys *syscall.Stat_t, userName, groupName string
 }
 
 // Loops through each argument given.
-func argumentLoop() {
-	for index := 0; index < flag.NArg(); index++ {
-		fi := getFileStat(index)                         // Get file stats
+func argumentLoop(report *diag.Reporter) {
+	for index := 0; index < opts.NArg(); index++ {
+		fi, err := getFileStat(index) // Get file stats
+		if err != nil {
+			report.Errorf("cannot statx %s: %s", diag.Quote(opts.Arg(index)), diag.Strerror(err))
+			continue
+		}
 		sys := getAdditionalFileStat(fi)                 // Get lower level file statistics.
 		usr := lookupUserID(fmt.Sprintf("%d", sys.Uid))  // Get user name
 		grp := lookupGroupID(fmt.Sprintf("%d", sys.Gid)) // Get group name
@@ -222,21 +170,31 @@ func argumentLoop() {
 	}
 }
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
-	processFlags()
+// Main runs stat with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("stat")
+	opts = getopt.New("stat")
+	dereference = opts.Bool('L', "dereference")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	if opts.NArg() == 0 {
+		return report.Usagef("missing operand")
 	}
 
-	argumentLoop()
+	argumentLoop(report)
+	return report.Status()
 }
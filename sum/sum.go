@@ -0,0 +1,113 @@
+//
+// sum.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sum
+
+import "fmt"
+import "io"
+import "os"
+
+import "github.com/aisola/go-coreutils/checksum"
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
+const (
+	help_text string = `
+    Usage: sum [OPTION]... [FILE]...
+
+    Print the BSD (16-bit) checksum and the number of blocks of each FILE.
+    If FILE is not given or is -, read standard input.
+
+        --help        display this help and exit
+        --version     output version information and exit
+
+        -r            use BSD sum algorithm (the default), use 1K blocks
+        -s, --sysv    use System V sum algorithm, use 512 bytes blocks
+    `
+	version_text = `
+    sum (go-coreutils) 0.1
+
+    Copyright (C) 2014, The GO-Coreutils Developers.
+    This program comes with ABSOLUTELY NO WARRANTY; for details see
+    LICENSE. This is free software, and you are welcome to redistribute
+    it under certain conditions in LICENSE.
+`
+)
+
+func init() {
+	applet.Register("sum", Main)
+}
+
+// Sum computes the checksum of r with alg, checksum.BSD or checksum.SysV,
+// and formats it with the size of r in blocks.
+func Sum(alg checksum.Algorithm, r io.Reader) (string, error) {
+	h := alg.New()
+	size, err := io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+	return alg.Format(h.Sum(nil), size), nil
+}
+
+// Main runs sum with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("sum")
+	opts := getopt.New("sum")
+	alg := checksum.BSD
+	opts.Func('r', "", getopt.NoArgument, func(string) error {
+		alg = checksum.BSD
+		return nil
+	})
+	opts.Func('s', "sysv", getopt.NoArgument, func(string) error {
+		alg = checksum.SysV
+		return nil
+	})
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
+
+	if *help {
+		fmt.Println(help_text)
+		return 0
+	}
+
+	if *version {
+		fmt.Print(version_text)
+		return 0
+	}
+
+	files := opts.Args()
+	if len(files) == 0 {
+		line, err := Sum(alg, os.Stdin)
+		if err != nil {
+			report.Error("-", err)
+		} else {
+			fmt.Println(line)
+		}
+		return report.Status()
+	}
+	for _, name := range files {
+		fp := os.Stdin
+		if name != "-" {
+			var err error
+			if fp, err = os.Open(name); err != nil {
+				report.Error(name, err)
+				continue
+			}
+		}
+		line, err := Sum(alg, fp)
+		if fp != os.Stdin {
+			fp.Close()
+		}
+		if err != nil {
+			report.Error(name, err)
+			continue
+		}
+		fmt.Println(line, name)
+	}
+	return report.Status()
+}
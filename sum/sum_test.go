@@ -0,0 +1,25 @@
+//
+// sum_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sum
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sum", []conformance.Case{
+		{Name: "files", Args: []string{"a.txt", "b.txt", "empty.txt", "big.bin"}},
+		{Name: "file", Args: []string{"a.txt"}},
+		{Name: "stdin", Stdin: "hello\n"},
+		{Name: "dash", Args: []string{"-", "a.txt"}, Stdin: "hello\n"},
+		{Name: "sysv", Args: []string{"-s", "a.txt", "big.bin"}},
+		{Name: "sysv-stdin", Args: []string{"--sysv"}, Stdin: "hello\n"},
+		{Name: "sysv-bsd", Args: []string{"-s", "-r", "big.bin"}},
+		{Name: "missing-file", Args: []string{"missing", "a.txt"}},
+		{Name: "invalid-option", Args: []string{"-z"}},
+	})
+}
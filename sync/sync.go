@@ -4,21 +4,24 @@
 //
 // Written By: Michael Murphy, Abram C. Isola
 //
-package main
+package sync
 
 import "fmt"
-import "flag"
 import "syscall"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	helpText = `
     Usage: sync [OPTION]
     
     Force changed blocks to disk; update the super block.
     
-        -help display this help and exit
+        --help display this help and exit
         
-        -version
+        --version
               output version information and exit
 `
 	versionText = `
@@ -31,21 +34,28 @@ const (
 `
 )
 
-var (
-	help    = flag.Bool("help", false, "display help information")
-	version = flag.Bool("version", false, "display version information")
-)
-
-func main() {
-	syscall.Sync()
+func init() {
+	applet.Register("sync", Main)
 }
 
-func init() {
-	flag.Parse()
+// Main runs sync with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("sync")
+	opts := getopt.New("sync")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 	if *help {
-		fmt.Println(helpText)
+		fmt.Print(helpText)
+		return 0
 	}
 	if *version {
-		fmt.Println(versionText)
+		fmt.Print(versionText)
+		return 0
 	}
+
+	syscall.Sync()
+	return 0
 }
@@ -0,0 +1,18 @@
+//
+// sync_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package sync
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "sync", []conformance.Case{
+		{Name: "sync"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
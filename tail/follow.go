@@ -0,0 +1,243 @@
+//
+// follow.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package tail
+
+import "errors"
+import "fmt"
+import "io"
+import "math"
+import "os"
+import "strconv"
+import "strings"
+import "time"
+
+import "github.com/aisola/go-coreutils/internal/diag"
+
+// followed is one of the files tail -f keeps reading.
+type followed struct {
+	name   string      // the operand, "-" for standard input
+	file   *os.File    // nil while the file cannot be opened
+	info   os.FileInfo // the file that was opened, to notice a replacement
+	offset int64       // how much of the file has been printed
+	ignore bool        // the file has been given up on
+	pipe   bool        // standard input is a pipe, which is not followed
+}
+
+// open starts following fp, which has been read up to its current offset.
+func (f *followed) open(fp *os.File) {
+	f.file = fp
+	f.info, _ = fp.Stat()
+	f.offset, _ = fp.Seek(0, io.SeekCurrent)
+	if f.name == "-" && f.info != nil {
+		f.pipe = f.info.Mode()&(os.ModeNamedPipe|os.ModeSocket) != 0
+	}
+}
+
+// close stops following the file until it can be opened again.
+func (f *followed) close() {
+	if f.file != nil && f.file != os.Stdin {
+		f.file.Close()
+	}
+	f.file = nil
+}
+
+// A watcher wakes the follow loop early when a followed file may have
+// changed, so that new data is printed as soon as it is written.
+type watcher interface {
+	Add(name string)
+	Wait(timeout time.Duration)
+	Close()
+}
+
+// poller is the watcher used when the system cannot tell tail about changes:
+// it sleeps for the whole interval and tail checks every file in turn.
+type poller struct{}
+
+func (poller) Add(name string)            {}
+func (poller) Wait(timeout time.Duration) { time.Sleep(timeout) }
+func (poller) Close()                     {}
+
+// follower prints the data appended to its files as it is written.
+type follower struct {
+	w        io.Writer
+	files    []*followed
+	byName   bool          // reopen a file when its name refers to a new one
+	retry    bool          // keep trying to open the files that are missing
+	pid      int           // stop once this process has died, if not 0
+	interval time.Duration // the longest wait between two checks
+	headers  bool          // print a header when the output switches files
+	last     *followed     // the file printed last
+	watch    watcher
+}
+
+// following reports whether any of the files can be followed.
+func (f *follower) following() bool {
+	for _, file := range f.files {
+		if !file.pipe {
+			return true
+		}
+	}
+	return false
+}
+
+// close closes all the files.
+func (f *follower) close() {
+	for _, file := range f.files {
+		file.close()
+	}
+}
+
+/* run checks the files for new data until the process f.pid has died, or
+ * until there is no file left that could be read. Between two checks it
+ * waits for the watcher, for no longer than f.interval. */
+func (f *follower) run() {
+	defer f.close()
+	defer f.watch.Close()
+	for _, file := range f.files {
+		if file.file == nil {
+			file.ignore = !f.retry
+		}
+		if file.name != "-" {
+			f.watch.Add(file.name)
+		}
+	}
+	for {
+		dead := f.pid != 0 && !processAlive(f.pid)
+		live := false
+		for _, file := range f.files {
+			if file.ignore || file.pipe {
+				continue
+			}
+			f.check(file)
+			if file.file != nil {
+				f.read(file)
+			}
+			live = live || !file.ignore
+		}
+		if !live {
+			report.Errorf("no files remaining")
+			return
+		}
+		if dead {
+			return
+		}
+		f.watch.Wait(f.interval)
+	}
+}
+
+/* check opens a file that was missing when retrying, and when following by
+ * name notices that the name has gone or now refers to another file. */
+func (f *follower) check(file *followed) {
+	if !f.byName {
+		if file.file == nil {
+			f.reopen(file, "has appeared;  following new file")
+		}
+		return
+	}
+	info, err := os.Stat(file.name)
+	switch {
+	case err != nil:
+		if file.file != nil {
+			report.Warnf("%s has become inaccessible: %s", diag.Quote(file.name), diag.Strerror(err))
+			file.close()
+		}
+		file.ignore = !f.retry
+	case file.file == nil:
+		f.reopen(file, "has appeared;  following new file")
+	case !os.SameFile(info, file.info):
+		f.read(file) // Print what was written to the old file first.
+		file.close()
+		f.reopen(file, "has been replaced;  following new file")
+	}
+}
+
+// reopen opens the file again from its start, saying why.
+func (f *follower) reopen(file *followed, why string) {
+	fp, err := os.Open(file.name)
+	if err != nil {
+		return
+	}
+	report.Warnf("%s %s", diag.Quote(file.name), why)
+	file.open(fp)
+}
+
+// read prints what has been written to the file since it was last read,
+// starting again from its beginning if it has been truncated.
+func (f *follower) read(file *followed) {
+	info, err := file.file.Stat()
+	if err != nil {
+		return
+	}
+	if info.Mode().IsRegular() {
+		if info.Size() < file.offset {
+			report.Warnf("%s: file truncated", diag.QuoteName(file.name))
+			file.offset, _ = file.file.Seek(0, io.SeekStart)
+		}
+		if info.Size() == file.offset {
+			return
+		}
+	} else {
+		// Do not wait on a FIFO that has nothing to read.
+		file.file.SetReadDeadline(time.Now().Add(time.Millisecond))
+	}
+
+	buffer := make([]byte, bufferSize)
+	for {
+		n, err := file.file.Read(buffer)
+		if n > 0 {
+			if f.headers && f.last != file {
+				printHeader(file.name, f.last == nil)
+			}
+			f.last = file
+			f.w.Write(buffer[:n])
+			file.offset += int64(n)
+		}
+		if err == io.EOF || errors.Is(err, os.ErrDeadlineExceeded) {
+			return
+		}
+		if err != nil {
+			report.Errorf("error reading %s: %s", diag.Quote(file.name), diag.Strerror(err))
+			file.close()
+			file.ignore = !f.retry
+			return
+		}
+	}
+}
+
+// setFollow handles -f and --follow, which follows by descriptor unless
+// told to follow by name.
+func setFollow(value string) error {
+	switch {
+	case value == "":
+		follow = "descriptor"
+	case strings.HasPrefix("descriptor", value):
+		follow = "descriptor"
+	case strings.HasPrefix("name", value):
+		follow = "name"
+	default:
+		return fmt.Errorf("invalid argument %s for '--follow'\n"+
+			"Valid arguments are:\n  - 'descriptor'\n  - 'name'", diag.Quote(value))
+	}
+	return nil
+}
+
+// parseInterval parses the number of seconds given to -s.
+func parseInterval(s string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil || seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, strconv.ErrSyntax
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// parsePID parses the process ID given to --pid; no ID is 0.
+func parsePID(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 31)
+	return int(n), err
+}
@@ -0,0 +1,63 @@
+//
+// follow_linux.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+package tail
+
+import "os"
+import "path/filepath"
+import "syscall"
+import "time"
+
+// The events that wake tail up: changes to a followed file itself, and the
+// creation, removal and renaming of files in its directory.
+const (
+	fileEvents = syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+	dirEvents  = syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_CREATE | syscall.IN_DELETE |
+		syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+)
+
+// inotify is a watcher that the kernel wakes when a followed file changes.
+type inotify struct {
+	fd     int
+	file   *os.File // fd, through which Wait can time out
+	buffer []byte
+}
+
+// newWatcher returns an inotify watcher, or a poller if inotify cannot be
+// used.
+func newWatcher() watcher {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return poller{}
+	}
+	return &inotify{fd: fd, file: os.NewFile(uintptr(fd), "inotify"), buffer: make([]byte, 4096)}
+}
+
+// Add watches the named file and its directory, so that tail also hears of
+// a file that is created or replaced. A name that cannot be watched is
+// still checked every interval.
+func (in *inotify) Add(name string) {
+	syscall.InotifyAddWatch(in.fd, name, fileEvents)
+	syscall.InotifyAddWatch(in.fd, filepath.Dir(name), dirEvents)
+}
+
+// Wait returns once some events have arrived or timeout has passed.
+func (in *inotify) Wait(timeout time.Duration) {
+	if err := in.file.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		time.Sleep(timeout)
+		return
+	}
+	in.file.Read(in.buffer)
+}
+
+func (in *inotify) Close() {
+	in.file.Close()
+}
+
+// processAlive reports whether the process pid is still running.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,28 @@
+//
+// follow_other.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+//go:build !linux
+// +build !linux
+
+package tail
+
+import "errors"
+import "os"
+import "syscall"
+
+// newWatcher returns a poller, as tail only knows how to use inotify.
+func newWatcher() watcher {
+	return poller{}
+}
+
+// processAlive reports whether the process pid is still running.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return !errors.Is(err, os.ErrProcessDone) && !errors.Is(err, syscall.ESRCH)
+}
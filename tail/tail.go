@@ -5,15 +5,21 @@
 // Written By: Michael Murphy
 //
 
-package main
+package tail
 
-import "bytes"
-import "flag"
+import "bufio"
+import "errors"
 import "fmt"
 import "io"
-import "io/ioutil"
+import "math"
 import "os"
 import "strings"
+import "syscall"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/units"
 
 const (
 	help_text string = `
@@ -24,18 +30,43 @@ const (
     
     Mandatory arguments to long options are mandatory for short options too.
 
-       -help        display this help and exit
-       -version     output version information and exit
+       --help       display this help and exit
+       --version    output version information and exit
+
+       -c, --bytes=[+]K
+              output the last K bytes; or use -c +K to output bytes starting with the Kth of each file
+
+       -n, --lines=[+]K
+              output the last K lines, instead of the last 10; or use -n +K to output starting with the Kth
 
-       -c, --bytes=K
-              output the last K bytes; or use -n +K to output starting with the Kth byte.
+       -f, --follow[={name|descriptor}]
+              output appended data as the file grows; an absent option argument means 'descriptor'
 
-       -n, -lines=K
-              output the last K lines; or use -n +K to output starting with 
-the Kth
+       -F     same as --follow=name --retry
 
-       -q, -quiet, -silent
+       --pid=PID
+              with -f, terminate after process ID, PID dies
+
+       -q, --quiet, --silent
               never output headers giving file names
+
+       --retry
+              keep trying to open a file if it is inaccessible
+
+       -s, --sleep-interval=N
+              with -f, sleep for approximately N seconds (default 1.0) between iterations; with inotify and --pid=P, check process P at least once every N seconds
+
+       -v, --verbose
+              always output headers giving file names
+
+       -z, --zero-terminated
+              line delimiter is NUL, not newline
+
+    A single option before at most one FILE may also be written in the obsolete form [-+]K[bcl][f], as in tail -5 or tail +2c: the last K lines, or starting with the Kth with +, with b for blocks of 512 bytes, c for bytes, l for lines and f to follow. K is 10 if it is left out.
+
+    K may have a multiplier suffix: b 512, kB 1000, K 1024, MB 1000*1000, M 1024*1024, GB 1000*1000*1000, G 1024*1024*1024, and so on for T, P, E, Z, Y, R, Q. Binary prefixes can be used, too: KiB=K, MiB=M, and so on.
+
+    With --follow (-f), tail defaults to following the file descriptor, which means that even if a tail'ed file is renamed, tail will continue to track its end. This default behavior is not desirable when you really want to track the actual name of the file, not the file descriptor (e.g., log rotation). Use --follow=name in that case. That causes tail to track the named file in a way that accommodates renaming, removal and creation.
 `
 	version_text = `
     tail (go-coreutils) 0.1
@@ -45,149 +76,500 @@ the Kth
     LICENSE. This is free software, and you are welcome to redistribute 
     it under certain conditions in LICENSE.
 `
-	bytes_text  = "output the last K bytes"
-	lines_text  = "output the last K lines"
-	silent_text = "never output headers giving file names"
 )
 
 var (
-	help        = flag.Bool("help", false, help_text)
-	version     = flag.Bool("version", false, version_text)
-	lines       = flag.Int("n", 10, lines_text)
-	linesLong   = flag.Int("lines", 10, lines_text)
-	bytesF      = flag.Int("c", 0, bytes_text)
-	bytesFLong  = flag.Int("bytes", 0, bytes_text)
-	silent      = flag.Bool("q", false, silent_text)
-	silentLong  = flag.Bool("quiet", false, silent_text)
-	silentLong2 = flag.Bool("silent", false, silent_text)
+	count   string // the K given to -n or -c
+	bytesF  bool   // count bytes rather than lines
+	silent  bool   // never output headers giving file names
+	verbose bool   // always output headers giving file names
+	eol     byte   // the byte that ends a line
+
+	follow         string // follow the files by "name" or by "descriptor"
+	retry          bool   // keep trying to open the files
+	pid            string // stop following once this process dies
+	sleepInterval  string // seconds to wait between checks of the files
+	disableInotify bool   // poll even where inotify is available
+
+	report *diag.Reporter
 )
 
-// bufferFile returns a byte slice of the file contents.
-func bufferFile(s string) []byte {
-	buffer, err := ioutil.ReadFile(s)
-	if err != nil {
-		fmt.Println(err, "wc: "+s+": No such file or directory")
-		os.Exit(0)
+// bufferSize is the size of the blocks tail reads.
+const bufferSize = 8192
+
+// Lines copies the last n lines of r, which end with eol, to w. If r can
+// seek, as a regular file can, and is larger than a block, Lines reads
+// backwards from its end; otherwise it reads r to the end keeping only the
+// last n lines. Either way memory use does not depend on the size of the
+// input, and r is left at its end.
+func Lines(w io.Writer, r io.Reader, n int64, eol byte) error {
+	if n <= 0 {
+		return skip(r)
 	}
-	return buffer
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if start, end, ok := extent(rs); ok {
+			return seekLines(w, rs, start, end, n, eol)
+		}
+	}
+	return bufferLines(w, r, n, eol)
 }
 
-// silentCheck prints the file name if silent mode is enabled.
-func silentCheck(filename string) {
-	if !*silent {
-		fmt.Printf("==> %s <==\n", filename)
+// LinesFrom copies the lines of r, which end with eol, to w starting with
+// the k'th, counting from 1.
+func LinesFrom(w io.Writer, r io.Reader, k int64, eol byte) error {
+	reader := bufio.NewReaderSize(r, bufferSize)
+	for ; k > 1; k-- {
+		_, err := reader.ReadSlice(eol)
+		for err == bufio.ErrBufferFull { // Skip the rest of a long line.
+			_, err = reader.ReadSlice(eol)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 	}
+	_, err := reader.WriteTo(w)
+	return err
 }
 
-// multiFileLineProcessor prints that last K lines of every file.
-func multiFileLineProcessor() {
-	for index, currentFile := range flag.Args() {
-		silentCheck(currentFile)
-		printTailingLines(string(bufferFile(currentFile)))
-		if index+1 != flag.NArg() && !*silent {
-			fmt.Println()
+// BytesFrom copies the bytes of r to w starting with the k'th, counting
+// from 1, seeking to it if r can seek.
+func BytesFrom(w io.Writer, r io.Reader, k int64) error {
+	if k > 1 {
+		rs, ok := r.(io.ReadSeeker)
+		var err error
+		if ok {
+			_, err = rs.Seek(k-1, io.SeekCurrent)
+		}
+		if !ok || err != nil {
+			_, err = io.CopyN(io.Discard, r, k-1)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
 	}
+	_, err := io.Copy(w, r)
+	return err
 }
 
-// multiFileByteProcessor prints the last K bytes of every file.
-func multiFileByteProcessor() {
-	for index, currentFile := range flag.Args() {
-		silentCheck(currentFile)
-		printTailingBytes(bufferFile(currentFile))
-		if index+1 != flag.NArg() && !*silent {
-			fmt.Println()
+// Bytes copies the last n bytes of r to w, seeking to them if r can seek.
+func Bytes(w io.Writer, r io.Reader, n int64) error {
+	if n <= 0 {
+		return skip(r)
+	}
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if start, end, ok := extent(rs); ok {
+			if end-n > start {
+				start = end - n
+			}
+			return copyRange(w, rs, start, end)
 		}
 	}
+	return bufferBytes(w, r, n)
 }
 
-/* splitAndCountLines splits the buffered string into a newline-deliminted
- * string slice and returns the slice along with the line count. */
-func splitAndCountLines(buffer string) ([]string, int) {
-	return strings.Split(buffer, "\n"), strings.Count(buffer, "\n")
+// skip moves r to its end if it can seek, so that following it prints only
+// what is appended later.
+func skip(r io.Reader) error {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		rs.Seek(0, io.SeekEnd)
+	}
+	return nil
 }
 
-// printTailingLines prints the last N lines from the input buffer.
-func printTailingLines(buffer string) {
-	lineSlice, totalLines := splitAndCountLines(buffer)
-	lineCount := totalLines
-	if *lines < lineCount {
-		lineCount = *lines
+// extent returns the current offset and the size of rs, or false if rs
+// cannot seek, like a pipe, or if what is left of it is no larger than a
+// block. As in GNU, such a size is not trusted: the files in /proc and /sys
+// report a size of 0 or of a block whatever their length, so they are read
+// as a stream.
+func extent(rs io.ReadSeeker) (start, end int64, ok bool) {
+	start, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, false
 	}
-	for index := totalLines - lineCount; index < totalLines; index++ {
-		fmt.Println(lineSlice[index])
+	end, err = rs.Seek(0, io.SeekEnd)
+	if err != nil || end-start <= bufferSize {
+		rs.Seek(start, io.SeekStart)
+		return 0, 0, false
 	}
+	return start, end, true
 }
 
-// printTailingBytes prints the last N bytes from the input buffer.
-func printTailingBytes(buffer []byte) {
-	totalBytes := len(buffer)
-	byteCount := totalBytes
-	if *bytesF < byteCount {
-		byteCount = *bytesF
+// copyRange copies the bytes of rs from start to end to w.
+func copyRange(w io.Writer, rs io.ReadSeeker, start, end int64) error {
+	if _, err := rs.Seek(start, io.SeekStart); err != nil {
+		return err
 	}
-	for index := totalBytes - byteCount; index < totalBytes; index++ {
-		fmt.Print(string(buffer[index]))
+	_, err := io.CopyN(w, rs, end-start)
+	if err == io.EOF { // The file was truncated meanwhile.
+		return nil
 	}
+	return err
 }
 
-// getStdin will get input from stdin if there are no file arguments for tail.
-func getStdin() {
-	buffer := bytes.NewBuffer(nil)
-	io.Copy(buffer, os.Stdin)
-	if *bytesF == 0 {
-		printTailingLines(buffer.String())
-	} else {
-		printTailingBytes(buffer.Bytes())
+/* seekLines reads rs backwards from end a block at a time, counting the
+ * newlines until it has found the start of the n'th line from the end, and
+ * copies the lines from there. The newline that ends the last line does not
+ * start a line of its own. If rs turns out to be shorter than end, its size
+ * was wrong, and it is read from start as a stream instead. */
+func seekLines(w io.Writer, rs io.ReadSeeker, start, end, n int64, eol byte) error {
+	buffer := make([]byte, bufferSize)
+	for pos := end; pos > start; {
+		size := int64(len(buffer))
+		if pos-start < size {
+			size = pos - start
+		}
+		pos -= size
+		if _, err := rs.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+		_, err := io.ReadFull(rs, buffer[:size])
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if _, err := rs.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+			return bufferLines(w, rs, n, eol)
+		}
+		if err != nil {
+			return err
+		}
+		for i := size - 1; i >= 0; i-- {
+			if buffer[i] != eol || pos+i == end-1 {
+				continue
+			}
+			if n--; n == 0 {
+				return copyRange(w, rs, pos+i+1, end)
+			}
+		}
 	}
+	return copyRange(w, rs, start, end)
 }
 
-// oneFile will use the first file argument as an argument for tail.
-func oneFile() {
-	if *bytesF == 0 {
-		printTailingLines(string(bufferFile(flag.Arg(0))))
-	} else {
-		printTailingBytes(bufferFile(flag.Arg(0)))
+/* bufferLines reads r to the end, keeping the last n lines in a ring, and
+ * writes them out. The ring grows to n lines only as they are read, so a
+ * large n does not cost memory for short inputs. */
+func bufferLines(w io.Writer, r io.Reader, n int64, eol byte) error {
+	var ring [][]byte
+	next := 0 // the oldest line once the ring is full
+	partial := false
+	reader := bufio.NewReaderSize(r, bufferSize)
+	for {
+		chunk, err := reader.ReadSlice(eol)
+		if len(chunk) > 0 {
+			switch {
+			case partial: // The rest of a line longer than the reader's buffer.
+				last := (next + len(ring) - 1) % len(ring)
+				ring[last] = append(ring[last], chunk...)
+			case int64(len(ring)) < n:
+				ring = append(ring, append([]byte(nil), chunk...))
+			default:
+				ring[next] = append(ring[next][:0], chunk...)
+				next = (next + 1) % len(ring)
+			}
+			partial = err == bufio.ErrBufferFull
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != bufio.ErrBufferFull {
+			return err
+		}
+	}
+	for i := range ring {
+		if _, err := w.Write(ring[(next+i)%len(ring)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bufferBytes reads r to the end, keeping at most the last n bytes, and
+// writes them out.
+func bufferBytes(w io.Writer, r io.Reader, n int64) error {
+	var kept []byte
+	buffer := make([]byte, bufferSize)
+	for {
+		size, err := r.Read(buffer)
+		kept = append(kept, buffer[:size]...)
+		if int64(len(kept)) > 2*n+bufferSize {
+			kept = append(kept[:0], kept[int64(len(kept))-n:]...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if int64(len(kept)) > n {
+		kept = kept[int64(len(kept))-n:]
+	}
+	_, err := w.Write(kept)
+	return err
+}
+
+// parseCount parses the K given to -n or -c. A leading '+' asks for the
+// lines or bytes starting with the K'th; a leading '-' is allowed, as the
+// count is from the end anyway.
+func parseCount(s string) (n int64, fromStart bool, err error) {
+	value := s
+	if strings.HasPrefix(value, "+") {
+		fromStart = true
+	} else if strings.HasPrefix(value, "-") {
+		value = value[1:]
+	}
+	size, err := units.ParseSize(value)
+	if size > math.MaxInt64 {
+		size = math.MaxInt64
+	}
+	return int64(size), fromStart, err
+}
+
+// obsolete applies the obsolete form of the options, [-+]NUM[bcl][f], if
+// it is the first of args and followed by at most one file, and returns
+// whether it was. An error means NUM is too large.
+func obsolete(args []string) (bool, error) {
+	switch {
+	case len(args) == 1:
+	case len(args) == 2 && !(strings.HasPrefix(args[1], "-") && args[1] != "-"):
+	case (len(args) == 2 || len(args) == 3) && args[1] == "--":
+	default:
+		return false, nil
+	}
+	arg := args[0]
+	if arg == "" || arg[0] != '-' && arg[0] != '+' || arg == "-" || arg == "-c" {
+		return false, nil
+	}
+	letters := strings.TrimLeft(arg[1:], "0123456789")
+	number, suffix, inBytes := arg[1:len(arg)-len(letters)], "", false
+	if number == "" {
+		number = "10"
+	}
+	switch {
+	case strings.HasPrefix(letters, "b"):
+		suffix, inBytes = "b", true
+		letters = letters[1:]
+	case strings.HasPrefix(letters, "c"):
+		inBytes = true
+		letters = letters[1:]
+	case strings.HasPrefix(letters, "l"):
+		letters = letters[1:]
+	}
+	following := letters == "f"
+	if letters != "" && !following {
+		return false, nil
 	}
+	if _, err := units.ParseSize(number + suffix); err != nil {
+		return true, syscall.ERANGE
+	}
+	count, bytesF = number+suffix, inBytes
+	if arg[0] == '+' {
+		count = "+" + count
+	}
+	if following {
+		follow = "descriptor"
+	}
+	return true, nil
 }
 
-// multipleFiles will launch the proper function for looping through all files.
-func multipleFiles() {
-	if *bytesF == 0 {
-		multiFileLineProcessor()
+// printHeader prints the header that precedes the output for the named
+// file, separated from earlier output by an empty line unless first is true.
+func printHeader(name string, first bool) {
+	if !first {
+		fmt.Println()
+	}
+	if name == "-" {
+		fmt.Println("==> standard input <==")
 	} else {
-		multiFileByteProcessor()
+		fmt.Printf("==> %s <==\n", name)
+	}
+}
+
+// openFile opens the named file, or returns standard input for "-".
+func openFile(name string) (*os.File, error) {
+	if name == "-" {
+		return os.Stdin, nil
 	}
+	return os.Open(name)
 }
 
-func main() {
+// tailFile prints the tail of the file f names, preceded by a header if
+// header is true, and leaves it open in f.file. It returns false if the
+// file cannot be opened.
+func tailFile(f *followed, header, first bool) bool {
+	fp, err := openFile(f.name)
+	if err != nil {
+		report.Errorf("cannot open %s for reading: %s", diag.Quote(f.name), diag.Strerror(err))
+		return false
+	}
+	if header {
+		printHeader(f.name, first)
+	}
+
+	n, fromStart, _ := parseCount(count)
 	switch {
-	case flag.NArg() == 0 || flag.Arg(0) == "-":
-		getStdin()
-	case flag.NArg() == 1:
-		oneFile()
+	case bytesF && fromStart:
+		err = BytesFrom(os.Stdout, fp, n)
+	case bytesF:
+		err = Bytes(os.Stdout, fp, n)
+	case fromStart:
+		err = LinesFrom(os.Stdout, fp, n, eol)
 	default:
-		multipleFiles()
+		err = Lines(os.Stdout, fp, n, eol)
 	}
+	if err != nil {
+		report.Errorf("error reading %s: %s", diag.Quote(f.name), diag.Strerror(err))
+	}
+	f.open(fp)
+	return true
 }
 
 func init() {
-	flag.Parse()
-	if *linesLong != 10 {
-		*lines = *linesLong
+	applet.Register("tail", Main)
+}
+
+// Main runs tail with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report = diag.New("tail")
+	opts := getopt.New("tail")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	count, bytesF = "10", false
+	opts.Func('n', "lines", getopt.RequiredArgument, func(value string) error {
+		count, bytesF = value, false
+		return nil
+	})
+	opts.Func('c', "bytes", getopt.RequiredArgument, func(value string) error {
+		count, bytesF = value, true
+		return nil
+	})
+	silent, verbose = false, false
+	quiet := func(string) error {
+		silent, verbose = true, false
+		return nil
 	}
-	if *silentLong || *silentLong2 {
-		*silent = true
+	opts.Func('q', "quiet", getopt.NoArgument, quiet)
+	opts.Func(0, "silent", getopt.NoArgument, quiet)
+	opts.Func('v', "verbose", getopt.NoArgument, func(string) error {
+		silent, verbose = false, true
+		return nil
+	})
+	zero := opts.Bool('z', "zero-terminated")
+	follow = ""
+	opts.Func('f', "follow", getopt.OptionalArgument, setFollow)
+	opts.Func('F', "", getopt.NoArgument, func(string) error {
+		follow, retry = "name", true
+		return nil
+	})
+	retry = false
+	opts.BoolVar(&retry, 0, "retry")
+	opts.StringVar(&pid, 0, "pid", "")
+	opts.StringVar(&sleepInterval, 's', "sleep-interval", "1")
+	disableInotify = false
+	opts.BoolVar(&disableInotify, 0, "-disable-inotify")
+	// A count is only allowed in the obsolete form, as the only option.
+	misplaced := rune(0)
+	for d := '0'; d <= '9'; d++ {
+		d := d
+		opts.Func(d, "", getopt.NoArgument, func(string) error {
+			misplaced = d
+			return errors.New("option used in invalid context")
+		})
 	}
-	if *bytesFLong != 0 {
-		*bytesF = *bytesFLong
+	if ok, err := obsolete(args); err != nil {
+		report.Errorf("invalid number: %s: %s", diag.Quote(args[0]), diag.Strerror(err))
+		return report.Status()
+	} else if ok {
+		args = args[1:]
+	}
+	if err := opts.Parse(args); misplaced != 0 {
+		report.Errorf("option used in invalid context -- %c", misplaced)
+		return report.Status()
+	} else if err != nil {
+		return report.Usage(err)
 	}
 	if *help {
-		fmt.Println(help_text)
-		os.Exit(0)
+		fmt.Print(help_text)
+		return 0
 	}
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	if _, _, err := parseCount(count); err != nil {
+		what := "lines"
+		if bytesF {
+			what = "bytes"
+		}
+		// As in GNU, a '-' is not part of the count, but a '+' is.
+		value := strings.TrimPrefix(count, "-")
+		if err == units.ErrRange {
+			report.Errorf("invalid number of %s: %s: %s", what, diag.Quote(value), diag.Strerror(err))
+		} else {
+			report.Errorf("invalid number of %s: %s", what, diag.Quote(value))
+		}
+		return report.Status()
+	}
+	eol = '\n'
+	if *zero {
+		eol = 0
+	}
+
+	f := &follower{w: os.Stdout, byName: follow == "name", retry: retry}
+	var err error
+	if f.interval, err = parseInterval(sleepInterval); err != nil {
+		report.Errorf("invalid number of seconds: %s", diag.Quote(sleepInterval))
+		return report.Status()
+	}
+	if f.pid, err = parsePID(pid); err != nil {
+		report.Errorf("invalid PID: %s", diag.Quote(pid))
+		return report.Status()
+	}
+	if retry && follow == "" {
+		report.Warnf("warning: --retry ignored; --retry is useful only when following")
+		f.retry = false
+	} else if retry && follow == "descriptor" {
+		report.Warnf("warning: --retry only effective for the initial open")
+	}
+	if f.pid != 0 && follow == "" {
+		report.Warnf("warning: PID ignored; --pid=PID is useful only when following")
+	}
+
+	files := opts.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	if f.byName {
+		for _, file := range files {
+			if file == "-" {
+				report.Errorf("cannot follow %s by name", diag.Quote(file))
+				return report.Status()
+			}
+		}
+	}
+	f.headers = verbose || len(files) > 1 && !silent
+	for _, file := range files {
+		file := &followed{name: file}
+		if tailFile(file, f.headers, f.last == nil) {
+			f.last = file
+		}
+		f.files = append(f.files, file)
+	}
+	if follow == "" || !f.following() {
+		f.close()
+		return report.Status()
+	}
+	if disableInotify {
+		f.watch = poller{}
+	} else {
+		f.watch = newWatcher()
 	}
+	f.run()
+	return report.Status()
 }
@@ -0,0 +1,244 @@
+//
+// tail_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package tail
+
+import "bytes"
+import "io"
+import "os"
+import "path/filepath"
+import "testing"
+import "time"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+import "github.com/aisola/go-coreutils/internal/diag"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "tail", []conformance.Case{
+		{Name: "default", Args: []string{"a.txt"}},
+		{Name: "lines", Args: []string{"-n", "3", "a.txt"}},
+		{Name: "lines-long", Args: []string{"--lines=2", "a.txt"}},
+		{Name: "lines-zero", Args: []string{"-n", "0", "a.txt"}},
+		{Name: "bytes", Args: []string{"-c", "5", "a.txt"}},
+		{Name: "no-trailing-newline", Args: []string{"-n", "2", "nonl.txt"}},
+		{Name: "empty", Args: []string{"empty.txt"}},
+		{Name: "stdin", Args: []string{"-n", "1"}, Stdin: "a\nb\n"},
+		{Name: "files", Args: []string{"-n", "1", "a.txt", "nonl.txt"}},
+		{Name: "files-quiet", Args: []string{"-q", "-n", "1", "a.txt", "nonl.txt"}},
+		{Name: "big-lines", Args: []string{"-n", "500", "big.txt"}},
+		{Name: "big-bytes", Args: []string{"-c", "10000", "big.txt"}},
+		{Name: "pipe-lines", Args: []string{"-n", "500"}, StdinFile: "big.txt"},
+		{Name: "pipe-bytes", Args: []string{"-c", "10000"}, StdinFile: "big.txt"},
+		{Name: "pipe-all", Args: []string{"-n", "5000"}, StdinFile: "big.txt"},
+		{Name: "pipe-no-trailing-newline", Args: []string{"-n", "2"}, StdinFile: "nonl.txt"},
+		{Name: "long-line", Args: []string{"-n", "2", "longline.txt"}},
+		{Name: "pipe-long-line", Args: []string{"-n", "2"}, StdinFile: "longline.txt"},
+		{Name: "plus-lines", Args: []string{"-n", "+3", "a.txt"}},
+		{Name: "plus-zero", Args: []string{"-n", "+0", "a.txt"}},
+		{Name: "plus-bytes", Args: []string{"-c", "+5", "a.txt"}},
+		{Name: "plus-lines-pipe", Args: []string{"-n", "+990"}, StdinFile: "big.txt"},
+		{Name: "plus-bytes-pipe", Args: []string{"-c", "+29000"}, StdinFile: "big.txt"},
+		{Name: "plus-past-end", Args: []string{"-n", "+100", "a.txt"}},
+		{Name: "minus-lines", Args: []string{"-n", "-2", "a.txt"}},
+		{Name: "bytes-suffix", Args: []string{"-c", "1K", "big.txt"}},
+		{Name: "bytes-suffix-decimal", Args: []string{"-c", "1kB", "big.txt"}},
+		{Name: "bytes-suffix-blocks", Args: []string{"-c", "1b", "big.txt"}},
+		{Name: "lines-suffix", Args: []string{"-n", "+1K", "big.txt"}},
+		{Name: "zero-terminated", Args: []string{"-z", "-n", "2"}, Stdin: "a\x00b\x00c\x00"},
+		{Name: "zero-terminated-file", Args: []string{"-z", "-n", "+2", "a.txt"}},
+		{Name: "verbose", Args: []string{"-v", "-n", "1", "a.txt"}},
+		{Name: "verbose-then-quiet", Args: []string{"-v", "-q", "-n", "1", "a.txt", "nonl.txt"}},
+		{Name: "invalid-lines", Args: []string{"-n", "x", "a.txt"}},
+		{Name: "invalid-plus", Args: []string{"-n", "+ 3", "a.txt"}},
+		{Name: "invalid-suffix", Args: []string{"-c", "1e", "a.txt"}},
+		{Name: "too-large", Args: []string{"-n", "99999999999999999999", "a.txt"}},
+		{Name: "missing-file", Args: []string{"-n", "1", "missing", "a.txt"}},
+		{Name: "follow-dead-pid", Args: []string{"-f", "--pid=2147483647", "a.txt"}},
+		{Name: "follow-files", Args: []string{"-f", "--pid=2147483647", "-n", "1", "a.txt", "nonl.txt"}},
+		{Name: "follow-name", Args: []string{"--follow=name", "--pid=2147483647", "a.txt"}},
+		{Name: "follow-missing", Args: []string{"-f", "--pid=2147483647", "missing"}},
+		{Name: "follow-missing-retry", Args: []string{"-F", "--pid=2147483647", "missing"}},
+		{Name: "follow-pipe", Args: []string{"-f", "-n", "1"}, Stdin: "a\nb\n"},
+		{Name: "follow-stdin-by-name", Args: []string{"-F", "-"}},
+		{Name: "follow-invalid", Args: []string{"--follow=inode", "a.txt"}},
+		{Name: "pid-not-following", Args: []string{"--pid=2147483647", "a.txt"}},
+		{Name: "retry-not-following", Args: []string{"--retry", "a.txt"}},
+		{Name: "retry-descriptor", Args: []string{"-f", "--retry", "--pid=2147483647", "a.txt"}},
+		{Name: "invalid-pid", Args: []string{"-f", "--pid=abc", "a.txt"}},
+		{Name: "invalid-sleep-interval", Args: []string{"-f", "-s", "-1", "a.txt"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+		{Name: "minus-invalid", Args: []string{"-n", "-x", "a.txt"}},
+		{Name: "obsolete-lines", Args: []string{"-2", "a.txt"}},
+		{Name: "obsolete-plus", Args: []string{"+3", "a.txt"}},
+		{Name: "obsolete-bytes", Args: []string{"-3c", "a.txt"}},
+		{Name: "obsolete-plus-bytes", Args: []string{"+5c", "a.txt"}},
+		{Name: "obsolete-blocks", Args: []string{"-1b", "big.txt"}},
+		{Name: "obsolete-default-count", Args: []string{"-l", "big.txt"}},
+		{Name: "obsolete-pipe", Args: []string{"-2"}, Stdin: "a\nb\nc\n"},
+		{Name: "obsolete-double-dash", Args: []string{"-2", "--", "a.txt"}},
+		{Name: "obsolete-two-files", Args: []string{"-2", "a.txt", "nonl.txt"}},
+		{Name: "obsolete-not-a-count", Args: []string{"+x", "a.txt"}},
+		{Name: "obsolete-too-large", Args: []string{"-99999999999999999999", "a.txt"}},
+	})
+}
+
+// scripted is a watcher that makes the next change to the followed files
+// each time tail waits.
+type scripted []func()
+
+func (s *scripted) Add(name string) {}
+func (s *scripted) Close()          {}
+
+func (s *scripted) Wait(timeout time.Duration) {
+	step := (*s)[0]
+	*s = (*s)[1:]
+	step()
+}
+
+// followFile runs a follower on the named file, which has already been
+// printed, making the changes in steps. After the last step tail is told
+// that the writer has died, if it has not given up already. It returns what
+// tail printed.
+func followFile(t *testing.T, name string, byName bool, steps scripted) string {
+	stderr := os.Stderr
+	os.Stderr, _ = os.Open(os.DevNull)
+	report = diag.New("tail")
+	os.Stderr = stderr
+
+	fp, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp.Seek(0, io.SeekEnd)
+	file := &followed{name: name}
+	file.open(fp)
+	var out bytes.Buffer
+	f := &follower{w: &out, files: []*followed{file}, byName: byName, watch: &steps}
+	steps = append(steps, func() { f.pid = 2147483647 })
+	f.run()
+	return out.String()
+}
+
+func appendFile(t *testing.T, name, data string) {
+	fp, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp.WriteString(data)
+	fp.Close()
+}
+
+func TestFollowName(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	appendFile(t, name, "old\n")
+	out := followFile(t, name, true, scripted{
+		func() { appendFile(t, name, "one\n") },
+		func() { os.WriteFile(name, []byte("two\n"), 0666) }, // truncated
+		func() {
+			appendFile(t, name, "rotated\n")
+			os.Rename(name, name+".1")
+			appendFile(t, name, "three\n")
+		},
+		func() { os.Remove(name) },
+	})
+	if want := "one\ntwo\nrotated\nthree\n"; out != want {
+		t.Errorf("tail --follow=name printed %q, want %q", out, want)
+	}
+}
+
+func TestFollowDescriptor(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	appendFile(t, name, "old\n")
+	out := followFile(t, name, false, scripted{
+		func() { appendFile(t, name, "one\n") },
+		func() {
+			os.Rename(name, name+".1")
+			appendFile(t, name+".1", "two\n")
+			appendFile(t, name, "other\n")
+		},
+		func() {
+			os.Remove(name + ".1")
+			appendFile(t, name, "more\n")
+		},
+	})
+	if want := "one\ntwo\n"; out != want {
+		t.Errorf("tail -f printed %q, want %q", out, want)
+	}
+}
+
+// misreported is a file whose size, as seeking to its end tells, is larger
+// than its data, like the files in /proc and /sys.
+type misreported struct {
+	*bytes.Reader
+	size int64
+}
+
+func (m *misreported) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekEnd {
+		m.Reader.Seek(0, io.SeekEnd)
+		return m.size + offset, nil
+	}
+	return m.Reader.Seek(offset, whence)
+}
+
+func TestMisreportedSize(t *testing.T) {
+	big := bytes.Repeat([]byte("line\n"), 3000)
+	for _, test := range []struct {
+		data  []byte
+		size  int64
+		lines int64
+		bytes int64
+		want  string
+	}{
+		{[]byte("always [madvise] never\n"), 4096, 1, 0, "always [madvise] never\n"},
+		{[]byte("always [madvise] never\n"), 4096, 0, 5, "ever\n"},
+		{[]byte("cpu 1\ncpu 2\n"), 0, 1, 0, "cpu 2\n"},
+		{[]byte("cpu 1\ncpu 2\n"), 0, 0, 3, " 2\n"},
+		{big, 5 * bufferSize, 2, 0, "line\nline\n"},
+	} {
+		var out bytes.Buffer
+		r := &misreported{bytes.NewReader(test.data), test.size}
+		var err error
+		if test.lines > 0 {
+			err = Lines(&out, r, test.lines, '\n')
+		} else {
+			err = Bytes(&out, r, test.bytes)
+		}
+		if err != nil || out.String() != test.want {
+			t.Errorf("%d bytes reported as %d, -n %d -c %d: got %q, %v; want %q",
+				len(test.data), test.size, test.lines, test.bytes, out.String(), err, test.want)
+		}
+	}
+}
+
+func TestObsolete(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		ok     bool
+		count  string
+		bytes  bool
+		follow string
+	}{
+		{[]string{"-5"}, true, "5", false, ""},
+		{[]string{"+5c", "f"}, true, "+5", true, ""},
+		{[]string{"-2bf", "--", "f"}, true, "2b", true, "descriptor"},
+		{[]string{"+f"}, true, "+10", false, "descriptor"},
+		{[]string{"-5", "-"}, true, "5", false, ""},
+		{[]string{"-5", "-v"}, false, "10", false, ""},
+		{[]string{"-5", "f", "g"}, false, "10", false, ""},
+		{[]string{"-c"}, false, "10", false, ""},
+		{[]string{"-"}, false, "10", false, ""},
+		{[]string{"f"}, false, "10", false, ""},
+		{[]string{"-5lf2"}, false, "10", false, ""},
+	} {
+		count, bytesF, follow = "10", false, ""
+		ok, err := obsolete(test.args)
+		if ok != test.ok || err != nil || count != test.count || bytesF != test.bytes || follow != test.follow {
+			t.Errorf("obsolete(%q) = %v, %v, count %q, bytes %v, follow %q; want %v, count %q, bytes %v, follow %q",
+				test.args, ok, err, count, bytesF, follow, test.ok, test.count, test.bytes, test.follow)
+		}
+	}
+}
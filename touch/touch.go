@@ -4,13 +4,16 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package touch
 
-import "flag"
 import "fmt"
 import "os"
 import "time"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text string = `
     Usage: touch [OPTION]...
@@ -20,7 +23,8 @@ const (
         --help      display this help and exit
         --version   output version information and exit
 
-        -c          do not create if file does not exist
+        -c, --no-create
+                    do not create if file does not exist
         -t=time     set time to time
     `
 	version_text = `
@@ -33,39 +37,51 @@ const (
 `
 )
 
-func main() {
-	create := flag.Bool("c", false, "do not create if file does not exist")
-	// newTime := flag.Int("t", 0, "set to time provided")
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("touch", Main)
+}
+
+// Main runs touch with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("touch")
+	opts := getopt.New("touch")
+	create := opts.Bool('c', "no-create")
+	// newTime := opts.Int("t", 0, "set to time provided")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	files := flag.Args()
+	files := opts.Args()
+	if len(files) == 0 {
+		return report.Usagef("missing file operand")
+	}
 
 	for i := 0; i < len(files); i++ {
 		now := time.Now()
 
 		err := os.Chtimes(files[i], now, now)
-		if err != nil && *create {
-			fmt.Printf("touch: cannot touch '%s'\n", files[i])
-			os.Exit(1)
+		if err == nil || os.IsNotExist(err) && *create {
+			continue
 		}
 
 		f, err := os.OpenFile(files[i], os.O_CREATE, 0644)
 		if err != nil {
-			fmt.Printf("touch: cannot touch `%s': %s\n", files[i], err)
-			os.Exit(1)
+			report.Errorf("cannot touch %s: %s", diag.Quote(files[i]), diag.Strerror(err))
+			continue
 		}
 		f.Close()
 	}
+	return report.Status()
 }
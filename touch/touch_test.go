@@ -0,0 +1,22 @@
+//
+// touch_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package touch
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "touch", []conformance.Case{
+		{Name: "new", Args: []string{"new"}},
+		{Name: "existing", Args: []string{"a.txt"}},
+		{Name: "no-create", Args: []string{"-c", "missing"}},
+		{Name: "missing-directory", Args: []string{"missing/a.txt", "b.txt"}},
+		{Name: "missing-operand"},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
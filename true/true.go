@@ -4,12 +4,12 @@
 //
 // Written By: Trey Tacon, Abram C. Isola
 //
-package main
+package true
 
 import (
-	"flag"
 	"fmt"
-	"os"
+
+	"github.com/aisola/go-coreutils/internal/applet"
 )
 
 const (
@@ -32,23 +32,22 @@ const (
 `
 )
 
-var (
-	help    = flag.Bool("help", false, help_text)
-	version = flag.Bool("version", false, version_text)
-)
-
-func main() {
-	flag.Parse()
-
-	if *help {
-		fmt.Println(help_text)
-		return
-	}
+func init() {
+	applet.Register("true", Main)
+}
 
-	if *version {
-		fmt.Println(version_text)
-		return
+// Main runs true with the given arguments and returns its exit status.
+// Like GNU true it ignores its arguments, except that a lone --help or
+// --version is honoured.
+func Main(args []string) int {
+	if len(args) == 1 {
+		switch args[0] {
+		case "--help":
+			fmt.Println(help_text)
+		case "--version":
+			fmt.Print(version_text)
+		}
 	}
 
-	os.Exit(0)
+	return 0
 }
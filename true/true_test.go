@@ -0,0 +1,18 @@
+//
+// true_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package true
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "true", []conformance.Case{
+		{Name: "true"},
+		{Name: "ignores-operands", Args: []string{"x", "y"}},
+	})
+}
@@ -0,0 +1,36 @@
+//
+// dot.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package tsort
+
+import "bufio"
+import "fmt"
+import "io"
+import "strings"
+
+// WriteDOT writes the graph to w in the DOT language of Graphviz: its nodes
+// in the order of their names, then its edges in the order they were added.
+// An edge added more than once is written once. Loops are written as they
+// are, whether Run has been called or not.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "digraph tsort {")
+	for _, n := range g.sorted() {
+		fmt.Fprintf(out, "\t%s;\n", dotID(n.name))
+	}
+	seen := make(map[edge]bool)
+	for _, e := range g.edges {
+		if !seen[e] {
+			seen[e] = true
+			fmt.Fprintf(out, "\t%s -> %s;\n", dotID(e.from), dotID(e.to))
+		}
+	}
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
+
+// dotID quotes a name as an ID of the DOT language.
+func dotID(v V) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(string(v)) + `"`
+}
@@ -0,0 +1,72 @@
+//
+// levels.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package tsort
+
+// levels returns the level of each node once the graph is sorted: 0 for a
+// node with no edge into it, and otherwise one more than the highest level
+// of the nodes before it. The edges Run erased to break loops do not count.
+func (g *Graph) levels() map[V]int {
+	g.Run()
+	level := make(map[V]int, len(g.nodes))
+	for _, v := range g.result {
+		for _, m := range g.nodes[v].succ {
+			level[m.name] = max(level[m.name], level[v]+1)
+		}
+	}
+	return level
+}
+
+// Levels returns the nodes grouped by level, each group in the order of the
+// names. The nodes of a level depend only on those of the levels before
+// it, so each group can be processed all at once after the groups before
+// it, such as by jobs running in parallel. Levels runs the sort if Run has
+// not been called, and loops are broken as Run breaks them.
+func (g *Graph) Levels() [][]V {
+	level := g.levels()
+	var groups [][]V
+	for _, n := range g.sorted() {
+		l := level[n.name]
+		for len(groups) <= l {
+			groups = append(groups, nil)
+		}
+		groups[l] = append(groups[l], n.name)
+	}
+	return groups
+}
+
+// CriticalPath returns a longest chain of nodes, each coming before the
+// next, which has a node of every level. Its length is the least number of
+// steps of a schedule by levels. Among paths of the same length, the one
+// whose nodes come first in the order of the names, from its end, is taken.
+// Like Levels, CriticalPath runs the sort if need be.
+func (g *Graph) CriticalPath() []V {
+	level := g.levels()
+	nodes := g.sorted()
+	if len(nodes) == 0 {
+		return nil
+	}
+	end := nodes[0]
+	pred := make(map[V][]V)
+	for _, n := range nodes {
+		if level[n.name] > level[end.name] {
+			end = n
+		}
+		for _, m := range n.succ {
+			pred[m.name] = append(pred[m.name], n.name)
+		}
+	}
+	path := make([]V, level[end.name]+1)
+	v := end.name
+	for l := len(path) - 1; l >= 0; l-- {
+		path[l] = v
+		for _, p := range pred[v] {
+			if level[p] == l-1 {
+				v = p
+				break
+			}
+		}
+	}
+	return path
+}
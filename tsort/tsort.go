@@ -4,25 +4,41 @@
 //
 // Written By: Akira Hayakawa
 //
-package main
+package tsort
 
 import (
 	"bufio"
-	"flag"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"sort"
+
+	"github.com/aisola/go-coreutils/internal/applet"
+	"github.com/aisola/go-coreutils/internal/diag"
+	"github.com/aisola/go-coreutils/internal/getopt"
 )
 
 const (
 	help_text string = `
-    Usage: tsort [OPTIONS] FILE
+    Usage: tsort [OPTIONS] [FILE]
     
-    Topological sort the strings in FILE. Strings are defined as any sequence of tokes separated by
-    whitespace (tab, space, or newline). If FILE it not passed, stdin is used instead.
+    Topological sort the strings in FILE. Strings are defined as any sequence of tokens separated by
+    whitespace (tab, space, or newline), and each pair of them, on one line or across lines, says
+    that the first comes before the second. If FILE is not passed or is -, stdin is used instead.
+
+    Strings that are not ordered by the pairs are written in the order of their bytes. If the
+    pairs contain a loop, its strings are reported on stderr and the order is written anyway,
+    breaking the loop, with an exit status of 1.
 
-        --help        display this help and exit
-        --version     output version information and exit
+        --dot             write the graph in the DOT language of Graphviz instead, loops included
+        --levels          write the strings grouped by level, one level per line: each string
+                          comes after some string of the level before and after none of its own
+                          level or later, so the strings of a level can be processed in parallel
+        --critical-path   write a longest chain of strings, each ordered before the next, one per
+                          line: the number of lines is the number of levels
+        --help            display this help and exit
+        --version         output version information and exit
     `
 	version_text = `
     tsort (go-coreutils) 0.1
@@ -34,110 +50,300 @@ const (
 `
 )
 
-var (
-	help    = flag.Bool("help", false, help_text)
-	version = flag.Bool("version", false, version_text)
-)
+func init() {
+	applet.Register("tsort", Main)
+}
 
-// TODO optimization: use int
+// V is a node of the graph, one of the strings of the input.
 type V string
 
-type Node struct {
-	in_edges  map[V]bool
-	out_edges []V
+// node is a node of the graph with the edges that leave it.
+type node struct {
+	name V
+	// count is the number of edges into the node not yet erased.
+	count int
+	// succ are the nodes the edges lead to, in the order of the input. An
+	// edge given twice is there twice.
+	succ []*node
+	done bool // the node is in the result
+	// qlink is the next node along the path followed by findLoop.
+	qlink *node
 }
 
-func NewNode() *Node {
-	n := Node{}
-	n.in_edges = make(map[V]bool)
-	n.out_edges = make([]V, 0)
-	return &n
+// edge is an edge of the graph as it was added.
+type edge struct {
+	from, to V
 }
 
+// Graph is a directed graph whose edges order its nodes.
 type Graph struct {
-	nodes  map[V]*Node
+	nodes  map[V]*node
+	edges  []edge
+	ran    bool
 	result []V
+	loops  [][]V
 }
 
 func NewGraph() *Graph {
 	g := Graph{}
-	g.nodes = make(map[V]*Node)
+	g.nodes = make(map[V]*node)
 	g.result = make([]V, 0)
 	return &g
 }
 
-func (g *Graph) initNode(n V) {
-	g.nodes[n] = NewNode()
+// node returns the node named n, adding it to the graph if need be.
+func (g *Graph) node(n V) *node {
+	if _, ok := g.nodes[n]; !ok {
+		g.nodes[n] = &node{name: n}
+	}
+	return g.nodes[n]
 }
 
-func (g *Graph) hasNode(n V) bool {
-	_, ok := g.nodes[n]
-	return ok
+// AddEdge records that from must come before to. An edge from a node to
+// itself only adds the node.
+func (g *Graph) AddEdge(from V, to V) {
+	f, t := g.node(from), g.node(to)
+	if f == t {
+		return
+	}
+	t.count++
+	f.succ = append(f.succ, t)
+	g.edges = append(g.edges, edge{from, to})
 }
 
-func (g *Graph) addEdge(from V, to V) {
-	if !g.hasNode(from) {
-		g.initNode(from)
-	}
-	if !g.hasNode(to) {
-		g.initNode(to)
+// sorted returns the nodes in the order of their names.
+func (g *Graph) sorted() []*node {
+	nodes := make([]*node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
 	}
-	_, ok := g.nodes[to].in_edges[from]
-	if ok {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].name < nodes[j].name })
+	return nodes
+}
+
+// Run orders the nodes by the algorithm of Knuth, as GNU tsort does, so
+// that the order is the same: the nodes without edges into them are taken
+// in the order of their names, and each is followed by those of its
+// successors that have no other edge left into them, the successor given
+// last first. When no node is left without edges into it, the rest of the
+// graph contains a loop. One is found and recorded, an edge of it is
+// erased, and the sort goes on. Run does nothing the second time.
+func (g *Graph) Run() {
+	if g.ran {
 		return
 	}
-
-	_n := g.nodes[from]
-	_n.out_edges = append(_n.out_edges, to)
-
-	g.nodes[to].in_edges[from] = true
+	g.ran = true
+	nodes := g.sorted()
+	left := len(nodes)
+	for left > 0 {
+		var queue []*node
+		for _, n := range nodes {
+			if n.count == 0 && !n.done {
+				queue = append(queue, n)
+			}
+		}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			g.result = append(g.result, n.name)
+			n.done = true
+			left--
+			for i := len(n.succ) - 1; i >= 0; i-- {
+				m := n.succ[i]
+				m.count--
+				if m.count == 0 {
+					queue = append(queue, m)
+				}
+			}
+		}
+		if left > 0 {
+			g.loops = append(g.loops, findLoop(nodes))
+		}
+	}
 }
 
-// Kahn's algorithm O(|V|+|E|)
-func (g *Graph) Run() {
-	start_nodes := make([]V, 0)
-	for k, n := range g.nodes {
-		if len(n.in_edges) == 0 {
-			start_nodes = append(start_nodes, k)
+// findLoop returns the nodes of a loop among those not yet sorted, in the
+// order of its edges, and erases the edge that closes it. It follows the
+// edges backwards from the first such node, in the order of the names, as
+// GNU tsort does, until the path comes back on itself.
+func findLoop(nodes []*node) []V {
+	var loop *node
+	for {
+		for _, k := range nodes {
+			if k.count == 0 {
+				continue
+			}
+			if loop == nil {
+				loop = k
+				continue
+			}
+			for i := len(k.succ) - 1; i >= 0; i-- {
+				if k.succ[i] != loop {
+					continue
+				}
+				if k.qlink == nil {
+					k.qlink = loop
+					loop = k
+					break
+				}
+				// k is on the path already: it closes the loop that
+				// goes from the head of the path back to k.
+				var members []V
+				for n := loop; n != nil; n = n.qlink {
+					members = append(members, n.name)
+					if n == k {
+						break
+					}
+				}
+				loop.count--
+				k.succ = append(k.succ[:i], k.succ[i+1:]...)
+				for _, n := range nodes {
+					n.qlink = nil
+				}
+				return members
+			}
 		}
 	}
+}
+
+// Result returns the nodes in the order found by Run.
+func (g *Graph) Result() []V {
+	return g.result
+}
+
+// Loops returns the loops Run found, each as its nodes in the order of
+// their edges. Run erased an edge of each to go on with the sort.
+func (g *Graph) Loops() [][]V {
+	return g.loops
+}
+
+// IsAcyclic reports whether Run found no loop.
+func (g *Graph) IsAcyclic() bool {
+	return len(g.loops) == 0
+}
+
+// Errors returned by ReadGraph and Sort for invalid input.
+var (
+	ErrOddTokens = errors.New("input contains an odd number of tokens")
+	ErrLoop      = errors.New("input contains a loop")
+)
+
+// LoopError is the error of Sort for a graph with loops. It matches ErrLoop
+// for errors.Is.
+type LoopError struct {
+	Loops [][]V
+}
 
-	for len(start_nodes) != 0 {
-		n := start_nodes[0]
-		start_nodes = start_nodes[1:]
+func (e *LoopError) Error() string {
+	return ErrLoop.Error()
+}
 
-		g.result = append(g.result, n)
-		_n := g.nodes[n]
-		for _, m := range _n.out_edges {
-			_m := g.nodes[m]
-			delete(_m.in_edges, n)
-			if len(_m.in_edges) == 0 {
-				start_nodes = append(start_nodes, m)
+func (e *LoopError) Is(target error) bool {
+	return target == ErrLoop
+}
+
+// readToken reads the next string from r, skipping the blanks and newlines
+// before it. It returns io.EOF when there is none left.
+func readToken(r *bufio.Reader) (string, error) {
+	var token []byte
+	for {
+		c, err := r.ReadByte()
+		switch {
+		case err == io.EOF && len(token) > 0:
+			return string(token), nil
+		case err != nil:
+			return "", err
+		case c == ' ' || c == '\t' || c == '\n':
+			if len(token) > 0 {
+				return string(token), nil
 			}
+		default:
+			token = append(token, c)
 		}
-		_n.out_edges = _n.out_edges[:0]
 	}
 }
 
-func (g *Graph) isAcyclic() bool {
-	for _, n := range g.nodes {
-		if len(n.out_edges) != 0 {
-			return false
+// ReadGraph reads strings separated by blanks and newlines from r. Each
+// pair of them, wherever the lines break, gives an edge of the graph.
+func ReadGraph(r io.Reader) (*Graph, error) {
+	g := NewGraph()
+	in := bufio.NewReader(r)
+	var from V
+	odd := false
+	for {
+		token, err := readToken(in)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if odd {
+			g.AddEdge(from, V(token))
+		} else {
+			from = V(token)
 		}
+		odd = !odd
+	}
+	if odd {
+		return nil, ErrOddTokens
+	}
+	return g, nil
+}
+
+// Sort reads a graph from r like ReadGraph and writes its nodes to w in
+// topological order, one per line. If the graph has loops, the order
+// breaks them and Sort returns a *LoopError after writing it.
+func Sort(w io.Writer, r io.Reader) error {
+	g, err := ReadGraph(r)
+	if err != nil {
+		return err
+	}
+	return g.write(w, func(out io.Writer) { writeLines(out, g.Result()) })
+}
+
+// writeLines writes the nodes to out, one per line.
+func writeLines(out io.Writer, nodes []V) {
+	for _, n := range nodes {
+		fmt.Fprintln(out, n)
+	}
+}
+
+// write runs the sort and writes its outcome to w with print. If the graph
+// has loops, it returns a *LoopError after writing.
+func (g *Graph) write(w io.Writer, print func(out io.Writer)) error {
+	g.Run()
+	out := bufio.NewWriter(w)
+	print(out)
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	if !g.IsAcyclic() {
+		return &LoopError{g.Loops()}
 	}
-	return true
+	return nil
 }
 
-func main() {
-	flag.Parse()
+// Main runs tsort with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("tsort")
+	opts := getopt.New("tsort")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	dot := opts.Bool(0, "dot")
+	levels := opts.Bool(0, "levels")
+	critical := opts.Bool(0, "critical-path")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
 	var input string
@@ -145,48 +351,68 @@ func main() {
 	var err error
 
 	switch {
-	case flag.NArg() < 1 || flag.Arg(0) == "-":
+	case opts.NArg() < 1 || opts.Arg(0) == "-":
 		input = "-"
 		fp = os.Stdin
-	case flag.NArg() == 1:
-		input = flag.Arg(0)
+	case opts.NArg() == 1:
+		input = opts.Arg(0)
 		fp, err = os.Open(input)
 		if err != nil {
-			panic(err)
+			report.Error(input, err)
+			return report.Status()
 		}
 		defer fp.Close()
 	default:
-		fmt.Fprintf(os.Stdout, "extra operand %s\n", flag.Arg(1))
-		os.Exit(1)
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(1)))
 	}
-
-	g := NewGraph()
-	scanner := bufio.NewScanner(fp)
-	for scanner.Scan() {
-		var nodes = strings.Fields(scanner.Text())
-		N := len(nodes)
-		if N > 2 {
-			fmt.Fprintf(os.Stdout, "%s: input contains an odd number of tokens\n", input)
-			os.Exit(1)
-		} else if N == 1 {
-			// TODO
-			// 1 \n 2 3 \n 4 is allowed but
-			// 1 \n 2 3 is not allowed
-			os.Exit(1)
+	modes := 0
+	for _, set := range []bool{*dot, *levels, *critical} {
+		if set {
+			modes++
 		}
-		g.addEdge(V(nodes[0]), V(nodes[1]))
 	}
-
-	g.Run()
-
-	if !g.isAcyclic() {
-		fmt.Fprintf(os.Stdout, "%s: input contains a loop\n", input)
-		os.Exit(1)
+	if modes > 1 {
+		return report.Usagef("only one of --dot, --levels and --critical-path may be given")
 	}
 
-	for _, n := range g.result {
-		fmt.Println(n)
+	g, err := ReadGraph(fp)
+	if err != nil {
+		report.Error(input, err)
+		return report.Status()
 	}
-
-	os.Exit(0)
+	switch {
+	case *dot:
+		err = g.WriteDOT(os.Stdout)
+	case *levels:
+		err = g.write(os.Stdout, func(out io.Writer) {
+			for _, group := range g.Levels() {
+				for i, n := range group {
+					if i > 0 {
+						fmt.Fprint(out, " ")
+					}
+					fmt.Fprint(out, n)
+				}
+				fmt.Fprintln(out)
+			}
+		})
+	case *critical:
+		err = g.write(os.Stdout, func(out io.Writer) { writeLines(out, g.CriticalPath()) })
+	default:
+		err = g.write(os.Stdout, func(out io.Writer) { writeLines(out, g.Result()) })
+	}
+	var loop *LoopError
+	switch {
+	case errors.As(err, &loop):
+		// Like GNU tsort, print the nodes of each loop on lines of their
+		// own after the error.
+		for _, members := range loop.Loops {
+			report.Errorf("%s: %s:", diag.QuoteName(input), err)
+			for _, n := range members {
+				report.Errorf("%s", n)
+			}
+		}
+	case err != nil:
+		report.Error(input, err)
+	}
+	return report.Status()
 }
@@ -0,0 +1,66 @@
+//
+// tsort_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package tsort
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "tsort", []conformance.Case{
+		{Name: "chain", Args: []string{"chain.txt"}},
+		{Name: "stdin", Stdin: "a b\nb c\n"},
+		{Name: "pairs-on-one-line", Stdin: "a b b c\n"},
+		{Name: "self-edge", Stdin: "a a\n"},
+		{Name: "loop", Args: []string{"loop.txt"}},
+		{Name: "odd", Stdin: "a b\nc\n"},
+		{Name: "pairs-across-lines", Stdin: "a\nb c\nd\n"},
+		{Name: "single-nodes", Stdin: "z z\ny y\nb a\n"},
+		{Name: "tabs-and-blanks", Stdin: "\t a   b\t\n\n c\ta"},
+		{Name: "duplicate-edges", Stdin: "a b a b b c\n"},
+		{Name: "unordered", Stdin: "c d b d a d f e\n"},
+		{Name: "loop-with-tail", Stdin: "start a\na b\nb a\nb end\n"},
+		{Name: "two-loops", Stdin: "a b b a c d d e e c\n"},
+		{Name: "nested-loops", Stdin: "a b b c c a b a c b d a\n"},
+		{Name: "stdin-dash", Args: []string{"-"}, Stdin: "x y\n"},
+		{Name: "missing-file", Args: []string{"missing"}},
+		{Name: "extra-operand", Args: []string{"a", "b"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
+
+func TestOutputModes(t *testing.T) {
+	const clothes = "shirt tie\ntie jacket\nsocks shoes\npants shoes\npants belt\nbelt jacket\nshirt belt\nshirt tie\n"
+	for _, test := range []struct {
+		args   []string
+		stdin  string
+		status int
+		stdout string
+		stderr string
+	}{
+		{[]string{"--levels"}, clothes, 0, "pants shirt socks\nbelt shoes tie\njacket\n", ""},
+		{[]string{"--critical-path"}, clothes, 0, "pants\nbelt\njacket\n", ""},
+		{[]string{"--dot"}, "a b\nb c\n\"q\" a\nc c\na b\n", 0,
+			"digraph tsort {\n\t\"\\\"q\\\"\";\n\t\"a\";\n\t\"b\";\n\t\"c\";\n" +
+				"\t\"a\" -> \"b\";\n\t\"b\" -> \"c\";\n\t\"\\\"q\\\"\" -> \"a\";\n}\n", ""},
+		{[]string{"--dot"}, "a b b a\n", 0, "digraph tsort {\n\t\"a\";\n\t\"b\";\n\t\"a\" -> \"b\";\n\t\"b\" -> \"a\";\n}\n", ""},
+		{[]string{"--levels"}, "a b b a b c x x\n", 1, "a x\nb\nc\n",
+			"tsort: -: input contains a loop:\ntsort: a\ntsort: b\n"},
+		{[]string{"--critical-path"}, "a b b c d c\n", 0, "a\nb\nc\n", ""},
+		{[]string{"--levels"}, "", 0, "", ""},
+		{[]string{"--critical-path"}, "", 0, "", ""},
+		{[]string{"--levels", "--dot"}, "", 1, "",
+			"tsort: only one of --dot, --levels and --critical-path may be given\nTry 'tsort --help' for more information.\n"},
+		{[]string{"--levels"}, "a b c\n", 1, "", "tsort: -: input contains an odd number of tokens\n"},
+	} {
+		got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: test.args, Stdin: test.stdin})
+		if got.Status != test.status || string(got.Stdout) != test.stdout || string(got.Stderr) != test.stderr {
+			t.Errorf("tsort %q <<< %q: got %d, %q, %q; want %d, %q, %q", test.args, test.stdin,
+				got.Status, got.Stdout, got.Stderr, test.status, test.stdout, test.stderr)
+		}
+	}
+}
@@ -7,47 +7,49 @@
 
 // +build linux
 
-package main
+package uname
 
-import "flag"
 import "fmt"
 import "io/ioutil"
-import "os"
 import "runtime"
 import "strings"
 import "syscall"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text = `
     Usage: uname [OPTION]...
 
     Print certain system information.  With no OPTION, same as -s.
 
-        -help        display this help and exit
-        -version     output version information and exit
+        --help       display this help and exit
+        --version    output version information and exit
 
-        -a, all
+        -a, --all
               print all information, in the following order.
 
-        -s, -kernel-name
+        -s, --kernel-name
               print the kernel name
 
-        -n, -nodename
+        -n, --nodename
               print the network node hostname
 
-        -r, -kernel-release
+        -r, --kernel-release
               print the kernel release
 
-        -v, -kernel-version
+        -v, --kernel-version
               print the kernel version
 
-        -m, -machine
+        -m, --machine
               print the machine hardware name
 
-        -o, -operating-system
+        -o, --operating-system
               print the operating system
 
-        -p, -processor-name
+        -p, --processor-name
               print the processor name
     `
 	version_text = `
@@ -61,28 +63,22 @@ const (
 )
 
 var (
-	help                = flag.Bool("help", false, "print help")
-	version             = flag.Bool("version", false, "print version")
-	printAll            = flag.Bool("a", false, "print all information")
-	printAllLong        = flag.Bool("all", false, "print all information")
-	printKernelname     = flag.Bool("s", false, "print the kernel name")
-	printKernelnameLong = flag.Bool("kernel-name", false, "print the kernel name")
-	printNodename       = flag.Bool("n", false, "print the network node hostname")
-	printNodenameLong   = flag.Bool("nodename", false, "print the network node hostname")
-	printRelease        = flag.Bool("r", false, "print the kernel release")
-	printReleaseLong    = flag.Bool("kernel-release", false, "print the kernel release")
-	printVersion        = flag.Bool("v", false, "print the kernel version")
-	printVersionLong    = flag.Bool("kernel-version", false, "print the kernel version")
-	printMachine        = flag.Bool("m", false, "print the machine architecture")
-	printMachineLong    = flag.Bool("machine", false, "print the machine architecture")
-	printDomain         = flag.Bool("d", false, "print the domain name the machine belongs to")
-	printDomainLong     = flag.Bool("domain", false, "print the domain name the machine belongs to")
-	printOS             = flag.Bool("o", false, "print the operating system")
-	printOSLong         = flag.Bool("operating-system", false, "print the operating system")
-	printProcessor      = flag.Bool("p", false, "print the processor name")
-	printProcessorLong  = flag.Bool("processor-name", false, "print the processor name")
+	opts            *getopt.Set
+	printAll        *bool
+	printKernelname *bool
+	printNodename   *bool
+	printRelease    *bool
+	printVersion    *bool
+	printMachine    *bool
+	printDomain     *bool
+	printOS         *bool
+	printProcessor  *bool
 )
 
+func init() {
+	applet.Register("uname", Main)
+}
+
 // sysinfo stores all information regarding the system in strings.
 type sysinfo struct {
 	name      string
@@ -106,7 +102,7 @@ func utsnameToString(unameArray [65]int8) string {
 }
 
 // Returns the operating system name.
-//TODO: Add additional operating systems.
+// TODO: Add additional operating systems.
 func getOS() string {
 	var osname string
 	if runtime.GOOS == "linux" {
@@ -131,9 +127,11 @@ func getProcessorName() string {
 }
 
 // getSystemInfo returns a sysinfo struct containing system information.
-func getSystemInfo() *sysinfo {
+func getSystemInfo() (*sysinfo, error) {
 	var utsname syscall.Utsname
-	_ = syscall.Uname(&utsname)
+	if err := syscall.Uname(&utsname); err != nil {
+		return nil, err
+	}
 	sys := sysinfo{
 		name:      utsnameToString(utsname.Sysname),
 		node:      utsnameToString(utsname.Nodename),
@@ -144,13 +142,13 @@ func getSystemInfo() *sysinfo {
 		os:        getOS(),
 		processor: getProcessorName(),
 	}
-	return &sys
+	return &sys, nil
 }
 
 /* unameString generates a string for printing based on input arguments and
  * system information gathered by 'sys'. */
 func (sys *sysinfo) unameString() string {
-	if flag.NFlag() == 0 {
+	if opts.NOpt() == 0 {
 		return sys.name
 	}
 	printArray := make([]string, 0)
@@ -160,46 +158,69 @@ func (sys *sysinfo) unameString() string {
 				sys.release, sys.version, sys.machine,
 				sys.processor, sys.os))
 	}
-	if *printKernelname || *printKernelnameLong {
+	if *printKernelname {
 		printArray = append(printArray, sys.name)
 	}
-	if *printNodename || *printNodenameLong {
+	if *printNodename {
 		printArray = append(printArray, sys.node)
 	}
-	if *printRelease || *printReleaseLong {
+	if *printRelease {
 		printArray = append(printArray, sys.release)
 	}
-	if *printVersion || *printVersionLong {
+	if *printVersion {
 		printArray = append(printArray, sys.version)
 	}
-	if *printMachine || *printMachineLong {
+	if *printMachine {
 		printArray = append(printArray, sys.machine)
 	}
-	if *printDomain || *printDomainLong {
+	if *printDomain {
 		printArray = append(printArray, sys.domain)
 	}
-	if *printOS || *printOSLong {
+	if *printOS {
 		printArray = append(printArray, sys.os)
 	}
-	if *printProcessor || *printProcessorLong {
+	if *printProcessor {
 		printArray = append(printArray, sys.processor)
 	}
 	return strings.Join(printArray, " ")
 }
 
-func main() {
-	sys := getSystemInfo()
-	fmt.Println(sys.unameString())
-}
-
-func init() {
-	flag.Parse()
+// Main runs uname with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("uname")
+	opts = getopt.New("uname")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	printAll = opts.Bool('a', "all")
+	printKernelname = opts.Bool('s', "kernel-name")
+	printNodename = opts.Bool('n', "nodename")
+	printRelease = opts.Bool('r', "kernel-release")
+	printVersion = opts.Bool('v', "kernel-version")
+	printMachine = opts.Bool('m', "machine")
+	printDomain = opts.Bool('d', "domain")
+	printOS = opts.Bool('o', "operating-system")
+	printProcessor = opts.Bool('p', "processor-name")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	if opts.NArg() > 0 {
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(0)))
 	}
+
+	sys, err := getSystemInfo()
+	if err != nil {
+		report.Errorf("cannot get system name")
+		return report.Status()
+	}
+	fmt.Println(sys.unameString())
+	return 0
 }
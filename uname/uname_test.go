@@ -0,0 +1,24 @@
+//
+// uname_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// +build linux
+
+package uname
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "uname", []conformance.Case{
+		{Name: "default"},
+		{Name: "kernel-name", Args: []string{"-s"}},
+		{Name: "operating-system", Args: []string{"-o"}},
+		{Name: "extra-operand", Args: []string{"x"}},
+		{Name: "invalid-option", Args: []string{"-x"}},
+	})
+}
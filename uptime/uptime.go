@@ -7,12 +7,13 @@
 
 // +build linux
 
-package main
+package uptime
 
 import "bufio"
 import "bytes"
+import "errors"
 import "fmt"
-import "flag"
+import "io"
 import "io/ioutil"
 import "os"
 import "strconv"
@@ -20,6 +21,10 @@ import "strings"
 import "syscall"
 import "time"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+
 const (
 	help_text string = `
     Usage: uptime
@@ -39,29 +44,49 @@ const (
 `
 )
 
+// Load holds the system load averages over 1, 5 and 15 minutes.
 type Load struct {
 	L1, L5, L15 float64
 }
 
+// Uptime holds the number of seconds the system has been running.
 type Uptime struct {
 	Time float64
 }
 
+// Get reads the load averages from /proc/loadavg.
 func (self *Load) Get() error {
-	line, err := ioutil.ReadFile("/proc/loadavg")
+	fp, err := os.Open("/proc/loadavg")
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	return self.Parse(fp)
+}
+
+// Parse reads the load averages from r, which has the format of
+// /proc/loadavg.
+func (self *Load) Parse(r io.Reader) error {
+	line, err := ioutil.ReadAll(r)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	f := strings.Fields(string(line))
+	if len(f) < 3 {
+		return errors.New("invalid load average")
+	}
 
-	self.L1, _ = strconv.ParseFloat(f[0], 64)
-	self.L5, _ = strconv.ParseFloat(f[1], 64)
-	self.L15, _ = strconv.ParseFloat(f[2], 64)
+	for i, l := range []*float64{&self.L1, &self.L5, &self.L15} {
+		if *l, err = strconv.ParseFloat(f[i], 64); err != nil {
+			return errors.New("invalid load average")
+		}
+	}
 
 	return nil
 }
 
+// Get reads the uptime of the system.
 func (self *Uptime) Get() error {
 	sysinfo := syscall.Sysinfo_t{}
 
@@ -74,6 +99,7 @@ func (self *Uptime) Get() error {
 	return nil
 }
 
+// Format returns the uptime as "N days, HH:MM".
 func (self *Uptime) Format() string {
 	buf := new(bytes.Buffer)
 	w := bufio.NewWriter(buf)
@@ -100,24 +126,56 @@ func (self *Uptime) Format() string {
 	return buf.String()
 }
 
+// Users returns the number of users logged in.
 func Users() int { return 0 }
 
-func main() {
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+// Write writes the line uptime prints to w: the time now, how long the
+// system has been running and the load averages.
+func Write(w io.Writer, now time.Time, up Uptime, load Load) error {
+	_, err := fmt.Fprintf(w, " %s up %s load average: %.2f, %.2f, %.2f\n",
+		now.Format("15:04:05"),
+		up.Format(),
+		load.L1, load.L5, load.L15)
+	return err
+}
+
+func init() {
+	applet.Register("uptime", Main)
+}
+
+// Main runs uptime with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("uptime")
+	opts := getopt.New("uptime")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
+
+	if *help {
+		fmt.Print(help_text)
+		return 0
+	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
 	up := Uptime{}
-	up.Get()
+	if err := up.Get(); err != nil {
+		report.Errorf("cannot get system uptime: %s", diag.Strerror(err))
+		return report.Status()
+	}
 	load := Load{}
-	load.Get()
+	if err := load.Get(); err != nil {
+		report.Error("/proc/loadavg", err)
+		return report.Status()
+	}
 
-	fmt.Printf(" %s up %s load average: %.2f, %.2f, %.2f\n",
-		time.Now().Format("15:04:05"),
-		up.Format(),
-		load.L1, load.L5, load.L15)
+	if err := Write(os.Stdout, time.Now(), up, load); err != nil {
+		report.Error("standard output", err)
+	}
+	return report.Status()
 }
@@ -0,0 +1,38 @@
+//
+// uptime_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+
+// +build linux
+
+package uptime
+
+import "strings"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+// The uptime installed on most systems comes from procps rather than GNU
+// coreutils, so there is nothing to record golden files from.
+func TestUsage(t *testing.T) {
+	got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: []string{"-x"}})
+	want := "uptime: invalid option -- 'x'\nTry 'uptime --help' for more information.\n"
+	if got.Status != 1 || string(got.Stderr) != want {
+		t.Errorf("uptime -x: status %d, stderr %q; want 1, %q", got.Status, got.Stderr, want)
+	}
+}
+
+func TestLoadParse(t *testing.T) {
+	var load Load
+	if err := load.Parse(strings.NewReader("0.25 1.50 2.00 1/123 4567\n")); err != nil {
+		t.Fatal(err)
+	}
+	if load != (Load{0.25, 1.5, 2}) {
+		t.Errorf("Parse = %+v", load)
+	}
+	if err := load.Parse(strings.NewReader("0.25\n")); err == nil {
+		t.Error("Parse accepted a truncated line")
+	}
+}
@@ -0,0 +1,7 @@
+// A comment that a backslash \
+   goes on with.
+int x; // So does this one, \
+after code.
+// Not this one: \ ends the comment.
+int y; /* block */
+
@@ -0,0 +1,6 @@
+package main
+
+// main says hello.
+func main() {
+	println("hello") // greet
+}
@@ -0,0 +1,4 @@
+/* Block
+ * comment.
+ */
+fn main() {}
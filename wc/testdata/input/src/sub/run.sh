@@ -0,0 +1,3 @@
+#!/bin/sh
+
+echo "# not a comment"
@@ -4,30 +4,40 @@
 //
 // Written By: Michael Murphy
 //
-package main
+package wc
 
 import "bufio"
 import "bytes"
-import "flag"
 import "fmt"
+import "io"
 import "os"
+import "path/filepath"
+import "sort"
+import "strconv"
 import "strings"
 import "unicode/utf8"
 
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
+import "github.com/aisola/go-coreutils/internal/parallel"
+import "github.com/aisola/go-coreutils/internal/quotearg"
+import "github.com/aisola/go-coreutils/internal/width"
+import "github.com/aisola/go-coreutils/sloc"
+
 var (
-	countBytes              = flag.Bool("c", false, "Print the byte counts")
-	countBytesL             = flag.Bool("bytes", false, "Print the byte counts")
-	countCharacters         = flag.Bool("m", false, "Print the character counts")
-	countCharactersL        = flag.Bool("chars", false, "Print the character counts")
-	countLines              = flag.Bool("l", false, "Print the newline counts")
-	countLinesL             = flag.Bool("lines", false, "Print the newline counts")
-	countSLOC               = flag.Bool("sloc", false, "Print the source lines of code")
-	occurrenceRef           = flag.String("o", "", "Print the occurrences of a particular word or phrase")
-	countWords              = flag.Bool("w", false, "Print the word counts")
-	countWordsL             = flag.Bool("words", false, "Print the word counts")
-	maxLineLength           = flag.Bool("L", false, "Print the length of the longest line")
-	maxLineLengthL          = flag.Bool("max-line-length", false, "Print the length of the longest line")
-	help_text        string = `
+	countBytes      *bool   // Print the byte counts
+	countCharacters *bool   // Print the character counts
+	countLines      *bool   // Print the newline counts
+	countSLOC       *bool   // Print the source lines of code
+	occurrenceRef   *string // Print the occurrences of a particular word or phrase
+	countWords      *bool   // Print the word counts
+	maxLineLength   *bool   // Print the length of the longest line
+	totalMode       string  // When to print the total counts: auto, always, only or never
+)
+
+const (
+	help_text string = `
     Usage: wc [OPTION]... [FILE]...
        
     Print newline, word, and byte counts for each FILE, and a total line if
@@ -39,29 +49,50 @@ var (
     The options below may be used to select which counts are printed, always in
     the following order: newline, word, character, byte, maximum line length.
 
-        -help        display this help and exit
-        -version     output version information and exit
+        --help       display this help and exit
+        --version    output version information and exit
         
-        -c, -bytes
+        -c, --bytes
               print the byte counts
         
-        -m, -chars
+        -m, --chars
               print the character counts
         
-        -l, -lines
+        -l, --lines
               print the newline counts
               
-        -sloc
-              print the source lines of code
+        --sloc
+              print the lines of source code, of comments and blank lines,
+              in the language of each file's name
+              
+        --sloc-language=NAME
+              count the source lines of every file in the language NAME:
+              Go, C, C++, Java, JavaScript, TypeScript, Rust, Python, Shell,
+              YAML or Text
               
-        -o
+        --sloc-languages
+              print the number of files and their lines of source code, of
+              comments and blank lines for each language, looking into the
+              directories among the FILEs; with no FILE, count the current
+              directory
+              
+        -o STRING
               print the occurrences of a particular letter, word or phrase
               
-        -L, -max-line-length
+        -L, --max-line-length
               print the length of the longest line
               
-        -w, -words
+        -w, --words
               print the word counts
+
+        --files0-from=F
+              read input from the files specified by NUL-terminated names in file F; If F is - then read names from standard input
+
+        -j, --jobs=N
+              count up to N files at once, one per processor by default
+
+        --total=WHEN
+              when to print a line with total counts; WHEN can be: auto, always, only, never
 `
 	version_text = `
     wc (go-coreutils) 0.1
@@ -73,161 +104,501 @@ var (
 `
 )
 
-// printAndExit will print a message and then exit the program.
-func printAndExit(message string) {
-	fmt.Println(message)
-	os.Exit(0)
+// occurrenceCounter counts the number of occurrences of ref.
+func occurrenceCounter(buffer []byte, ref string) int {
+	return bytes.Count(buffer, []byte(ref))
 }
 
-// openFile will open the file and check for errors, then return the file
-func openFile(s string) *os.File {
-	fi, err := os.Open(s)
+// wordcount returns the number of words by splitting the buffer's fields.
+func wordCount(buffer []byte) int {
+	return len(strings.Fields(string(buffer)))
+}
+
+// Counts holds the statistics wc gathers for one input.
+type Counts struct {
+	Bytes         int
+	Characters    int
+	Lines         int
+	MaxLineLength int
+	Words         int
+	SLOC          sloc.Counts
+	Occurrences   int
+}
+
+// Counter gathers the Counts of its input.
+type Counter struct {
+	Occurrence string // the letter, word or phrase to count the occurrences of
+
+	// SLOC tells whether to count the source lines, which are in Language,
+	// or else in the language of the file's name, or plain text.
+	SLOC     bool
+	Language *sloc.Language
+
+	// SingleByte tells that the input is in a single-byte locale, such as
+	// the C locale, where each byte is a character and only the printable
+	// ASCII characters take up a column. Otherwise it is UTF-8.
+	SingleByte bool
+}
+
+// Count reads r to the end and returns its statistics.
+func Count(r io.Reader) (Counts, error) {
+	return new(Counter).Count(r)
+}
+
+// Count reads r to the end and returns its statistics.
+func (c *Counter) Count(r io.Reader) (Counts, error) {
+	return c.count(r, c.Language)
+}
+
+// count reads r, whose source lines are in lang, to the end and returns its
+// statistics.
+func (c *Counter) count(r io.Reader, lang *sloc.Language) (Counts, error) {
+	var counts Counts
+	var scanner *sloc.Scanner
+	if c.SLOC {
+		if lang == nil {
+			lang = sloc.Text
+		}
+		scanner = lang.NewScanner()
+	}
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			c.addLine(&counts, line)
+			if scanner != nil {
+				scanner.Line(line)
+			}
+		}
+		if err != nil {
+			if scanner != nil {
+				counts.SLOC = scanner.Counts
+			}
+			if err == io.EOF {
+				return counts, nil
+			}
+			return counts, err
+		}
+	}
+}
+
+// addLine adds the statistics of one line, including its newline, to counts.
+// As in GNU wc, a byte that is not UTF-8 is not a character, and the length
+// of a line is the number of columns it takes up on a terminal: a tab goes
+// to the next multiple of 8, wide characters take up two columns and
+// unprintable ones none, and a carriage return or a form feed starts the
+// line over.
+func (c *Counter) addLine(counts *Counts, line []byte) {
+	counts.Bytes += len(line)
+	counts.Words += wordCount(line)
+	if c.Occurrence != "" {
+		counts.Occurrences += occurrenceCounter(line, c.Occurrence)
+	}
+	column := 0
+	for len(line) > 0 {
+		r, n := rune(line[0]), 1
+		if !c.SingleByte {
+			r, n = utf8.DecodeRune(line)
+		}
+		line = line[n:]
+		valid := c.SingleByte || r != utf8.RuneError || n > 1
+		if valid {
+			counts.Characters++
+		}
+		switch {
+		case r == '\n':
+			counts.Lines++
+			column = 0
+		case r == '\r' || r == '\f':
+			column = 0
+		case r == '\t':
+			column += 8 - column%8
+		case !valid:
+		case c.SingleByte:
+			if ' ' <= r && r <= '~' {
+				column++
+			}
+		case quotearg.IsPrint(r):
+			column += width.Rune(r)
+		}
+		if counts.MaxLineLength < column {
+			counts.MaxLineLength = column
+		}
+	}
+}
+
+// result is what counting one file gave.
+type result struct {
+	counts  Counts
+	lang    *sloc.Language // the language of the source lines, or nil
+	err     error          // reading the file failed, after counting what was read
+	openErr error          // the file could not be opened
+}
+
+// countFile counts the named file.
+func (c *Counter) countFile(name string) result {
+	r := result{lang: c.Language}
+	if r.lang == nil {
+		r.lang = sloc.ForFile(name)
+	}
+	fi, err := os.Open(name)
 	if err != nil {
-		printAndExit("wc: " + s + ": No such file or directory")
+		r.openErr = err
+		return r
+	}
+	r.counts, r.err = c.count(fi, r.lang)
+	fi.Close()
+	return r
+}
+
+// Add adds the counts of another input to c, as for the total.
+func (c *Counts) Add(other Counts) {
+	c.Bytes += other.Bytes
+	c.Characters += other.Characters
+	c.Lines += other.Lines
+	if c.MaxLineLength < other.MaxLineLength {
+		c.MaxLineLength = other.MaxLineLength
 	}
-	return fi
+	c.Words += other.Words
+	c.SLOC.Add(other.SLOC)
+	c.Occurrences += other.Occurrences
 }
 
-// slocCounter counts the source lines of code
-func slocCounter(buffer []byte, count int) int {
-	// Returns true if the input line is not an empty.
-	isNotEmpty := func(buffer []byte) bool {
-		return !(len(buffer) < 1)
+// columns returns the statistics selected on the command line in the order
+// GNU wc prints them, followed by the ones it does not have.
+func columns(counts Counts) []int {
+	var values []int
+	if *countLines {
+		values = append(values, counts.Lines)
+	}
+	if *countWords {
+		values = append(values, counts.Words)
+	}
+	if *countCharacters {
+		values = append(values, counts.Characters)
+	}
+	if *countBytes {
+		values = append(values, counts.Bytes)
 	}
-	// Returns true if the input line is a line of code
-	isCode := func(line string) bool {
-		line = strings.Replace(line, "\t", "", -1)
-		line = strings.Replace(line, " ", "", -1)
-		prefix := strings.HasPrefix // make an alias for 'HasPrefix'
-		return prefix(line, "//") || prefix(line, " *") ||
-			prefix(line, "/*") || prefix(line, "*/") ||
-			prefix(line, "{") && len(line) == 1 ||
-			prefix(line, "}") && len(line) == 1 ||
-			prefix(line, ")") || prefix(line, "package") ||
-			prefix(line, "import") || prefix(line, "var (") ||
-			prefix(line, "const (")
+	if *maxLineLength {
+		values = append(values, counts.MaxLineLength)
 	}
-	if isNotEmpty(buffer) && !isCode(string(buffer)) {
-		count++
+	if *countSLOC {
+		values = append(values, counts.SLOC.Code, counts.SLOC.Comment, counts.SLOC.Blank)
 	}
-	return count
+	if len(*occurrenceRef) != 0 { // Count occurences if not empty.
+		values = append(values, counts.Occurrences)
+	}
+	return values
 }
 
-// occurrenceCounter counts the number of occurrences of occurrenceRef.
-func occurrenceCounter(buffer []byte) int {
-	return bytes.Count(buffer, []byte(*occurrenceRef))
+// printStats prints the statistics selected on the command line for the
+// named input, each right-aligned in width columns.
+func printStats(counts Counts, width int, name string) {
+	var line []string
+	for _, value := range columns(counts) {
+		line = append(line, fmt.Sprintf("%*d", width, value))
+	}
+	if name != "" {
+		line = append(line, name)
+	}
+	fmt.Println(strings.Join(line, " "))
 }
 
-// wordcount returns the number of words by splitting the buffer's fields.
-func wordCount(buffer []byte) int {
-	return len(strings.Fields(string(buffer)))
+/* numberWidth returns the width of the columns for the named inputs, which
+ * is that of the total size of the regular files among them, or at least 7
+ * if one of them is something else, like a pipe, whose size is not known
+ * beforehand. As in GNU wc, the counts of a single input are not padded
+ * when there is only one of them. */
+func numberWidth(names []string) int {
+	if len(names) == 0 || len(names) == 1 && len(columns(Counts{})) == 1 {
+		return 1
+	}
+	width, minimum := 1, 1
+	var total int64
+	for _, name := range names {
+		var fi os.FileInfo
+		var err error
+		if name == "-" {
+			fi, err = os.Stdin.Stat()
+		} else {
+			fi, err = os.Stat(name)
+		}
+		switch {
+		case err != nil:
+		case fi.Mode().IsRegular():
+			total += fi.Size()
+		default:
+			minimum = 7
+		}
+	}
+	for ; total >= 10; total /= 10 {
+		width++
+	}
+	if width < minimum {
+		width = minimum
+	}
+	return width
 }
 
-/* characterCount counts the number of characters in the bytes buffer by
- * sending a bytes slice of the buffer to the RuneCount function in utf8. This
- * gives support for non-ASCII characters that don't fit inside of a byte. */
-func characterCount(buffer []byte) int {
-	return utf8.RuneCount(buffer)
+/* sourceFiles returns the names of the files to count by language: the
+ * operands that are not directories, and the files in the directories and
+ * their subdirectories, other than those of version control systems. Only
+ * the files in one of the languages sloc knows are kept, unless all is
+ * true. */
+func sourceFiles(names []string, all bool, report *diag.Reporter) []string {
+	var files []string
+	for _, name := range names {
+		if fi, err := os.Stat(name); err != nil || !fi.IsDir() {
+			files = append(files, name)
+			continue
+		}
+		filepath.Walk(name, func(path string, fi os.FileInfo, err error) error {
+			switch {
+			case err != nil:
+				report.Error(path, err)
+			case fi.IsDir() && path != name && (fi.Name() == ".git" || fi.Name() == ".hg" || fi.Name() == ".svn"):
+				return filepath.SkipDir
+			case fi.Mode().IsRegular() && (all || sloc.ForFile(path) != nil):
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	return files
 }
 
-// wcstat stores statistics for each file processsed by wc
-type wcstat struct {
-	bytes      int
-	characters int
-	lines      int
-	maxLength  int
-	words      int
-	sloc       int
-	occurences int
-	fileName   string
+// languageStats are the counts of the files in one language.
+type languageStats struct {
+	lang  *sloc.Language
+	files int
+	sloc.Counts
 }
 
-// maxLineLength determines the maximum line length for the entire file.
-// NOTE: GNU wc counts by bytes rather than by runes, which is not accurate. It
-// also does not correctly detect tabs, giving a false line length size.
-func (wc *wcstat) maxLineLength(buffer []byte) {
-	length := utf8.RuneCount(buffer)
-	if wc.maxLength < length {
-		wc.maxLength = length
+// addLanguage adds the counts of a file to those of its language. Files in
+// no language are left out.
+func addLanguage(stats map[*sloc.Language]*languageStats, r result) {
+	if r.lang == nil {
+		return
+	}
+	if stats[r.lang] == nil {
+		stats[r.lang] = &languageStats{lang: r.lang}
 	}
+	stats[r.lang].files++
+	stats[r.lang].Add(r.counts.SLOC)
 }
 
-// processStats will process the input buffer and append the obtained stats
-// to the wcstat struct.
-func (wc *wcstat) getStats(buffer []byte) {
-	switch {
-	case *countBytes || *countBytesL:
-		wc.bytes += len(buffer) + 1
-	case *countCharacters || *countCharactersL:
-		wc.characters += characterCount(buffer) + 1
-	case *countLines || *countLinesL:
-		wc.lines++
-	case *maxLineLength || *maxLineLengthL:
-		wc.maxLineLength(buffer)
-	case *countWords || *countWordsL:
-		wc.words += wordCount(buffer)
-	case *countSLOC:
-		wc.sloc += slocCounter(buffer, 0)
-	case len(*occurrenceRef) != 0: // Count occurences if not empty.
-		wc.occurences += occurrenceCounter(buffer)
-	default: // Print all if no argument is given.
-		wc.lines++
-		wc.words += wordCount(buffer)
-		wc.bytes += len(buffer)
-	}
-}
-
-// printStats prints the statistics for the current file.
-func (wc *wcstat) printStats() {
+/* printLanguages prints the number of files and the lines of code, comment
+ * and blank lines of each language, those with the most code first, and
+ * their total as --total says. */
+func printLanguages(stats map[*sloc.Language]*languageStats) {
+	var rows []*languageStats
+	total := &languageStats{lang: &sloc.Language{Name: "total"}}
+	for _, row := range stats {
+		rows = append(rows, row)
+		total.files += row.files
+		total.Add(row.Counts)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Code != rows[j].Code {
+			return rows[i].Code > rows[j].Code
+		}
+		return rows[i].lang.Name < rows[j].lang.Name
+	})
 	switch {
-	case *countBytes || *countBytesL:
-		fmt.Println(wc.bytes, wc.fileName)
-	case *countCharacters || *countCharactersL:
-		fmt.Println(wc.characters, wc.fileName)
-	case *countLines || *countLinesL:
-		fmt.Println(wc.lines, wc.fileName)
-	case *maxLineLength || *maxLineLengthL:
-		fmt.Println(wc.maxLength, wc.fileName)
-	case *countWords || *countWordsL:
-		fmt.Println(wc.words, wc.fileName)
-	case *countSLOC:
-		fmt.Println(wc.sloc, wc.fileName)
-	case len(*occurrenceRef) != 0: // Count occurences if not empty.
-		fmt.Println(wc.occurences, wc.fileName)
-	default: // Print all if no argument is given.
-		fmt.Println(wc.lines, wc.words, wc.bytes+wc.lines, wc.fileName)
-	}
-}
-
-// scanFile scans each file, line by line, gathering statistics using a scanner.
-func (wc *wcstat) scanFile(scanner *bufio.Scanner) {
-	for scanner.Scan() {
-		wc.getStats(scanner.Bytes())
-	}
-}
-
-func main() {
-	if flag.NArg() == 0 || flag.Arg(0) == "-" {
-		var wc wcstat
-		wc.scanFile(bufio.NewScanner(os.Stdin))
-		wc.printStats()
+	case totalMode == "only":
+		total.lang.Name = ""
+		rows = []*languageStats{total}
+	case totalMode == "always" || totalMode == "auto" && len(rows) > 1:
+		rows = append(rows, total)
+	}
+
+	width := len(strconv.Itoa(total.files))
+	if w := len(strconv.Itoa(total.Code + total.Comment + total.Blank)); w > width {
+		width = w
+	}
+	for _, row := range rows {
+		line := fmt.Sprintf("%*d %*d %*d %*d", width, row.files, width, row.Code,
+			width, row.Comment, width, row.Blank)
+		if row.lang.Name != "" {
+			line += " " + row.lang.Name
+		}
+		fmt.Println(line)
+	}
+}
+
+// readNames reads the NUL-terminated file names given to --files0-from.
+func readNames(name string) ([]string, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
 	} else {
-		for file := 0; file < flag.NArg(); file++ {
-			wc := wcstat{fileName: flag.Arg(file)}
-			wc.scanFile(bufio.NewScanner(openFile(flag.Arg(file))))
-			wc.printStats()
+		data, err = os.ReadFile(name)
+	}
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	names := strings.Split(string(data), "\x00")
+	if names[len(names)-1] == "" {
+		names = names[:len(names)-1]
+	}
+	return names, nil
+}
+
+// utf8Locale reports whether the locale named by LC_ALL, LC_CTYPE or LANG,
+// the first of them that is set, has UTF-8 characters. Without any, the
+// locale is C, where every byte is a character.
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToLower(os.Getenv(name)); value != "" {
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
 		}
 	}
+	return false
+}
+
+// setTotal handles --total=WHEN.
+func setTotal(value string) error {
+	for _, when := range []string{"auto", "always", "only", "never"} {
+		if value == when {
+			totalMode = when
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid argument %s for '--total'\n"+
+		"Valid arguments are:\n  - 'auto'\n  - 'always'\n  - 'only'\n  - 'never'", diag.Quote(value))
 }
 
 func init() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+	applet.Register("wc", Main)
+}
+
+// Main runs wc with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("wc")
+	opts := getopt.New("wc")
+	countBytes = opts.Bool('c', "bytes")
+	countCharacters = opts.Bool('m', "chars")
+	countLines = opts.Bool('l', "lines")
+	countSLOC = opts.Bool(0, "sloc")
+	language := opts.String(0, "sloc-language", "")
+	byLanguage := opts.Bool(0, "sloc-languages")
+	occurrenceRef = opts.String('o', "", "")
+	countWords = opts.Bool('w', "words")
+	maxLineLength = opts.Bool('L', "max-line-length")
+	files0From := opts.String(0, "files0-from", "")
+	totalMode = "auto"
+	jobs := opts.Int('j', "jobs", parallel.DefaultJobs())
+	opts.Func(0, "total", getopt.RequiredArgument, setTotal)
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 	if *help {
-		printAndExit(help_text)
+		fmt.Print(help_text)
+		return 0
 	}
 	if *version {
-		printAndExit(version_text)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	if *jobs < 1 {
+		return report.Usagef("invalid number of jobs: %s", diag.Quote(strconv.Itoa(*jobs)))
+	}
+	counter := Counter{Occurrence: *occurrenceRef, SLOC: *countSLOC || *byLanguage, SingleByte: !utf8Locale()}
+	if *language != "" {
+		if counter.Language = sloc.ForName(*language); counter.Language == nil {
+			return report.Usagef("invalid language: %s", diag.Quote(*language))
+		}
+	}
+	if len(columns(Counts{})) == 0 { // Print the lines, words and bytes if no counter is given.
+		*countLines, *countWords, *countBytes = true, true, true
+	}
+
+	names := opts.Args()
+	unnamed := false // standard input is read without being named
+	width := 0
+	if *files0From != "" {
+		if opts.NArg() > 0 {
+			return report.Usagef("extra operand %s\nfile operands cannot be combined with --files0-from",
+				diag.Quote(opts.Arg(0)))
+		}
+		var err error
+		if names, err = readNames(*files0From); err != nil {
+			report.Errorf("cannot open %s for reading: %s", diag.Quote(*files0From), diag.Strerror(err))
+			return report.Status()
+		}
+		// As GNU wc does, only read ahead in a list that is in a file.
+		if fi, err := os.Stat(*files0From); *files0From == "-" || err != nil || !fi.Mode().IsRegular() {
+			width = 1
+		}
+	} else if len(names) == 0 {
+		names, unnamed = []string{"-"}, true
+	}
+	if *byLanguage {
+		if len(names) == 1 && unnamed {
+			names, unnamed = []string{"."}, false
+		}
+		names = sourceFiles(names, counter.Language != nil, report)
+	} else if width == 0 {
+		width = numberWidth(names)
+	}
+
+	var total Counts
+	stats := make(map[*sloc.Language]*languageStats)
+	results := make([]result, len(names))
+	// Files are counted in parallel, but standard input is read in turn,
+	// as it may be named more than once.
+	parallel.Do(len(names), *jobs, func(i int) {
+		if names[i] != "" && names[i] != "-" {
+			results[i] = counter.countFile(names[i])
+		}
+	}, func(i int) {
+		name, r := names[i], results[i]
+		switch {
+		case *files0From != "" && name == "":
+			report.Errorf("%s:%d: invalid zero-length file name", diag.QuoteName(*files0From), i+1)
+			return
+		case *files0From == "-" && name == "-":
+			report.Errorf("when reading file names from stdin, no file name of %s allowed", diag.Quote(name))
+			return
+		case name == "-":
+			r.counts, r.err = counter.Count(os.Stdin)
+			if r.lang = counter.Language; r.lang == nil {
+				r.lang = sloc.Text
+			}
+		case r.openErr != nil:
+			report.Error(name, r.openErr)
+			return
+		}
+		if r.err != nil {
+			report.Error(name, r.err)
+		}
+		if *byLanguage {
+			addLanguage(stats, r)
+			return
+		}
+		total.Add(r.counts)
+		if unnamed {
+			name = ""
+		}
+		if totalMode != "only" {
+			printStats(r.counts, width, name)
+		}
+	})
+	if *byLanguage {
+		printLanguages(stats)
+		return report.Status()
+	}
+	if totalMode == "always" || totalMode == "only" || totalMode == "auto" && len(names) > 1 {
+		name := "total"
+		if totalMode == "only" {
+			name = ""
+		}
+		printStats(total, width, name)
 	}
+	return report.Status()
 }
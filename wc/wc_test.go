@@ -0,0 +1,160 @@
+//
+// wc_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package wc
+
+import "bytes"
+import "fmt"
+import "os"
+import "path/filepath"
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+import "github.com/aisola/go-coreutils/internal/parallel"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "wc", []conformance.Case{
+		{Name: "lines", Args: []string{"-l", "a.txt"}},
+		{Name: "lines-prefix", Args: []string{"--l", "a.txt"}},
+		{Name: "words", Args: []string{"-w", "a.txt"}},
+		{Name: "bytes", Args: []string{"-c", "a.txt"}},
+		{Name: "chars", Args: []string{"-m", "utf8.txt"}, Env: []string{"LC_ALL=C.UTF-8"}},
+		{Name: "max-line-length", Args: []string{"-L", "a.txt"}},
+		{Name: "max-line-length-prefix", Args: []string{"--m", "a.txt"}},
+		{Name: "max-line-length-tabs", Args: []string{"-L", "tabs.txt"}},
+		{Name: "max-line-length-wide", Args: []string{"-L", "wide.txt"}, Env: []string{"LC_ALL=C.UTF-8"}},
+		{Name: "max-line-length-wide-stdin", Args: []string{"-L"}, Stdin: "日本\n", Env: []string{"LC_ALL=C.UTF-8"}},
+		{Name: "max-line-length-c-locale", Args: []string{"-L", "wide.txt"}},
+		{Name: "chars-c-locale", Args: []string{"-m", "utf8.txt"}},
+		{Name: "chars-invalid", Args: []string{"-m", "wide.txt"}, Env: []string{"LC_ALL=C.UTF-8"}},
+		{Name: "chars-lang", Args: []string{"-mL", "utf8.txt", "wide.txt"}, Env: []string{"LC_ALL=", "LANG=C.UTF-8"}},
+		{Name: "bytes-no-trailing-newline", Args: []string{"-c", "nonl.txt"}},
+		{Name: "lines-no-trailing-newline", Args: []string{"-l", "nonl.txt"}},
+		{Name: "empty", Args: []string{"-l", "empty.txt"}},
+		{Name: "default", Args: []string{"a.txt"}},
+		{Name: "default-stdin", Stdin: "a b\nc\n"},
+		{Name: "default-stdin-operand", Args: []string{"-"}, Stdin: "a b\nc\n"},
+		{Name: "lines-words", Args: []string{"-lw", "a.txt"}},
+		{Name: "words-lines", Args: []string{"-w", "-l", "a.txt"}},
+		{Name: "bytes-max-line-length", Args: []string{"-Lc", "a.txt"}},
+		{Name: "all-counters", Args: []string{"-lwmcL", "a.txt", "nonl.txt"}},
+		{Name: "files", Args: []string{"a.txt", "nonl.txt", "empty.txt"}},
+		{Name: "files-lines", Args: []string{"-l", "a.txt", "nonl.txt"}},
+		{Name: "files-and-stdin", Args: []string{"a.txt", "-"}, Stdin: "a b\nc\n"},
+		{Name: "files0-from", Args: []string{"--files0-from=names0"}},
+		{Name: "files0-from-stdin", Args: []string{"--files0-from=-"}, StdinFile: "names0"},
+		{Name: "files0-from-bad-names", Args: []string{"-l", "--files0-from", "bad-names0"}},
+		{Name: "files0-from-stdin-dash", Args: []string{"--files0-from=-"}, Stdin: "a.txt\x00-\x00"},
+		{Name: "files0-from-operand", Args: []string{"--files0-from=names0", "a.txt"}},
+		{Name: "files0-from-missing", Args: []string{"--files0-from=missing"}},
+		{Name: "stdin-lines", Args: []string{"-l"}, Stdin: "a\nb\n"},
+		{Name: "missing-file", Args: []string{"-l", "missing"}},
+		{Name: "missing-and-present", Args: []string{"-l", "missing", "a.txt"}},
+		{Name: "directory", Args: []string{"-l", "dir", "a.txt"}},
+		{Name: "invalid-option", Args: []string{"-Z"}},
+		{Name: "unrecognized-option", Args: []string{"--bogus"}},
+	})
+}
+
+// --total is newer than the GNU wc the golden files are recorded from, so
+// its cases are checked here.
+func TestTotal(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		stdout string
+	}{
+		{[]string{"--total=auto", "a.txt"}, " 4  6 29 a.txt\n"},
+		{[]string{"--total=always", "a.txt"}, " 4  6 29 a.txt\n 4  6 29 total\n"},
+		{[]string{"--total=always", "-l", "a.txt"}, "4 a.txt\n4 total\n"},
+		{[]string{"--total=only", "a.txt", "nonl.txt"}, " 5  9 48\n"},
+		{[]string{"--total=never", "-l", "a.txt", "nonl.txt"}, " 4 a.txt\n 1 nonl.txt\n"},
+	} {
+		got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: test.args})
+		if got.Status != 0 || string(got.Stdout) != test.stdout || len(got.Stderr) != 0 {
+			t.Errorf("wc %v: status %d, stdout %q, stderr %q; want 0, %q", test.args,
+				got.Status, got.Stdout, got.Stderr, test.stdout)
+		}
+	}
+	got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: []string{"--total=sometimes"}})
+	want := "wc: invalid argument 'sometimes' for '--total'\n" +
+		"Valid arguments are:\n  - 'auto'\n  - 'always'\n  - 'only'\n  - 'never'\n" +
+		"Try 'wc --help' for more information.\n"
+	if got.Status != 1 || string(got.Stderr) != want {
+		t.Errorf("wc --total=sometimes: status %d, stderr %q; want 1, %q", got.Status, got.Stderr, want)
+	}
+}
+
+// --sloc and --sloc-languages are not in GNU wc.
+func TestSLOC(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		stdout string
+	}{
+		{[]string{"--sloc", "src/main.go"}, " 4  1  1 src/main.go\n"},
+		{[]string{"-l", "--sloc", "src/main.go", "src/sub/lib.rs"},
+			"  6   4   1   1 src/main.go\n  4   1   3   0 src/sub/lib.rs\n 10   5   4   1 total\n"},
+		{[]string{"--sloc", "src/NOTES"}, " 1  0  0 src/NOTES\n"},
+		{[]string{"--sloc", "splice.c"}, "  2   4   1 splice.c\n"},
+		{[]string{"--sloc", "--sloc-language=go", "src/sub/run.sh"}, " 2  0  1 src/sub/run.sh\n"},
+		{[]string{"--sloc-languages", "src"}, " 1  4  1  1 Go\n 1  1  3  0 Rust\n 1  1  1  1 Shell\n 3  6  5  2 total\n"},
+		{[]string{"--sloc-languages", "--total=never", "src/sub"}, "1 1 3 0 Rust\n1 1 1 1 Shell\n"},
+		{[]string{"--sloc-languages", "--total=only", "src"}, " 3  6  5  2\n"},
+		{[]string{"--sloc-languages", "--sloc-language=text", "src/sub"}, "2 6 0 1 Text\n"},
+	} {
+		got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: test.args})
+		if got.Status != 0 || string(got.Stdout) != test.stdout || len(got.Stderr) != 0 {
+			t.Errorf("wc %v: status %d, stdout %q, stderr %q; want 0, %q", test.args,
+				got.Status, got.Stdout, got.Stderr, test.stdout)
+		}
+	}
+	got := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: []string{"--sloc-language=cobol"}})
+	want := "wc: invalid language: 'cobol'\nTry 'wc --help' for more information.\n"
+	if got.Status != 1 || string(got.Stderr) != want {
+		t.Errorf("wc --sloc-language=cobol: status %d, stderr %q; want 1, %q", got.Status, got.Stderr, want)
+	}
+}
+
+// -j is not in GNU wc either; counting in parallel must not change the
+// output.
+func TestJobs(t *testing.T) {
+	args := []string{"a.txt", "missing", "-", "nonl.txt", "dir", "-", "utf8.txt", "empty.txt"}
+	want := conformance.Exec(t, conformance.Setup(t), conformance.Case{Args: args, Stdin: "a b\n"})
+	for _, jobs := range []string{"1", "3", "16"} {
+		c := conformance.Case{Args: append([]string{"-j", jobs}, args...), Stdin: "a b\n"}
+		got := conformance.Exec(t, conformance.Setup(t), c)
+		if got.Status != want.Status || !bytes.Equal(got.Stdout, want.Stdout) || !bytes.Equal(got.Stderr, want.Stderr) {
+			t.Errorf("wc -j %s: got %d, %q, %q; want %d, %q, %q", jobs, got.Status, got.Stdout, got.Stderr,
+				want.Status, want.Stdout, want.Stderr)
+		}
+	}
+}
+
+// BenchmarkCountFiles counts 64 files of 1 MiB with up to 8 jobs. The
+// speedup depends on the number of processors.
+func BenchmarkCountFiles(b *testing.B) {
+	dir := b.TempDir()
+	line := []byte("the quick brown fox jumps over the lazy dog\n")
+	data := bytes.Repeat(line, (1<<20)/len(line))
+	names := make([]string, 64)
+	for i := range names {
+		names[i] = filepath.Join(dir, fmt.Sprint(i))
+		if err := os.WriteFile(names[i], data, 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	for _, jobs := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("jobs=%d", jobs), func(b *testing.B) {
+			b.SetBytes(int64(len(data) * len(names)))
+			var counter Counter
+			results := make([]result, len(names))
+			for n := 0; n < b.N; n++ {
+				parallel.Do(len(names), jobs, func(i int) {
+					results[i] = counter.countFile(names[i])
+				}, func(i int) {})
+			}
+		})
+	}
+}
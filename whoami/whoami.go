@@ -4,13 +4,15 @@
 //
 // Written By: Abram C. Isola
 //
-package main
+package whoami
 
-import "os"
 import "fmt"
-import "log"
+import "os"
 import "os/user"
-import "flag"
+
+import "github.com/aisola/go-coreutils/internal/applet"
+import "github.com/aisola/go-coreutils/internal/diag"
+import "github.com/aisola/go-coreutils/internal/getopt"
 
 const (
 	help_text string = `
@@ -31,26 +33,40 @@ const (
 `
 )
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("whoami", Main)
+}
+
+// Main runs whoami with the given arguments and returns its exit status.
+func Main(args []string) int {
+	report := diag.New("whoami")
+	opts := getopt.New("whoami")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
+	}
+
+	if opts.NArg() > 0 {
+		return report.Usagef("extra operand %s", diag.Quote(opts.Arg(0)))
 	}
 
 	current_user, err := user.Current()
 	if err != nil {
-		log.Fatalln(err)
+		report.Errorf("cannot find name for user ID %d", os.Geteuid())
+		return report.Status()
 	}
 
 	fmt.Println(current_user.Username)
-	os.Exit(0)
+	return 0
 }
@@ -4,12 +4,14 @@
 //
 // Written By: Trey Tacon, Abram C. Isola
 //
-package main
+package yes
 
 import (
-	"flag"
 	"fmt"
-	"os"
+
+	"github.com/aisola/go-coreutils/internal/applet"
+	"github.com/aisola/go-coreutils/internal/diag"
+	"github.com/aisola/go-coreutils/internal/getopt"
 )
 
 const (
@@ -32,27 +34,40 @@ const (
 `
 )
 
-func main() {
-	help := flag.Bool("help", false, help_text)
-	version := flag.Bool("version", false, version_text)
-	flag.Parse()
+func init() {
+	applet.Register("yes", Main)
+}
+
+// Main runs yes with the given arguments. It only returns on a usage error
+// or when printing help or version information.
+func Main(args []string) int {
+	report := diag.New("yes")
+	opts := getopt.New("yes")
+	help := opts.Bool(0, "help")
+	version := opts.Bool(0, "version")
+	if err := opts.Parse(args); err != nil {
+		return report.Usage(err)
+	}
 
 	if *help {
 		fmt.Println(help_text)
-		os.Exit(0)
+		return 0
 	}
 
 	if *version {
-		fmt.Println(version_text)
-		os.Exit(0)
+		fmt.Print(version_text)
+		return 0
 	}
 
-	var opts = flag.Args()
-	if len(opts) == 0 {
-		opts = []string{"y"}
+	var operands = opts.Args()
+	if len(operands) == 0 {
+		operands = []string{"y"}
 	}
 
 	for {
-		fmt.Println(opts[0])
+		if _, err := fmt.Println(operands[0]); err != nil {
+			report.Error("standard output", err)
+			return report.Status()
+		}
 	}
 }
@@ -0,0 +1,18 @@
+//
+// yes_test.go (go-coreutils) 0.1
+// Copyright (C) 2014, The GO-Coreutils Developers.
+//
+package yes
+
+import "testing"
+
+import "github.com/aisola/go-coreutils/internal/conformance"
+
+func TestMain(m *testing.M) { conformance.Main(m, Main) }
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, "yes", []conformance.Case{
+		{Name: "invalid-option", Args: []string{"-x"}},
+		{Name: "unrecognized-option", Args: []string{"--bogus"}},
+	})
+}